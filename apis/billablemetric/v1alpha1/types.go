@@ -73,16 +73,60 @@ type ObservedBillableMetric struct {
 	ArchivedAt      string            `json:"archivedAt,omitempty"`
 }
 
+// ReplacementPolicy determines what happens when the definition of a billable
+// metric changes, since Metronome doesn't allow billable metrics to be updated.
+type ReplacementPolicy string
+
+const (
+	// ReplacementPolicyNever refuses changes to the billable metric.
+	ReplacementPolicyNever ReplacementPolicy = "Never"
+	// ReplacementPolicyReplace creates a new billable metric with the changes,
+	// moves the Products that use the old one and follow its replacements to
+	// it, and archives the old one once no product uses it.
+	ReplacementPolicyReplace ReplacementPolicy = "Replace"
+)
+
+// Replacement configures how changes to a billable metric are applied.
+type Replacement struct {
+	// Policy for applying changes to the billable metric.
+	// +kubebuilder:validation:Enum=Never;Replace
+	// +kubebuilder:default=Never
+	// +optional
+	Policy ReplacementPolicy `json:"policy,omitempty"`
+
+	// StartingAt is when the Products that follow the replacements of the
+	// billable metric start using its replacement, as an RFC 3339 timestamp.
+	// Defaults to the time of the replacement.
+	// +optional
+	StartingAt string `json:"startingAt,omitempty"`
+}
+
 // BillableMetricSpec defines the desired state of a BillableMetric.
 type BillableMetricSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ForProvider       BillableMetricParameters `json:"forProvider"`
+
+	// Replacement configures how changes to the billable metric are applied.
+	// Changes are refused unless its policy is Replace.
+	// +optional
+	Replacement *Replacement `json:"replacement,omitempty"`
 }
 
 // BillableMetricStatus represents the observed state of a BillableMetric.
 type BillableMetricStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          ObservedBillableMetric `json:"atProvider,omitempty"`
+
+	// ReplacedIDs are the IDs of the billable metrics this one replaced that
+	// are yet to be archived.
+	// +optional
+	ReplacedIDs []string `json:"replacedIds,omitempty"`
+
+	// BlockingProductIDs are the IDs of the products that still use a
+	// replaced billable metric, which keep it from being archived. Archiving
+	// is retried once one of them stops using it.
+	// +optional
+	BlockingProductIDs []string `json:"blockingProductIds,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -129,6 +129,11 @@ func (in *BillableMetricSpec) DeepCopyInto(out *BillableMetricSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.Replacement != nil {
+		in, out := &in.Replacement, &out.Replacement
+		*out = new(Replacement)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BillableMetricSpec.
@@ -146,6 +151,16 @@ func (in *BillableMetricStatus) DeepCopyInto(out *BillableMetricStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.ReplacedIDs != nil {
+		in, out := &in.ReplacedIDs, &out.ReplacedIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlockingProductIDs != nil {
+		in, out := &in.BlockingProductIDs, &out.BlockingProductIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BillableMetricStatus.
@@ -253,3 +268,18 @@ func (in *PropertyFilter) DeepCopy() *PropertyFilter {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Replacement) DeepCopyInto(out *Replacement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Replacement.
+func (in *Replacement) DeepCopy() *Replacement {
+	if in == nil {
+		return nil
+	}
+	out := new(Replacement)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	customerv1alpha1 "github.com/redbackthomson/provider-metronome/apis/customer/v1alpha1"
+	productv1alpha1 "github.com/redbackthomson/provider-metronome/apis/product/v1alpha1"
+	ratecardv1alpha1 "github.com/redbackthomson/provider-metronome/apis/ratecard/v1alpha1"
+)
+
+// ResolveReferences of this Contract
+func (co *Contract) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, co)
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	// Resolve spec.forProvider.CustomerID
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: co.Spec.ForProvider.CustomerID,
+		Reference:    co.Spec.ForProvider.CustomerRef,
+		Selector:     co.Spec.ForProvider.CustomerSelector,
+		To:           reference.To{Managed: &customerv1alpha1.Customer{}, List: &customerv1alpha1.CustomerList{}},
+		Extract:      CustomerID(),
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "Spec.ForProvider.CustomerID")
+	}
+
+	if rsp.ResolvedValue == "" {
+		return errors.New("Spec.ForProvider.CustomerID not yet resolvable")
+	}
+
+	co.Spec.ForProvider.CustomerID = rsp.ResolvedValue
+	co.Spec.ForProvider.CustomerRef = rsp.ResolvedReference
+
+	// Resolve spec.forProvider.RateCardID
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: co.Spec.ForProvider.RateCardID,
+		Reference:    co.Spec.ForProvider.RateCardRef,
+		Selector:     co.Spec.ForProvider.RateCardSelector,
+		To:           reference.To{Managed: &ratecardv1alpha1.RateCard{}, List: &ratecardv1alpha1.RateCardList{}},
+		Extract:      RateCardID(),
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "Spec.ForProvider.RateCardID")
+	}
+
+	if rsp.ResolvedValue == "" {
+		return errors.New("Spec.ForProvider.RateCardID not yet resolvable")
+	}
+
+	co.Spec.ForProvider.RateCardID = rsp.ResolvedValue
+	co.Spec.ForProvider.RateCardRef = rsp.ResolvedReference
+
+	// Resolve spec.forProvider.commits[*].ProductID
+	for i := range co.Spec.ForProvider.Commits {
+		commit := &co.Spec.ForProvider.Commits[i]
+		path := fmt.Sprintf("Spec.ForProvider.Commits[%d].ProductID", i)
+
+		rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+			CurrentValue: commit.ProductID,
+			Reference:    commit.ProductRef,
+			To:           reference.To{Managed: &productv1alpha1.Product{}, List: &productv1alpha1.ProductList{}},
+			Extract:      ProductID(),
+		})
+
+		if err != nil {
+			return errors.Wrap(err, path)
+		}
+
+		if rsp.ResolvedValue == "" {
+			return errors.Errorf("%s not yet resolvable", path)
+		}
+
+		commit.ProductID = rsp.ResolvedValue
+		commit.ProductRef = rsp.ResolvedReference
+	}
+
+	// Resolve spec.forProvider.overrides[*].ProductID
+	for i := range co.Spec.ForProvider.Overrides {
+		override := &co.Spec.ForProvider.Overrides[i]
+		path := fmt.Sprintf("Spec.ForProvider.Overrides[%d].ProductID", i)
+
+		rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+			CurrentValue: override.ProductID,
+			Reference:    override.ProductRef,
+			To:           reference.To{Managed: &productv1alpha1.Product{}, List: &productv1alpha1.ProductList{}},
+			Extract:      ProductID(),
+		})
+
+		if err != nil {
+			return errors.Wrap(err, path)
+		}
+
+		if rsp.ResolvedValue == "" {
+			return errors.Errorf("%s not yet resolvable", path)
+		}
+
+		override.ProductID = rsp.ResolvedValue
+		override.ProductRef = rsp.ResolvedReference
+	}
+
+	return nil
+}
+
+// CustomerID extracts info from a kubernetes referenced object
+func CustomerID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, _ := mg.(*customerv1alpha1.Customer)
+		return cr.Status.AtProvider.ID
+	}
+}
+
+// RateCardID extracts info from a kubernetes referenced object
+func RateCardID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, _ := mg.(*ratecardv1alpha1.RateCard)
+		return cr.Status.AtProvider.ID
+	}
+}
+
+// ProductID extracts info from a kubernetes referenced object
+func ProductID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, _ := mg.(*productv1alpha1.Product)
+		return cr.Status.AtProvider.ID
+	}
+}
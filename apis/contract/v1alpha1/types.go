@@ -0,0 +1,211 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	ratev1alpha1 "github.com/redbackthomson/provider-metronome/apis/rate/v1alpha1"
+)
+
+type ScheduleItem struct {
+	Amount ratev1alpha1.Decimal `json:"amount"`
+	// StartingAt is an RFC 3339 timestamp on an hour boundary.
+	StartingAt string `json:"startingAt"`
+	// EndingBefore is an RFC 3339 timestamp on an hour boundary.
+	EndingBefore string `json:"endingBefore"`
+}
+
+// AccessSchedule is the schedule of amounts a commit makes available.
+type AccessSchedule struct {
+	// CreditTypeID defaults to the fiat credit type of the rate card.
+	// +optional
+	CreditTypeID  string         `json:"creditTypeId,omitempty"`
+	ScheduleItems []ScheduleItem `json:"scheduleItems"`
+}
+
+type OverwriteRate struct {
+	// +kubebuilder:validation:Enum=FLAT;PERCENTAGE;SUBSCRIPTION;TIERED;CUSTOM
+	RateType string               `json:"rateType"`
+	Price    ratev1alpha1.Decimal `json:"price,omitempty"`
+}
+
+// Commit is an amount the customer commits to spend on the contract. Commits
+// are matched to those of the contract by type, product and name; commits
+// missing from the contract are added by amending it, while changes to the
+// other fields of an existing commit are not applied.
+type Commit struct {
+	// +kubebuilder:validation:Enum=PREPAID;POSTPAID
+	Type string `json:"type"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// +optional
+	ProductID string `json:"productId,omitempty"`
+
+	// +optional
+	ProductRef *xpv1.Reference `json:"productRef,omitempty"`
+
+	AccessSchedule *AccessSchedule `json:"accessSchedule,omitempty"`
+	// +optional
+	ApplicableProductIDs []string `json:"applicableProductIds,omitempty"`
+	// +optional
+	RolloverFraction *float64 `json:"rolloverFraction,omitempty"`
+}
+
+// Override changes the rates of a product for the customer. Overrides are
+// matched to those of the contract by product, type and start time; overrides
+// missing from the contract are added by amending it.
+type Override struct {
+	// +optional
+	ProductID string `json:"productId,omitempty"`
+
+	// +optional
+	ProductRef *xpv1.Reference `json:"productRef,omitempty"`
+
+	// StartingAt is an RFC 3339 timestamp on an hour boundary.
+	StartingAt string `json:"startingAt"`
+	// EndingBefore is an RFC 3339 timestamp on an hour boundary.
+	// +optional
+	EndingBefore string `json:"endingBefore,omitempty"`
+	// +kubebuilder:validation:Enum=MULTIPLIER;OVERWRITE
+	Type string `json:"type"`
+	// Multiplier applied to the rates of MULTIPLIER overrides.
+	// +optional
+	Multiplier *float64 `json:"multiplier,omitempty"`
+	// OverwriteRate replaces the rates of OVERWRITE overrides.
+	// +optional
+	OverwriteRate *OverwriteRate `json:"overwriteRate,omitempty"`
+}
+
+// ContractParameters represents the request payload for creating a contract.
+type ContractParameters struct {
+	// +optional
+	CustomerID string `json:"customerId,omitempty"`
+
+	// +optional
+	CustomerRef *xpv1.Reference `json:"customerRef,omitempty"`
+
+	// +optional
+	CustomerSelector *xpv1.Selector `json:"customerSelector,omitempty"`
+
+	// +optional
+	RateCardID string `json:"rateCardId,omitempty"`
+
+	// +optional
+	RateCardRef *xpv1.Reference `json:"rateCardRef,omitempty"`
+
+	// +optional
+	RateCardSelector *xpv1.Selector `json:"rateCardSelector,omitempty"`
+
+	// +optional
+	Name string `json:"name,omitempty"`
+	// StartingAt is an RFC 3339 timestamp on an hour boundary.
+	StartingAt string `json:"startingAt"`
+	// EndingBefore is an RFC 3339 timestamp on an hour boundary. Changing it
+	// updates the end date of the contract.
+	// +optional
+	EndingBefore string `json:"endingBefore,omitempty"`
+	// +optional
+	NetPaymentTermsDays *int              `json:"netPaymentTermsDays,omitempty"`
+	CustomFields        map[string]string `json:"customFields,omitempty"`
+	Commits             []Commit          `json:"commits,omitempty"`
+	Overrides           []Override        `json:"overrides,omitempty"`
+}
+
+type ProductIdentifier struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ObservedCommit struct {
+	ID                   string            `json:"id"`
+	Type                 string            `json:"type"`
+	Name                 string            `json:"name,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Product              ProductIdentifier `json:"product"`
+	AccessSchedule       *AccessSchedule   `json:"accessSchedule,omitempty"`
+	RolloverFraction     float64           `json:"rolloverFraction,omitempty"`
+	ApplicableProductIDs []string          `json:"applicableProductIds,omitempty"`
+}
+
+type ObservedOverride struct {
+	ID            string            `json:"id"`
+	Product       ProductIdentifier `json:"product"`
+	StartingAt    string            `json:"startingAt"`
+	EndingBefore  string            `json:"endingBefore,omitempty"`
+	Type          string            `json:"type"`
+	Multiplier    float64           `json:"multiplier,omitempty"`
+	OverwriteRate *OverwriteRate    `json:"overwriteRate,omitempty"`
+}
+
+// ObservedContract represents the data structure of a contract.
+type ObservedContract struct {
+	ID                  string             `json:"id"`
+	CustomerID          string             `json:"customerId"`
+	RateCardID          string             `json:"rateCardId"`
+	Name                string             `json:"name,omitempty"`
+	StartingAt          string             `json:"startingAt"`
+	EndingBefore        string             `json:"endingBefore,omitempty"`
+	NetPaymentTermsDays int                `json:"netPaymentTermsDays,omitempty"`
+	CreatedAt           string             `json:"createdAt"`
+	CreatedBy           string             `json:"createdBy,omitempty"`
+	CustomFields        map[string]string  `json:"customFields,omitempty"`
+	Commits             []ObservedCommit   `json:"commits,omitempty"`
+	Overrides           []ObservedOverride `json:"overrides,omitempty"`
+}
+
+// ContractSpec defines the desired state of a Contract.
+type ContractSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ContractParameters `json:"forProvider"`
+}
+
+// ContractStatus represents the observed state of a Contract.
+type ContractStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ObservedContract `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Contract represents a Metronome Contract resource
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,metronome}
+type Contract struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ContractSpec   `json:"spec"`
+	Status ContractStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ContractList contains a list of Contract
+type ContractList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Contract `json:"items"`
+}
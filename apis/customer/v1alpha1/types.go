@@ -0,0 +1,97 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// BillingProviderConfiguration configures how the invoices of a customer are
+// delivered to a billing provider.
+type BillingProviderConfiguration struct {
+	// +kubebuilder:validation:Enum=aws_marketplace;azure_marketplace;gcp_marketplace;stripe;netsuite;quickbooks_online;workday;custom
+	BillingProvider string `json:"billingProvider"`
+	// +kubebuilder:validation:Enum=direct_to_billing_provider;aws_sqs;tackle;aws_sns
+	DeliveryMethod string               `json:"deliveryMethod"`
+	Configuration  BillingConfiguration `json:"configuration,omitempty"`
+}
+
+type BillingConfiguration struct {
+	StripeCustomerID string `json:"stripeCustomerId,omitempty"`
+	// +kubebuilder:validation:Enum=charge_automatically;send_invoice
+	StripeCollectionMethod string `json:"stripeCollectionMethod,omitempty"`
+}
+
+// CustomerParameters represents the request payload for creating a customer.
+type CustomerParameters struct {
+	Name string `json:"name"`
+	// IngestAliases are the identifiers, such as email addresses, used to
+	// attribute usage events to the customer.
+	IngestAliases                 []string                       `json:"ingestAliases,omitempty"`
+	CustomFields                  map[string]string              `json:"customFields,omitempty"`
+	BillingProviderConfigurations []BillingProviderConfiguration `json:"billingProviderConfigurations,omitempty"`
+}
+
+// ObservedCustomer represents the data structure of a customer.
+type ObservedCustomer struct {
+	ID                            string                         `json:"id"`
+	ExternalID                    string                         `json:"externalId,omitempty"`
+	Name                          string                         `json:"name"`
+	IngestAliases                 []string                       `json:"ingestAliases,omitempty"`
+	CustomFields                  map[string]string              `json:"customFields,omitempty"`
+	SalesforceAccountID           string                         `json:"salesforceAccountId,omitempty"`
+	BillingProviderConfigurations []BillingProviderConfiguration `json:"billingProviderConfigurations,omitempty"`
+}
+
+// CustomerSpec defines the desired state of a Customer.
+type CustomerSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       CustomerParameters `json:"forProvider"`
+}
+
+// CustomerStatus represents the observed state of a Customer.
+type CustomerStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ObservedCustomer `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Customer represents a Metronome Customer resource
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,metronome}
+type Customer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CustomerSpec   `json:"spec"`
+	Status CustomerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CustomerList contains a list of Customer
+type CustomerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Customer `json:"items"`
+}
@@ -46,6 +46,11 @@ type CustomFieldKeySpec struct {
 type CustomFieldKeyStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          ObservedCustomFieldKey `json:"atProvider,omitempty"`
+	// RemovedValues are the values of the key, keyed by object ID, saved
+	// before the key was removed to change whether it enforces uniqueness.
+	// They are set again once the key has been added back.
+	// +optional
+	RemovedValues map[string]string `json:"removedValues,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -120,6 +120,13 @@ func (in *CustomFieldKeyStatus) DeepCopyInto(out *CustomFieldKeyStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	out.AtProvider = in.AtProvider
+	if in.RemovedValues != nil {
+		in, out := &in.RemovedValues, &out.RemovedValues
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomFieldKeyStatus.
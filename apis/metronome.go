@@ -21,6 +21,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	billablemetricv1alpha1 "github.com/redbackthomson/provider-metronome/apis/billablemetric/v1alpha1"
+	contractv1alpha1 "github.com/redbackthomson/provider-metronome/apis/contract/v1alpha1"
+	customerv1alpha1 "github.com/redbackthomson/provider-metronome/apis/customer/v1alpha1"
 	customfieldkeyv1alpha1 "github.com/redbackthomson/provider-metronome/apis/customfieldkey/v1alpha1"
 	productv1alpha1 "github.com/redbackthomson/provider-metronome/apis/product/v1alpha1"
 	ratev1alpha1 "github.com/redbackthomson/provider-metronome/apis/rate/v1alpha1"
@@ -33,6 +35,8 @@ func init() {
 	AddToSchemes = append(AddToSchemes,
 		metronomev1alpha1.SchemeBuilder.AddToScheme,
 		billablemetricv1alpha1.SchemeBuilder.AddToScheme,
+		contractv1alpha1.SchemeBuilder.AddToScheme,
+		customerv1alpha1.SchemeBuilder.AddToScheme,
 		customfieldkeyv1alpha1.SchemeBuilder.AddToScheme,
 		productv1alpha1.SchemeBuilder.AddToScheme,
 		ratecardv1alpha1.SchemeBuilder.AddToScheme,
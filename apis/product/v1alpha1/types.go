@@ -22,6 +22,12 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
+// AnnotationKeyFollowReplacements is the annotation of a Product that, when
+// set to "true", lets a BillableMetric with the Replace policy change the
+// spec of the Product to use its replacement. Other Products that use a
+// replaced billable metric are reported on the BillableMetric instead.
+const AnnotationKeyFollowReplacements = "metronome.crossplane.io/follow-billable-metric-replacements"
+
 type QuantityConversion struct {
 	ConversionFactor float64 `json:"conversionFactor"`
 	Operation        string  `json:"operation"`
@@ -33,6 +39,19 @@ type QuantityRounding struct {
 	RoundingMethod string  `json:"roundingMethod"`
 }
 
+// UpdatePolicy determines when updates to a product start.
+type UpdatePolicy string
+
+const (
+	// UpdatePolicyImmediate starts updates at the next hour boundary.
+	UpdatePolicyImmediate UpdatePolicy = "Immediate"
+	// UpdatePolicyNextBillingPeriod starts updates at the start of the next
+	// calendar month in UTC, when monthly billing periods start.
+	UpdatePolicyNextBillingPeriod UpdatePolicy = "NextBillingPeriod"
+	// UpdatePolicyExplicit starts updates at spec.forProvider.startingAt.
+	UpdatePolicyExplicit UpdatePolicy = "Explicit"
+)
+
 // ProductParameters represents the request payload for creating a product.
 type ProductParameters struct {
 	// +optional
@@ -54,6 +73,42 @@ type ProductParameters struct {
 	QuantityRounding     *QuantityRounding   `json:"quantityRounding,omitempty"`
 	Tags                 []string            `json:"tags,omitempty"`
 	StartingAt           string              `json:"startingAt,omitempty"`
+
+	// CustomFields of the product. Values of keys that aren't listed are
+	// removed from the product.
+	// +optional
+	CustomFields map[string]string `json:"customFields,omitempty"`
+
+	// UpdatePolicy determines when updates to the product start. Defaults to
+	// Explicit if startingAt is set, and Immediate otherwise.
+	// +kubebuilder:validation:Enum=Immediate;NextBillingPeriod;Explicit
+	// +optional
+	UpdatePolicy UpdatePolicy `json:"updatePolicy,omitempty"`
+
+	// Schedule of changes to the product planned ahead of time. Changes that
+	// haven't started yet are submitted to Metronome as product updates, and
+	// are part of the desired state of the product once they start.
+	// +optional
+	Schedule []ProductScheduleEntry `json:"schedule,omitempty"`
+}
+
+// ProductScheduleEntry is a change to a product that starts at a given time.
+// Only the fields that are set are changed.
+type ProductScheduleEntry struct {
+	// StartingAt is when the change starts, as an RFC 3339 timestamp.
+	StartingAt string `json:"startingAt"`
+
+	// +optional
+	BillableMetricID     string              `json:"billableMetricId,omitempty"`
+	Name                 string              `json:"name,omitempty"`
+	CompositeProductIDs  []string            `json:"compositeProductIds,omitempty"`
+	CompositeTags        []string            `json:"compositeTags,omitempty"`
+	ExcludeFreeUsage     bool                `json:"excludeFreeUsage,omitempty"`
+	PresentationGroupKey []string            `json:"presentationGroupKey,omitempty"`
+	PricingGroupKey      []string            `json:"pricingGroupKey,omitempty"`
+	QuantityConversion   *QuantityConversion `json:"quantityConversion,omitempty"`
+	QuantityRounding     *QuantityRounding   `json:"quantityRounding,omitempty"`
+	Tags                 []string            `json:"tags,omitempty"`
 }
 
 type ProductDetails struct {
@@ -81,6 +136,10 @@ type ObservedProduct struct {
 	Updates      []ProductDetails  `json:"updates"`
 	CustomFields map[string]string `json:"customFields,omitempty"`
 	ArchivedAt   string            `json:"archivedAt,omitempty"`
+
+	// UpdateStartingAt is when the last update made to the product to match
+	// its spec starts.
+	UpdateStartingAt string `json:"updateStartingAt,omitempty"`
 }
 
 // ProductSpec defines the desired state of a Product.
@@ -213,6 +213,20 @@ func (in *ProductParameters) DeepCopyInto(out *ProductParameters) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CustomFields != nil {
+		in, out := &in.CustomFields, &out.CustomFields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = make([]ProductScheduleEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProductParameters.
@@ -225,6 +239,56 @@ func (in *ProductParameters) DeepCopy() *ProductParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProductScheduleEntry) DeepCopyInto(out *ProductScheduleEntry) {
+	*out = *in
+	if in.CompositeProductIDs != nil {
+		in, out := &in.CompositeProductIDs, &out.CompositeProductIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompositeTags != nil {
+		in, out := &in.CompositeTags, &out.CompositeTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PresentationGroupKey != nil {
+		in, out := &in.PresentationGroupKey, &out.PresentationGroupKey
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PricingGroupKey != nil {
+		in, out := &in.PricingGroupKey, &out.PricingGroupKey
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.QuantityConversion != nil {
+		in, out := &in.QuantityConversion, &out.QuantityConversion
+		*out = new(QuantityConversion)
+		**out = **in
+	}
+	if in.QuantityRounding != nil {
+		in, out := &in.QuantityRounding, &out.QuantityRounding
+		*out = new(QuantityRounding)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProductScheduleEntry.
+func (in *ProductScheduleEntry) DeepCopy() *ProductScheduleEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ProductScheduleEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProductSpec) DeepCopyInto(out *ProductSpec) {
 	*out = *in
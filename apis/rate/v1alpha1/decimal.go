@@ -0,0 +1,93 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+// maxExponentDigits is the most digits the exponent of a decimal may have, so
+// that a decimal can't be a number too large to work with.
+const maxExponentDigits = 2
+
+// Decimal is an exact decimal number, such as a price, written as a string,
+// e.g. "0.0001". Two decimals are equal if they are the same number, however
+// they are written. The exponent, if any, has at most two digits.
+// +kubebuilder:validation:Type=string
+// +kubebuilder:validation:Pattern=`^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]{1,2})?$`
+type Decimal string
+
+// UnmarshalJSON reads a decimal from a string, or from a JSON number as
+// prices were written before they were decimals.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] != '"' {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+		*d = Decimal(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*d = Decimal(s)
+	return nil
+}
+
+// Rat returns the number the decimal is, or false if it isn't a number or its
+// exponent is too long. An empty decimal is zero.
+func (d Decimal) Rat() (*big.Rat, bool) {
+	if d == "" {
+		return new(big.Rat), true
+	}
+	if i := strings.IndexAny(string(d), "eE"); i >= 0 {
+		if len(strings.TrimLeft(string(d[i+1:]), "+-")) > maxExponentDigits {
+			return nil, false
+		}
+	}
+	return new(big.Rat).SetString(string(d))
+}
+
+// Cmp compares the numbers two decimals are, returning -1, 0 or +1. Decimals
+// that aren't numbers are compared as strings.
+func (d Decimal) Cmp(o Decimal) int {
+	a, aok := d.Rat()
+	b, bok := o.Rat()
+	if !aok || !bok {
+		switch {
+		case d < o:
+			return -1
+		case d > o:
+			return 1
+		}
+		return 0
+	}
+	return a.Cmp(b)
+}
+
+// Equal reports whether two decimals are the same number.
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
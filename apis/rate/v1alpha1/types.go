@@ -23,16 +23,27 @@ import (
 )
 
 type Tier struct {
-	Price float64 `json:"price"`
+	Price Decimal `json:"price"`
 	Size  float64 `json:"size,omitempty"`
 }
 
 type CommitRate struct {
 	RateType string  `json:"rateType"`
-	Price    float64 `json:"price,omitempty"`
+	Price    Decimal `json:"price,omitempty"`
 	Tiers    []Tier  `json:"tiers,omitempty"`
 }
 
+// AnnotationKeyChangeAt is the annotation of a Rate that sets when a change to
+// its spec takes effect, as an RFC 3339 timestamp on an hour boundary. Changes
+// take effect at the next hour boundary if it isn't set.
+const AnnotationKeyChangeAt = "metronome.crossplane.io/change-at"
+
+// AnnotationKeyEndAt is the annotation of a Rate that sets when the rate ends
+// once the Rate is deleted, as an RFC 3339 timestamp on an hour boundary. The
+// rate ends at the next hour boundary if it isn't set, and the Rate is kept
+// until it has ended.
+const AnnotationKeyEndAt = "metronome.crossplane.io/end-at"
+
 // RateParameters represents the request payload for creating a rate card.
 type RateParameters struct {
 	// +optional
@@ -59,8 +70,8 @@ type RateParameters struct {
 
 	// Price is the default price. For FLAT and SUBSCRIPTION rateType, this
 	// must be >=0 and the unit is **CENTS**. For PERCENTAGE rateType, this is
-	// a decimal fraction, e.g. use 0.1 for 10%; this must be >=0 and <=1.
-	Price              float64           `json:"price,omitempty"`
+	// a decimal fraction, e.g. use "0.1" for 10%; this must be >=0 and <=1.
+	Price              Decimal           `json:"price,omitempty"`
 	PricingGroupValues map[string]string `json:"pricingGroupValues,omitempty"`
 	CommitRate         *CommitRate       `json:"commitRate,omitempty"`
 	CreditTypeID       string            `json:"creditTypeId,omitempty"`
@@ -80,13 +91,22 @@ type RateDetails struct {
 	RateType           string            `json:"rateType"`
 	CreditType         CreditType        `json:"creditType,omitempty"`
 	IsProrated         bool              `json:"isProrated,omitempty"`
-	Price              float64           `json:"price,omitempty"`
+	Price              Decimal           `json:"price,omitempty"`
 	PricingGroupValues map[string]string `json:"pricingGroupValues,omitempty"`
 	Quantity           float64           `json:"quantity,omitempty"`
 	Tiers              []Tier            `json:"tiers,omitempty"`
 	UseListPrices      bool              `json:"useListPrices,omitempty"`
 }
 
+// SupersededRate is an earlier version of a rate, which was ended when the
+// spec of the rate changed.
+type SupersededRate struct {
+	StartingAt   string      `json:"startingAt"`
+	EndingBefore string      `json:"endingBefore"`
+	Details      RateDetails `json:"rate"`
+	CommitRate   *CommitRate `json:"commitRate,omitempty"`
+}
+
 // ObservedRate represents the data structure of a rate card.
 type ObservedRate struct {
 	Entitled           bool              `json:"entitled"`
@@ -99,6 +119,8 @@ type ObservedRate struct {
 	CommitRate         CommitRate        `json:"commitRate,omitempty"`
 	EndingBefore       string            `json:"endingBefore,omitempty"`
 	PricingGroupValues map[string]string `json:"pricingGroupValues,omitempty"`
+	// Superseded are the earlier versions of the rate, oldest first.
+	Superseded []SupersededRate `json:"superseded,omitempty"`
 }
 
 // RateSpec defines the desired state of a Rate.
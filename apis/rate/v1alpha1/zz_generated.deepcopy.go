@@ -84,6 +84,13 @@ func (in *ObservedRate) DeepCopyInto(out *ObservedRate) {
 			(*out)[key] = val
 		}
 	}
+	if in.Superseded != nil {
+		in, out := &in.Superseded, &out.Superseded
+		*out = make([]SupersededRate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedRate.
@@ -269,6 +276,27 @@ func (in *RateStatus) DeepCopy() *RateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupersededRate) DeepCopyInto(out *SupersededRate) {
+	*out = *in
+	in.Details.DeepCopyInto(&out.Details)
+	if in.CommitRate != nil {
+		in, out := &in.CommitRate, &out.CommitRate
+		*out = new(CommitRate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupersededRate.
+func (in *SupersededRate) DeepCopy() *SupersededRate {
+	if in == nil {
+		return nil
+	}
+	out := new(SupersededRate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tier) DeepCopyInto(out *Tier) {
 	*out = *in
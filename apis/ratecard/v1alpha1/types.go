@@ -38,9 +38,11 @@ type FiatCreditType struct {
 
 // RateCardParameters represents the request payload for creating a rate card.
 type RateCardParameters struct {
-	Name                  string                 `json:"name"`
-	Description           string                 `json:"description,omitempty"`
-	FiatCreditTypeID      string                 `json:"fiatCreditTypeId,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// FiatCreditTypeID can't be changed after the rate card is created.
+	FiatCreditTypeID string `json:"fiatCreditTypeId,omitempty"`
+	// CreditTypeConversions can't be changed after the rate card is created.
 	CreditTypeConversions []CreditTypeConversion `json:"creditTypeConversions,omitempty"`
 	Aliases               []RateCardAlias        `json:"aliases,omitempty"`
 	CustomFields          map[string]string      `json:"customFields,omitempty"`
@@ -56,6 +58,11 @@ type ObservedRateCard struct {
 	CreatedBy      string            `json:"createdBy"`
 	Aliases        []RateCardAlias   `json:"aliases,omitempty"`
 	CustomFields   map[string]string `json:"customFields,omitempty"`
+
+	// CreditTypeConversions the rate card was created with, since Metronome
+	// doesn't return them. Unset if they aren't known.
+	// +optional
+	CreditTypeConversions []CreditTypeConversion `json:"creditTypeConversions"`
 }
 
 // RateCardSpec defines the desired state of a RateCard.
@@ -70,6 +70,11 @@ func (in *ObservedRateCard) DeepCopyInto(out *ObservedRateCard) {
 			(*out)[key] = val
 		}
 	}
+	if in.CreditTypeConversions != nil {
+		in, out := &in.CreditTypeConversions, &out.CreditTypeConversions
+		*out = make([]CreditTypeConversion, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedRateCard.
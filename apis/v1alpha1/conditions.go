@@ -0,0 +1,133 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// TypeImmutableFieldsChanged indicates whether the desired state of a managed
+// resource changes fields that can't be updated in Metronome.
+const TypeImmutableFieldsChanged xpv1.ConditionType = "ImmutableFieldsChanged"
+
+// Reasons a managed resource does or does not change immutable fields.
+const (
+	ReasonImmutableFieldChanged    xpv1.ConditionReason = "ImmutableFieldChanged"
+	ReasonImmutableFieldsUnchanged xpv1.ConditionReason = "ImmutableFieldsUnchanged"
+)
+
+// ImmutableFieldsChanged returns a condition indicating that the desired
+// state of a managed resource changes the given fields, which can't be updated
+// in Metronome. The resource must be replaced to change them.
+func ImmutableFieldsChanged(fields ...string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeImmutableFieldsChanged,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonImmutableFieldChanged,
+		Message:            fmt.Sprintf("cannot change immutable fields %s; recreate the resource to change them", strings.Join(fields, ", ")),
+	}
+}
+
+// ImmutableFieldsUnchanged returns a condition indicating that the desired
+// state of a managed resource doesn't change any immutable fields.
+func ImmutableFieldsUnchanged() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeImmutableFieldsChanged,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonImmutableFieldsUnchanged,
+	}
+}
+
+// TypeChangeBlocked indicates whether a change to the desired state of a
+// managed resource can't be applied in Metronome until something outside the
+// resource changes.
+const TypeChangeBlocked xpv1.ConditionType = "ChangeBlocked"
+
+// Reasons a change to a managed resource is or isn't blocked.
+const (
+	ReasonArchivedCustomFieldValues   xpv1.ConditionReason = "ArchivedCustomFieldValues"
+	ReasonReplacedBillableMetricInUse xpv1.ConditionReason = "ReplacedBillableMetricInUse"
+	ReasonChangeUnblocked             xpv1.ConditionReason = "ChangeUnblocked"
+)
+
+// ChangeBlocked returns a condition indicating that a change to the desired
+// state of a managed resource can't be applied, for the given reason.
+func ChangeBlocked(reason xpv1.ConditionReason, msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeChangeBlocked,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            msg,
+	}
+}
+
+// ChangeUnblocked returns a condition indicating that nothing blocks changes
+// to the desired state of a managed resource.
+func ChangeUnblocked() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeChangeBlocked,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonChangeUnblocked,
+	}
+}
+
+// TypeCredentialsValid indicates whether Metronome accepts the credentials of
+// a ProviderConfig.
+const TypeCredentialsValid xpv1.ConditionType = "CredentialsValid"
+
+// Reasons the credentials of a ProviderConfig are or aren't valid.
+const (
+	ReasonCredentialsAccepted    xpv1.ConditionReason = "Accepted"
+	ReasonCredentialsUnavailable xpv1.ConditionReason = "CredentialsUnavailable"
+	ReasonUnauthorized           xpv1.ConditionReason = "Unauthorized"
+	ReasonUnreachable            xpv1.ConditionReason = "Unreachable"
+	ReasonTLSError               xpv1.ConditionReason = "TLSError"
+	ReasonCheckFailed            xpv1.ConditionReason = "CheckFailed"
+)
+
+// CredentialsValid returns a condition indicating that Metronome accepted the
+// credentials of a ProviderConfig.
+func CredentialsValid() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCredentialsValid,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCredentialsAccepted,
+	}
+}
+
+// CredentialsInvalid returns a condition indicating that the credentials of a
+// ProviderConfig couldn't be verified with Metronome, for the given reason.
+func CredentialsInvalid(reason xpv1.ConditionReason, msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCredentialsValid,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            msg,
+	}
+}
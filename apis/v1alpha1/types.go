@@ -27,6 +27,61 @@ type ProviderConfigSpec struct {
 	// Credentials used to connect to Metronome. Typically a file containing the
 	// API key.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// BaseURL of the Metronome API, such as a sandbox environment. Defaults
+	// to the provider-wide base URL.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	BaseURL *string `json:"baseURL,omitempty"`
+
+	// RateLimit overrides the provider-wide client-side rate limit applied to
+	// requests made with these credentials. The limit is shared by every
+	// ProviderConfig that uses the same API key.
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+
+	// Transport configures how requests are sent to Metronome.
+	// +optional
+	Transport *Transport `json:"transport,omitempty"`
+}
+
+// Transport configures the HTTP client used to send requests to Metronome.
+type Transport struct {
+	// Timeout of each request sent to Metronome, including reading the
+	// response. Defaults to the provider-wide request timeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// ProxyURL of an HTTP proxy requests are sent through. The proxy is
+	// chosen from the HTTPS_PROXY and NO_PROXY environment variables of the
+	// provider if unset.
+	// +optional
+	ProxyURL *string `json:"proxyURL,omitempty"`
+
+	// CABundleSecretRef references a Secret key containing PEM encoded CA
+	// certificates trusted in addition to the system roots, such as the CA
+	// of an intercepting proxy.
+	// +optional
+	CABundleSecretRef *xpv1.SecretKeySelector `json:"caBundleSecretRef,omitempty"`
+
+	// ClientCertSecretRef references a kubernetes.io/tls Secret containing
+	// the certificate and key presented for mutual TLS.
+	// +optional
+	ClientCertSecretRef *xpv1.SecretReference `json:"clientCertSecretRef,omitempty"`
+}
+
+// RateLimit configures a client-side token bucket rate limiter.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained number of requests per second sent
+	// to Metronome. A value of 0 disables rate limiting.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RequestsPerSecond *float64 `json:"requestsPerSecond,omitempty"`
+
+	// Burst is the maximum number of requests sent to Metronome at once.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst *int `json:"burst,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -47,6 +102,7 @@ type ProviderConfigStatus struct {
 
 // A ProviderConfig configures a Metronome 'provider', i.e. a connection to a particular
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="CREDENTIALS-VALID",type="string",JSONPath=".status.conditions[?(@.type=='CredentialsValid')].status"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentialsSecretRef.name",priority=1
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,metronome}
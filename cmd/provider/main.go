@@ -17,15 +17,21 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"io"
 	"os"
 	"path/filepath"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
@@ -41,7 +47,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/redbackthomson/provider-metronome/apis"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/connector"
 	metronomeControllers "github.com/redbackthomson/provider-metronome/internal/controller"
+	"github.com/redbackthomson/provider-metronome/internal/migration"
+	metronomeWebhooks "github.com/redbackthomson/provider-metronome/internal/webhook"
 )
 
 func main() {
@@ -56,7 +66,19 @@ func main() {
 
 		enableManagementPolicies = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("true").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
 
-		metronomeBaseUrl = app.Flag("metronome-base-url", "Base URL to use for all Metronome API requests").Default("https://api.metronome.com").Envar("METRONOME_BASE_URL").String()
+		metronomeBaseUrl        = app.Flag("metronome-base-url", "Base URL to use for Metronome API requests, unless overridden by a ProviderConfig.").Default("https://api.metronome.com").Envar("METRONOME_BASE_URL").String()
+		metronomeRateLimit      = app.Flag("metronome-rate-limit", "The maximum rate per second at which requests may be sent to Metronome for each API key. Set to 0 to disable.").Default("50").Envar("METRONOME_RATE_LIMIT").Float64()
+		metronomeRateLimitBurst = app.Flag("metronome-rate-limit-burst", "The maximum number of requests that may be sent to Metronome at once for each API key.").Default("50").Envar("METRONOME_RATE_LIMIT_BURST").Int()
+		metronomeRequestTimeout = app.Flag("metronome-request-timeout", "The time limit of each request sent to Metronome, unless overridden by a ProviderConfig.").Default("30s").Envar("METRONOME_REQUEST_TIMEOUT").Duration()
+
+		validateCustomFieldKeys = app.Flag("validate-custom-field-keys", "Require a CustomFieldKey resource for every custom field set on a Product or RateCard.").Default("false").Envar("VALIDATE_CUSTOM_FIELD_KEYS").Bool()
+		adoptCreatedObjects     = app.Flag("adopt-created-objects", "Mark the billable metrics, customers, products and rate cards the provider creates with a crossplane_uid custom field, so that one whose ID was lost is adopted rather than created again. Creates the crossplane_uid custom field key in Metronome.").Default("false").Envar("ADOPT_CREATED_OBJECTS").Bool()
+
+		webhookTLSCertDir = app.Flag("webhook-tls-cert-dir", "The directory of the TLS certificate and key (tls.crt and tls.key) that the validating webhooks are served with. Webhooks are disabled if unset.").Envar("TLS_SERVER_CERTS_DIR").String()
+
+		tracingEndpoint    = app.Flag("tracing-endpoint", "Host and port of an OTLP/HTTP collector to export traces to. Tracing is disabled if unset.").Envar("TRACING_ENDPOINT").String()
+		tracingInsecure    = app.Flag("tracing-insecure", "Connect to the OTLP/HTTP collector without TLS.").Default("false").Envar("TRACING_INSECURE").Bool()
+		tracingSampleRatio = app.Flag("tracing-sample-ratio", "The fraction of traces that are recorded, between 0 and 1.").Default("1").Envar("TRACING_SAMPLE_RATIO").Float64()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -79,6 +101,14 @@ func main() {
 			SyncPeriod: syncInterval,
 		},
 
+		// Credentials are read straight from the API server so that reading
+		// them doesn't start an informer on every Secret in the cluster.
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{&corev1.Secret{}},
+			},
+		},
+
 		// controller-runtime uses both ConfigMaps and Leases for leader
 		// election by default. Leases expire after 15 seconds, with a
 		// 10 second renewal deadline. We've observed leader loss due to
@@ -91,14 +121,21 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		WebhookServer: webhook.NewServer(webhook.Options{
+			CertDir: *webhookTLSCertDir,
+		}),
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
 	mm := managed.NewMRMetricRecorder()
 	sm := statemetrics.NewMRStateMetrics()
 
+	am := metronomeClient.NewMetrics()
+
 	metrics.Registry.MustRegister(mm)
 	metrics.Registry.MustRegister(sm)
+	metrics.Registry.MustRegister(am)
 
 	mo := controller.MetricOptions{
 		PollStateMetricInterval: *pollStateMetricInterval,
@@ -121,8 +158,54 @@ func main() {
 		log.Info("Beta feature enabled", "flag", feature.EnableBetaManagementPolicies)
 	}
 
-	kingpin.FatalIfError(metronomeControllers.Setup(mgr, o, *metronomeBaseUrl), "Cannot setup Template controllers")
-	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
+	co := connector.Options{
+		BaseURL:      *metronomeBaseUrl,
+		RateLimiters: metronomeClient.NewRateLimiters(*metronomeRateLimit, *metronomeRateLimitBurst),
+		Metrics:      am,
+		HTTPClients:  metronomeClient.NewHTTPClients(*metronomeRequestTimeout),
+		Clients:      connector.NewClientCache(),
+
+		ValidateCustomFieldKeys: *validateCustomFieldKeys,
+		AdoptCreatedObjects:     *adoptCreatedObjects,
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+
+	tp, err := newTracerProvider(ctx, tracingOptions{
+		Endpoint:    *tracingEndpoint,
+		Insecure:    *tracingInsecure,
+		SampleRatio: *tracingSampleRatio,
+	})
+	kingpin.FatalIfError(err, "Cannot set up tracing")
+	if tp != nil {
+		co.TracerProvider = tp
+		log.Info("Tracing enabled", "endpoint", *tracingEndpoint)
+	}
+
+	kingpin.FatalIfError(metronomeControllers.Setup(mgr, o, co), "Cannot setup Template controllers")
+	// runs once the manager has started, and only on the leader. A failed
+	// migration doesn't stop the manager: the resources it couldn't migrate
+	// get a warning event, and are migrated again on the next start.
+	record := event.NewAPIRecorder(mgr.GetEventRecorderFor("migration"))
+	kingpin.FatalIfError(mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if err := migration.Prices(ctx, mgr.GetClient(), record, log); err != nil {
+			log.Info("Cannot migrate prices", "error", err)
+		}
+		return nil
+	})), "Cannot add migrations")
+	if *webhookTLSCertDir != "" {
+		kingpin.FatalIfError(metronomeWebhooks.Setup(mgr), "Cannot setup webhooks")
+	}
+	err = mgr.Start(ctx)
+	if tp != nil {
+		// flush any spans that haven't been exported yet
+		sctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if serr := tp.Shutdown(sctx); serr != nil {
+			log.Info("Cannot shut down tracing", "error", serr)
+		}
+		cancel()
+	}
+	kingpin.FatalIfError(err, "Cannot start controller manager")
 }
 
 // UseISO8601 sets the logger to use ISO8601 timestamp format
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const serviceName = "provider-metronome"
+
+// tracingOptions configure how spans are exported.
+type tracingOptions struct {
+	// Endpoint is the host and port of an OTLP/HTTP collector. Tracing is
+	// disabled if empty.
+	Endpoint string
+	// Insecure disables TLS when connecting to the collector.
+	Insecure bool
+	// SampleRatio is the fraction of traces that are recorded.
+	SampleRatio float64
+}
+
+// newTracerProvider returns a TracerProvider that exports spans to the
+// configured OTLP collector, or nil if tracing is disabled.
+func newTracerProvider(ctx context.Context, o tracingOptions) (*sdktrace.TracerProvider, error) {
+	if o.Endpoint == "" {
+		return nil, nil
+	}
+
+	eo := []otlptracehttp.Option{otlptracehttp.WithEndpoint(o.Endpoint)}
+	if o.Insecure {
+		eo = append(eo, otlptracehttp.WithInsecure())
+	}
+	exp, err := otlptracehttp.New(ctx, eo...)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create OTLP trace exporter")
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	), nil
+}
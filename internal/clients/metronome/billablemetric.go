@@ -21,14 +21,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/google/uuid"
 )
 
 var (
-	ErrBillableMetricInvalidName     = errors.New("invalid billable metric name")
-	ErrBillableMetricAlreadyArchived = errors.New("billable metric already archived")
+	ErrBillableMetricInvalidName = errors.New("invalid billable metric name")
 )
 
 const (
@@ -38,7 +38,7 @@ const (
 type BillableMetricClient interface {
 	CreateBillableMetric(ctx context.Context, reqData CreateBillableMetricRequest) (*CreateBillableMetricResponse, error)
 	GetBillableMetric(ctx context.Context, id string) (*GetBillableMetricResponse, error)
-	ListBillableMetrics(ctx context.Context) (*ListBillableMetricsResponse, error)
+	ListBillableMetrics(ctx context.Context, reqData ListBillableMetricsRequest, nextPage string) (*ListBillableMetricsResponse, error)
 	UpdateBillableMetric(ctx context.Context, id string, reqData UpdateBillableMetricRequest) (*UpdateBillableMetricResponse, error)
 	ArchiveBillableMetric(ctx context.Context, id string) (*ArchiveBillableMetricResponse, error)
 }
@@ -115,6 +115,12 @@ type GetBillableMetricResponse struct {
 	Data BillableMetric `json:"data"`
 }
 
+// ListBillableMetricsRequest represents the query parameters for listing
+// billable metrics. Archived metrics are only listed if IncludeArchived is set.
+type ListBillableMetricsRequest struct {
+	IncludeArchived bool
+}
+
 // ListBillableMetricsResponse represents the response for listing billable metrics.
 type ListBillableMetricsResponse struct {
 	Data     []BillableMetric `json:"data"`
@@ -148,17 +154,14 @@ func (c *BillableMetricClientImpl) CreateBillableMetric(ctx context.Context, req
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, nonIdempotent)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, fmt.Errorf("failed to create billable metric: %s", c.Message)
-		}
-		return nil, fmt.Errorf("failed to create billable metric: %s", resp.Status)
+		return nil, fmt.Errorf("failed to create billable metric: %w", newAPIError(resp))
 	}
 
 	var response CreateBillableMetricResponse
@@ -182,17 +185,14 @@ func (c *BillableMetricClientImpl) GetBillableMetric(ctx context.Context, id str
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, fmt.Errorf("failed to get billable metric: %s", c.Message)
-		}
-		return nil, fmt.Errorf("failed to get billable metric: %s", resp.Status)
+		return nil, fmt.Errorf("failed to get billable metric: %w", newAPIError(resp))
 	}
 
 	var response GetBillableMetricResponse
@@ -203,8 +203,8 @@ func (c *BillableMetricClientImpl) GetBillableMetric(ctx context.Context, id str
 	return &response, nil
 }
 
-// ListBillableMetrics retrieves a list of all billable metrics.
-func (c *BillableMetricClientImpl) ListBillableMetrics(ctx context.Context) (*ListBillableMetricsResponse, error) {
+// ListBillableMetrics retrieves a single page of billable metrics.
+func (c *BillableMetricClientImpl) ListBillableMetrics(ctx context.Context, reqData ListBillableMetricsRequest, nextPage string) (*ListBillableMetricsResponse, error) {
 	url := fmt.Sprintf("%s/v1/billable-metrics", c.Client.baseURL)
 
 	req, err := c.Client.newAuthenticatedRequest(ctx, "GET", url, nil)
@@ -212,17 +212,23 @@ func (c *BillableMetricClientImpl) ListBillableMetrics(ctx context.Context) (*Li
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	q := req.URL.Query()
+	if reqData.IncludeArchived {
+		q.Add("include_archived", "true")
+	}
+	if nextPage != "" {
+		q.Add("next_page", nextPage)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, fmt.Errorf("failed to list billable metrics: %s", c.Message)
-		}
-		return nil, fmt.Errorf("failed to list billable metrics: %s", resp.Status)
+		return nil, fmt.Errorf("failed to list billable metrics: %w", newAPIError(resp))
 	}
 
 	var response ListBillableMetricsResponse
@@ -233,6 +239,18 @@ func (c *BillableMetricClientImpl) ListBillableMetrics(ctx context.Context) (*Li
 	return &response, nil
 }
 
+// AllBillableMetrics returns an iterator over the billable metrics on every
+// page of the results of ListBillableMetrics.
+func AllBillableMetrics(ctx context.Context, c BillableMetricClient, reqData ListBillableMetricsRequest) iter.Seq2[BillableMetric, error] {
+	return Paginate(func(nextPage string) ([]BillableMetric, string, error) {
+		res, err := c.ListBillableMetrics(ctx, reqData, nextPage)
+		if err != nil || res == nil {
+			return nil, "", err
+		}
+		return res.Data, derefPage(res.NextPage), nil
+	})
+}
+
 // UpdateBillableMetric updates a billable metric by ID.
 func (c *BillableMetricClientImpl) UpdateBillableMetric(ctx context.Context, id string, reqData UpdateBillableMetricRequest) (*UpdateBillableMetricResponse, error) {
 	url := fmt.Sprintf("%s/v1/billable-metrics/%s", c.Client.baseURL, id)
@@ -251,17 +269,14 @@ func (c *BillableMetricClientImpl) UpdateBillableMetric(ctx context.Context, id
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, fmt.Errorf("failed to update billable metric: %s", c.Message)
-		}
-		return nil, fmt.Errorf("failed to update billable metric: %s", resp.Status)
+		return nil, fmt.Errorf("failed to update billable metric: %w", newAPIError(resp))
 	}
 
 	var response UpdateBillableMetricResponse
@@ -294,7 +309,7 @@ func (c *BillableMetricClientImpl) ArchiveBillableMetric(ctx context.Context, id
 	}
 
 	// Execute the HTTP request
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -302,13 +317,7 @@ func (c *BillableMetricClientImpl) ArchiveBillableMetric(ctx context.Context, id
 
 	// Check for a successful response
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			if c.Message == errBillableMetricAlreadyArchived {
-				return nil, ErrBillableMetricAlreadyArchived
-			}
-			return nil, fmt.Errorf("failed to archive billable metric: %s", c.Message)
-		}
-		return nil, fmt.Errorf("failed to archive billable metric: %s", resp.Status)
+		return nil, fmt.Errorf("failed to archive billable metric: %w", newAlreadyArchivedError(resp, errBillableMetricAlreadyArchived))
 	}
 
 	// Decode the response data
@@ -0,0 +1,283 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cassette records HTTP interactions with the Metronome API to YAML
+// fixtures and replays them, so tests can exercise the real client without
+// network access.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrUnmatched is returned when replaying a request that doesn't match any
+// unused recorded interaction.
+var ErrUnmatched = errors.New("no recorded interaction matches request")
+
+// scrubbedHeaders are removed from recorded requests so that credentials
+// never end up in fixtures.
+var scrubbedHeaders = []string{"Authorization"}
+
+// A Mode determines whether a Transport records or replays interactions.
+type Mode string
+
+const (
+	// ModeReplay serves responses from a cassette without contacting the
+	// upstream server.
+	ModeReplay Mode = "replay"
+	// ModeRecord sends requests to the upstream server and records every
+	// interaction to the cassette.
+	ModeRecord Mode = "record"
+)
+
+// Cassette is the set of interactions stored in a fixture.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single request and the response it received.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. URL is the path and query of the request;
+// the host is not recorded so cassettes replay against any base URL.
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Transport is an http.RoundTripper that records or replays interactions.
+type Transport struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// An Option configures a Transport.
+type Option func(*Transport)
+
+// WithTransport sets the transport used to send requests upstream while
+// recording. http.DefaultTransport is used by default.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(t *Transport) {
+		t.next = rt
+	}
+}
+
+// New returns a Transport that records to or replays from the cassette at
+// path. Replaying requires the cassette to exist; recording replaces it when
+// Save is called.
+func New(path string, mode Mode, opts ...Option) (*Transport, error) {
+	t := &Transport{
+		path: path,
+		mode: mode,
+		next: http.DefaultTransport,
+	}
+	for _, o := range opts {
+		o(t)
+	}
+
+	switch mode {
+	case ModeRecord:
+	case ModeReplay:
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read cassette")
+		}
+		if err := yaml.Unmarshal(b, &t.cassette); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse cassette %s", path)
+		}
+		t.used = make([]bool, len(t.cassette.Interactions))
+	default:
+		return nil, errors.Errorf("unknown cassette mode %q", mode)
+	}
+	return t, nil
+}
+
+// Client returns an HTTP client that sends requests through the transport.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// RoundTrip records or replays a single request.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.mode == ModeRecord {
+		return t.record(req, body)
+	}
+	return t.replay(req, body)
+}
+
+func (t *Transport) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := req.Header.Clone()
+	for _, h := range scrubbedHeaders {
+		headers.Del(h)
+	}
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.RequestURI(),
+			Headers: headers,
+			Body:    string(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    resp.Header.Clone(),
+			Body:       string(respBody),
+		},
+	})
+	t.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// replay responds with the first unused interaction matching the request's
+// method, URL and body, so repeated identical requests receive the recorded
+// responses in order.
+func (t *Transport) replay(req *http.Request, body []byte) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, in := range t.cassette.Interactions {
+		if t.used[i] || !matches(in.Request, req, body) {
+			continue
+		}
+		t.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Headers.Clone(),
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, errors.Wrapf(ErrUnmatched, "%s %s %s", req.Method, req.URL.RequestURI(), body)
+}
+
+// Save writes the recorded interactions to the cassette. It does nothing
+// when replaying.
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, err := yaml.Marshal(t.cassette)
+	if err != nil {
+		return errors.Wrap(err, "cannot serialize cassette")
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return errors.Wrap(err, "cannot create cassette directory")
+	}
+	return errors.Wrap(os.WriteFile(t.path, b, 0o644), "cannot write cassette") // nolint:gosec // Fixtures aren't secret
+}
+
+// Unused returns the recorded interactions that haven't been replayed.
+func (t *Transport) Unused() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var out []Interaction
+	for i, in := range t.cassette.Interactions {
+		if !t.used[i] {
+			out = append(out, in)
+		}
+	}
+	return out
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+func matches(r Request, req *http.Request, body []byte) bool {
+	return r.Method == req.Method && r.URL == req.URL.RequestURI() && equalBodies([]byte(r.Body), body)
+}
+
+// equalBodies compares JSON bodies semantically, so that recorded fixtures
+// can be reformatted by hand, and any other bodies byte for byte.
+func equalBodies(a, b []byte) bool {
+	var av, bv any
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return bytes.Equal(a, b)
+	}
+	ab, _ := json.Marshal(av)
+	bb, _ := json.Marshal(bv)
+	return bytes.Equal(ab, bb)
+}
@@ -0,0 +1,105 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func post(t *testing.T, c *http.Client, url, body string) (int, string, error) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := c.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b), nil
+}
+
+func Test_Transport_RecordReplay(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		b, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(r.URL.Path + " " + string(b) + " " + strings.Repeat("!", calls)))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "testdata", "c.yaml")
+
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("New(...): %v", err)
+	}
+	for _, body := range []string{`{"a":1,"b":2}`, `{"a":1,"b":2}`, `{"c":3}`} {
+		if _, _, err := post(t, rec.Client(), srv.URL+"/v1/things?next_page=x", body); err != nil {
+			t.Fatalf("post(...): %v", err)
+		}
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save(): %v", err)
+	}
+
+	raw, _ := os.ReadFile(path)
+	if strings.Contains(string(raw), "secret") {
+		t.Errorf("Save(): cassette contains the Authorization header:\n%s", raw)
+	}
+
+	rep, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("New(...): %v", err)
+	}
+
+	// JSON bodies match regardless of formatting, and identical requests
+	// replay their responses in the order they were recorded
+	type result struct {
+		code int
+		body string
+	}
+	var got []result
+	for _, body := range []string{`{"b":2, "a":1}`, `{"c":3}`, `{"a":1,"b":2}`} {
+		code, b, err := post(t, rep.Client(), "http://elsewhere/v1/things?next_page=x", body)
+		if err != nil {
+			t.Fatalf("post(...): %v", err)
+		}
+		got = append(got, result{code, b})
+	}
+	want := []result{
+		{http.StatusCreated, `/v1/things {"a":1,"b":2} !`},
+		{http.StatusCreated, `/v1/things {"c":3} !!!`},
+		{http.StatusCreated, `/v1/things {"a":1,"b":2} !!`},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(result{})); diff != "" {
+		t.Errorf("replay: -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(3, calls); diff != "" {
+		t.Errorf("replay sent requests upstream: -want calls, +got calls: %s", diff)
+	}
+
+	if _, _, err := post(t, rep.Client(), "http://elsewhere/v1/things?next_page=x", `{"c":3}`); !errors.Is(err, ErrUnmatched) {
+		t.Errorf("post(...): want ErrUnmatched once interactions are used up, got %v", err)
+	}
+	if _, _, err := post(t, rep.Client(), "http://elsewhere/v1/things", `{"c":3}`); !errors.Is(err, ErrUnmatched) {
+		t.Errorf("post(...): want ErrUnmatched for a different query, got %v", err)
+	}
+	if diff := cmp.Diff(0, len(rep.Unused())); diff != "" {
+		t.Errorf("Unused(): -want, +got: %s", diff)
+	}
+}
+
+func Test_New_MissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.yaml"), ModeReplay); err == nil {
+		t.Error("New(...): want error replaying a missing cassette")
+	}
+}
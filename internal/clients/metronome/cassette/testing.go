@@ -0,0 +1,58 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cassette
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ModeEnvVar is the environment variable that selects the mode of cassettes
+// started with Start. Cassettes replay unless it is set to "record".
+const ModeEnvVar = "METRONOME_CASSETTE_MODE"
+
+// Start returns a Transport for the cassette testdata/cassettes/<name>.yaml,
+// relative to the test's package. When the test finishes, a recorded cassette
+// is saved and a replayed cassette fails the test if any of its interactions
+// weren't used.
+func Start(tb testing.TB, name string, opts ...Option) *Transport {
+	tb.Helper()
+
+	mode := ModeReplay
+	if Mode(os.Getenv(ModeEnvVar)) == ModeRecord {
+		mode = ModeRecord
+	}
+
+	t, err := New(filepath.Join("testdata", "cassettes", name+".yaml"), mode, opts...)
+	if err != nil {
+		tb.Fatalf("cannot start cassette %s: %v", name, err)
+	}
+
+	tb.Cleanup(func() {
+		if err := t.Save(); err != nil {
+			tb.Errorf("cannot save cassette %s: %v", name, err)
+		}
+		if mode != ModeReplay {
+			return
+		}
+		for _, in := range t.Unused() {
+			tb.Errorf("cassette %s: interaction was not replayed: %s %s", name, in.Request.Method, in.Request.URL)
+		}
+	})
+	return t
+}
@@ -20,8 +20,12 @@ import (
 	"bytes"
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/time/rate"
 )
 
 type Client struct {
@@ -30,6 +34,87 @@ type Client struct {
 	baseURL    string
 	authToken  string
 	httpClient *http.Client
+	retry      retryConfig
+	limiter    *rate.Limiter
+
+	metrics        *Metrics
+	providerConfig string
+
+	tracer trace.Tracer
+
+	onClose []func()
+}
+
+// An Option configures a Client.
+type Option func(*Client)
+
+// WithMaxAttempts sets the total number of times a request is sent to
+// Metronome before giving up, including the first attempt. A value of 1
+// disables retries.
+func WithMaxAttempts(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.retry.maxAttempts = n
+		}
+	}
+}
+
+// WithRetryBudget sets the maximum total time spent waiting between retries
+// of a single request, including any delay requested by Metronome through the
+// Retry-After header.
+func WithRetryBudget(d time.Duration) Option {
+	return func(c *Client) {
+		c.retry.budget = d
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry and the upper bound
+// of the exponential backoff between retries.
+func WithRetryBackoff(base, limit time.Duration) Option {
+	return func(c *Client) {
+		c.retry.backoffBase = base
+		c.retry.backoffLimit = limit
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests to Metronome.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithRateLimiter sets the token bucket every request, including retries,
+// must wait on before being sent to Metronome.
+func WithRateLimiter(l *rate.Limiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+// WithOnClose calls f when the client is closed, to release something the
+// client was created with.
+func WithOnClose(f func()) Option {
+	return func(c *Client) {
+		c.onClose = append(c.onClose, f)
+	}
+}
+
+// WithMetrics records every request sent to Metronome in the given metrics,
+// labeled with the name of the ProviderConfig the client was created for.
+func WithMetrics(m *Metrics, providerConfig string) Option {
+	return func(c *Client) {
+		c.metrics = m
+		c.providerConfig = providerConfig
+	}
+}
+
+// WithTracerProvider records a span for every request sent to Metronome using
+// a tracer from the given provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracer = tp.Tracer(tracerName)
+	}
 }
 
 func (c *Client) BillableMetric() BillableMetricClient {
@@ -44,6 +129,10 @@ func (c *Client) Customer() CustomerClient {
 	return &CustomerClientImpl{Client: c}
 }
 
+func (c *Client) CustomField() CustomFieldClient {
+	return &CustomFieldClientImpl{Client: c}
+}
+
 func (c *Client) CustomFieldKey() CustomFieldKeyClient {
 	return &CustomFieldKeyClientImpl{Client: c}
 }
@@ -70,11 +159,28 @@ func (c *Client) newAuthenticatedRequest(ctx context.Context, method, url string
 	return req, nil
 }
 
-func New(log logging.Logger, baseURL, authToken string) (*Client, error) {
-	return &Client{
+// Close releases what the client was created with, such as its share of a
+// rate limiter, and closes the idle connections of the HTTP client, which may
+// be shared with other clients. The client must not be used once it is
+// closed.
+func (c *Client) Close() {
+	for _, f := range c.onClose {
+		f()
+	}
+	c.httpClient.CloseIdleConnections()
+}
+
+func New(log logging.Logger, baseURL, authToken string, opts ...Option) (*Client, error) {
+	c := &Client{
 		logger:     log,
 		baseURL:    baseURL,
 		authToken:  authToken,
-		httpClient: &http.Client{},
-	}, nil
+		httpClient: &http.Client{Timeout: DefaultRequestTimeout},
+		retry:      defaultRetryConfig(),
+		tracer:     noop.NewTracerProvider().Tracer(tracerName),
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c, nil
 }
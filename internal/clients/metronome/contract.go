@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/pkg/errors"
@@ -31,8 +32,12 @@ var (
 )
 
 type ContractClient interface {
+	CreateContract(ctx context.Context, reqData CreateContractRequest) (*CreateContractResponse, error)
 	GetContract(ctx context.Context, reqData GetContractRequest) (*GetContractResponse, error)
-	ListContracts(ctx context.Context, reqData ListContractsRequest) (*ListContractsResponse, error)
+	ListContracts(ctx context.Context, reqData ListContractsRequest, nextPage string) (*ListContractsResponse, error)
+	AmendContract(ctx context.Context, reqData AmendContractRequest) (*AmendContractResponse, error)
+	UpdateContractEndDate(ctx context.Context, reqData UpdateContractEndDateRequest) error
+	ArchiveContract(ctx context.Context, reqData ArchiveContractRequest) error
 }
 
 type ContractClientImpl struct {
@@ -51,38 +56,129 @@ type ProductIdentifier struct {
 	Name string `json:"name"`
 }
 
+type ScheduleItem struct {
+	Amount       Decimal `json:"amount"`
+	StartingAt   string  `json:"starting_at"`
+	EndingBefore string  `json:"ending_before"`
+}
+
+// AccessSchedule is the schedule of amounts a commit makes available.
+type AccessSchedule struct {
+	CreditTypeID  string         `json:"credit_type_id,omitempty"`
+	ScheduleItems []ScheduleItem `json:"schedule_items"`
+}
+
+type OverwriteRate struct {
+	RateType string  `json:"rate_type"`
+	Price    Decimal `json:"price,omitempty"`
+}
+
 type Commit struct {
 	ID                   string            `json:"id"`
 	Type                 string            `json:"type"`
 	Name                 string            `json:"name"`
 	Description          string            `json:"description"`
 	Product              ProductIdentifier `json:"product"`
+	AccessSchedule       *AccessSchedule   `json:"access_schedule,omitempty"`
 	RolloverFraction     float64           `json:"rollover_fraction"`
 	ApplicableProductIDs []string          `json:"applicable_product_ids"`
 }
 
 type Override struct {
-	ID         string            `json:"id"`
-	Product    ProductIdentifier `json:"product"`
-	StartingAt string            `json:"starting_at"`
-	Type       string            `json:"type"`
-	Multiplier float64           `json:"multiplier"`
+	ID            string            `json:"id"`
+	Product       ProductIdentifier `json:"product"`
+	StartingAt    string            `json:"starting_at"`
+	EndingBefore  string            `json:"ending_before,omitempty"`
+	Type          string            `json:"type"`
+	Multiplier    float64           `json:"multiplier"`
+	OverwriteRate *OverwriteRate    `json:"overwrite_rate,omitempty"`
 }
 
 type Contract struct {
 	ID                  string            `json:"id"`
 	CustomerID          string            `json:"customer_id"`
 	RateCardID          string            `json:"rate_card_id"`
+	Name                string            `json:"name,omitempty"`
 	StartingAt          string            `json:"starting_at"`
 	NetPaymentTermsDays int               `json:"net_payment_terms_days"`
 	EndingBefore        string            `json:"ending_before"`
 	CreatedAt           string            `json:"created_at"`
 	CreatedBy           string            `json:"created_by"`
+	ArchivedAt          string            `json:"archived_at,omitempty"`
+	UniquenessKey       string            `json:"uniqueness_key,omitempty"`
 	CustomFields        map[string]string `json:"custom_fields"`
 	Commits             []Commit          `json:"commits"`
 	Overrides           []Override        `json:"overrides"`
 }
 
+// CommitRequest represents a commit added to a contract.
+type CommitRequest struct {
+	Type                 string          `json:"type"`
+	Name                 string          `json:"name,omitempty"`
+	Description          string          `json:"description,omitempty"`
+	ProductID            string          `json:"product_id"`
+	AccessSchedule       *AccessSchedule `json:"access_schedule,omitempty"`
+	ApplicableProductIDs []string        `json:"applicable_product_ids,omitempty"`
+	RolloverFraction     *float64        `json:"rollover_fraction,omitempty"`
+}
+
+// OverrideRequest represents an override added to a contract.
+type OverrideRequest struct {
+	ProductID     string         `json:"product_id"`
+	StartingAt    string         `json:"starting_at"`
+	EndingBefore  string         `json:"ending_before,omitempty"`
+	Type          string         `json:"type"`
+	Multiplier    *float64       `json:"multiplier,omitempty"`
+	OverwriteRate *OverwriteRate `json:"overwrite_rate,omitempty"`
+}
+
+// CreateContractRequest represents the request payload for creating a
+// contract. Metronome rejects a second contract with the same uniqueness key.
+type CreateContractRequest struct {
+	CustomerID          string            `json:"customer_id"`
+	RateCardID          string            `json:"rate_card_id"`
+	Name                string            `json:"name,omitempty"`
+	StartingAt          string            `json:"starting_at"`
+	EndingBefore        string            `json:"ending_before,omitempty"`
+	NetPaymentTermsDays *int              `json:"net_payment_terms_days,omitempty"`
+	CustomFields        map[string]string `json:"custom_fields,omitempty"`
+	Commits             []CommitRequest   `json:"commits,omitempty"`
+	Overrides           []OverrideRequest `json:"overrides,omitempty"`
+	UniquenessKey       string            `json:"uniqueness_key,omitempty"`
+}
+
+type CreateContractResponse struct {
+	Data IDOnly `json:"data"`
+}
+
+// AmendContractRequest represents the request payload for adding commits and
+// overrides to a contract, effective from StartingAt.
+type AmendContractRequest struct {
+	CustomerID string            `json:"customer_id"`
+	ContractID string            `json:"contract_id"`
+	StartingAt string            `json:"starting_at"`
+	Commits    []CommitRequest   `json:"commits,omitempty"`
+	Overrides  []OverrideRequest `json:"overrides,omitempty"`
+}
+
+type AmendContractResponse struct {
+	Data IDOnly `json:"data"`
+}
+
+// UpdateContractEndDateRequest represents the request payload for changing
+// the end date of a contract. An empty EndingBefore removes the end date.
+type UpdateContractEndDateRequest struct {
+	CustomerID   string `json:"customer_id"`
+	ContractID   string `json:"contract_id"`
+	EndingBefore string `json:"ending_before,omitempty"`
+}
+
+type ArchiveContractRequest struct {
+	CustomerID   string `json:"customer_id"`
+	ContractID   string `json:"contract_id"`
+	VoidInvoices bool   `json:"void_invoices"`
+}
+
 type GetContractResponse struct {
 	Data Contract `json:"data"`
 }
@@ -92,7 +188,43 @@ type ListContractsRequest struct {
 }
 
 type ListContractsResponse struct {
-	Data []Contract `json:"data"`
+	Data     []Contract `json:"data"`
+	NextPage *string    `json:"next_page"`
+}
+
+func (c *ContractClientImpl) CreateContract(ctx context.Context, reqData CreateContractRequest) (*CreateContractResponse, error) {
+	url := fmt.Sprintf("%s/v1/contracts/create", c.Client.baseURL)
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	// safe to retry with a uniqueness key, which makes duplicates conflict
+	kind := nonIdempotent
+	if reqData.UniquenessKey != "" {
+		kind = idempotent
+	}
+	resp, err := c.Client.do(req, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrap(newAPIError(resp), "failed to create contract")
+	}
+
+	var response CreateContractResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
 }
 
 func (c *ContractClientImpl) GetContract(ctx context.Context, reqData GetContractRequest) (*GetContractResponse, error) {
@@ -115,17 +247,14 @@ func (c *ContractClientImpl) GetContract(ctx context.Context, reqData GetContrac
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to get contract")
-		}
-		return nil, errors.New("failed to get contract: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to get contract")
 	}
 
 	var response GetContractResponse
@@ -136,7 +265,7 @@ func (c *ContractClientImpl) GetContract(ctx context.Context, reqData GetContrac
 	return &response, nil
 }
 
-func (c *ContractClientImpl) ListContracts(ctx context.Context, reqData ListContractsRequest) (*ListContractsResponse, error) {
+func (c *ContractClientImpl) ListContracts(ctx context.Context, reqData ListContractsRequest, nextPage string) (*ListContractsResponse, error) {
 	url := fmt.Sprintf("%s/v2/contracts/list", c.Client.baseURL)
 	jsonData, err := json.Marshal(reqData)
 	if err != nil {
@@ -148,17 +277,20 @@ func (c *ContractClientImpl) ListContracts(ctx context.Context, reqData ListCont
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	q := req.URL.Query()
+	if nextPage != "" {
+		q.Add("next_page", nextPage)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to list contracts")
-		}
-		return nil, errors.New("failed to list contracts: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to list contracts")
 	}
 
 	var response ListContractsResponse
@@ -168,3 +300,95 @@ func (c *ContractClientImpl) ListContracts(ctx context.Context, reqData ListCont
 
 	return &response, nil
 }
+
+// AllContracts returns an iterator over the contracts on every page of the
+// results of ListContracts.
+func AllContracts(ctx context.Context, c ContractClient, reqData ListContractsRequest) iter.Seq2[Contract, error] {
+	return Paginate(func(nextPage string) ([]Contract, string, error) {
+		res, err := c.ListContracts(ctx, reqData, nextPage)
+		if err != nil || res == nil {
+			return nil, "", err
+		}
+		return res.Data, derefPage(res.NextPage), nil
+	})
+}
+
+func (c *ContractClientImpl) AmendContract(ctx context.Context, reqData AmendContractRequest) (*AmendContractResponse, error) {
+	url := fmt.Sprintf("%s/v1/contracts/amend", c.Client.baseURL)
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Client.do(req, nonIdempotent)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrap(newAPIError(resp), "failed to amend contract")
+	}
+
+	var response AmendContractResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *ContractClientImpl) UpdateContractEndDate(ctx context.Context, reqData UpdateContractEndDateRequest) error {
+	url := fmt.Sprintf("%s/v1/contracts/updateEndDate", c.Client.baseURL)
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Client.do(req, idempotent)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrap(newAPIError(resp), "failed to update contract end date")
+	}
+
+	return nil
+}
+
+func (c *ContractClientImpl) ArchiveContract(ctx context.Context, reqData ArchiveContractRequest) error {
+	url := fmt.Sprintf("%s/v1/contracts/archive", c.Client.baseURL)
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Client.do(req, idempotent)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrap(newAlreadyArchivedError(resp, "Contract already archived"), "failed to archive contract")
+	}
+
+	return nil
+}
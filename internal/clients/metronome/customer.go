@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/pkg/errors"
@@ -29,7 +30,11 @@ type CustomerClient interface {
 	CreateCustomer(ctx context.Context, reqData CreateCustomerRequest) (*CreateCustomerResponse, error)
 	GetCustomer(ctx context.Context, customerID string) (*GetCustomerResponse, error)
 	UpdateCustomerAliases(ctx context.Context, customerID string, reqData UpdateAliasesRequest) error
-	ListCustomers(ctx context.Context) (*ListCustomersResponse, error)
+	UpdateCustomerName(ctx context.Context, customerID string, reqData UpdateNameRequest) error
+	ListCustomers(ctx context.Context, reqData ListCustomersRequest, nextPage string) (*ListCustomersResponse, error)
+	ArchiveCustomer(ctx context.Context, customerID string) error
+	GetBillingProviderConfigurations(ctx context.Context, customerID string) (*GetBillingProviderConfigurationsResponse, error)
+	SetBillingProviderConfigurations(ctx context.Context, reqData SetBillingProviderConfigurationsRequest) error
 }
 
 type CustomerClientImpl struct {
@@ -42,6 +47,7 @@ type CreateCustomerRequest struct {
 	IngestAliases                 []string                       `json:"ingest_aliases"`
 	Name                          string                         `json:"name"`
 	BillingProviderConfigurations []BillingProviderConfiguration `json:"customer_billing_provider_configurations"`
+	CustomFields                  map[string]string              `json:"custom_fields,omitempty"`
 }
 
 type CreateCustomerResponse struct {
@@ -66,6 +72,13 @@ type GetCustomerData struct {
 	Name           string            `json:"name"`
 	CustomerConfig CustomerConfig    `json:"customer_config"`
 	CustomFields   map[string]string `json:"custom_fields"`
+	ArchivedAt     string            `json:"archived_at,omitempty"`
+}
+
+// ListCustomersRequest represents the query parameters for listing customers.
+// Only active customers are listed unless OnlyArchived is set.
+type ListCustomersRequest struct {
+	OnlyArchived bool
 }
 
 type ListCustomersResponse struct {
@@ -92,6 +105,37 @@ type UpdateAliasesRequest struct {
 	IngestAliases []string `json:"ingest_aliases"`
 }
 
+type UpdateNameRequest struct {
+	Name string `json:"name"`
+}
+
+type ArchiveCustomerRequest struct {
+	ID string `json:"id"`
+}
+
+type GetBillingProviderConfigurationsRequest struct {
+	CustomerID string `json:"customer_id"`
+}
+
+type GetBillingProviderConfigurationsResponse struct {
+	Data []CustomerBillingProviderConfiguration `json:"data"`
+}
+
+// CustomerBillingProviderConfiguration is a billing provider configuration
+// of a single customer.
+type CustomerBillingProviderConfiguration struct {
+	ID              string               `json:"id,omitempty"`
+	CustomerID      string               `json:"customer_id"`
+	BillingProvider string               `json:"billing_provider"`
+	DeliveryMethod  string               `json:"delivery_method"`
+	Configuration   BillingConfiguration `json:"configuration"`
+	ArchivedAt      string               `json:"archived_at,omitempty"`
+}
+
+type SetBillingProviderConfigurationsRequest struct {
+	Data []CustomerBillingProviderConfiguration `json:"data"`
+}
+
 func (c *CustomerClientImpl) CreateCustomer(ctx context.Context, reqData CreateCustomerRequest) (*CreateCustomerResponse, error) {
 	url := fmt.Sprintf("%s/v1/customers", c.Client.baseURL)
 	jsonData, err := json.Marshal(reqData)
@@ -104,17 +148,14 @@ func (c *CustomerClientImpl) CreateCustomer(ctx context.Context, reqData CreateC
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, nonIdempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to create customer")
-		}
-		return nil, errors.New("failed to create customer: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to create customer")
 	}
 
 	var response CreateCustomerResponse
@@ -133,17 +174,14 @@ func (c *CustomerClientImpl) GetCustomer(ctx context.Context, customerID string)
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to get customer")
-		}
-		return nil, errors.New("failed to get customer: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to get customer")
 	}
 
 	var response GetCustomerResponse
@@ -166,40 +204,148 @@ func (c *CustomerClientImpl) UpdateCustomerAliases(ctx context.Context, customer
 		return err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return errors.Wrap(c, "failed to update customer aliases")
-		}
-		return errors.New("failed to update customer aliases: " + resp.Status)
+		return errors.Wrap(newAPIError(resp), "failed to update customer aliases")
 	}
 
 	return nil
 }
 
-func (c *CustomerClientImpl) ListCustomers(ctx context.Context) (*ListCustomersResponse, error) {
+func (c *CustomerClientImpl) UpdateCustomerName(ctx context.Context, customerID string, reqData UpdateNameRequest) error {
+	url := fmt.Sprintf("%s/v1/customers/%s/setName", c.Client.baseURL, customerID)
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Client.do(req, idempotent)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrap(newAPIError(resp), "failed to update customer name")
+	}
+
+	return nil
+}
+
+func (c *CustomerClientImpl) ArchiveCustomer(ctx context.Context, customerID string) error {
+	url := fmt.Sprintf("%s/v1/customers/archive", c.Client.baseURL)
+	jsonData, err := json.Marshal(ArchiveCustomerRequest{ID: customerID})
+	if err != nil {
+		return err
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Client.do(req, idempotent)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrap(newAlreadyArchivedError(resp, "Customer already archived"), "failed to archive customer")
+	}
+
+	return nil
+}
+
+func (c *CustomerClientImpl) GetBillingProviderConfigurations(ctx context.Context, customerID string) (*GetBillingProviderConfigurationsResponse, error) {
+	url := fmt.Sprintf("%s/v1/getCustomerBillingProviderConfigurations", c.Client.baseURL)
+	jsonData, err := json.Marshal(GetBillingProviderConfigurationsRequest{CustomerID: customerID})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Client.do(req, idempotent)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrap(newAPIError(resp), "failed to get customer billing provider configurations")
+	}
+
+	var response GetBillingProviderConfigurationsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *CustomerClientImpl) SetBillingProviderConfigurations(ctx context.Context, reqData SetBillingProviderConfigurationsRequest) error {
+	url := fmt.Sprintf("%s/v1/setCustomerBillingProviderConfigurations", c.Client.baseURL)
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Client.do(req, nonIdempotent)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrap(newAPIError(resp), "failed to set customer billing provider configurations")
+	}
+
+	return nil
+}
+
+func (c *CustomerClientImpl) ListCustomers(ctx context.Context, reqData ListCustomersRequest, nextPage string) (*ListCustomersResponse, error) {
 	url := fmt.Sprintf("%s/v1/customers", c.Client.baseURL)
 	req, err := c.Client.newAuthenticatedRequest(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	q := req.URL.Query()
+	if reqData.OnlyArchived {
+		q.Add("only_archived", "true")
+	}
+	if nextPage != "" {
+		q.Add("next_page", nextPage)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to list customers")
-		}
-		return nil, errors.New("failed to list customers: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to list customers")
 	}
 
 	var response ListCustomersResponse
@@ -209,3 +355,15 @@ func (c *CustomerClientImpl) ListCustomers(ctx context.Context) (*ListCustomersR
 
 	return &response, nil
 }
+
+// AllCustomers returns an iterator over the customers on every page of the
+// results of ListCustomers.
+func AllCustomers(ctx context.Context, c CustomerClient, reqData ListCustomersRequest) iter.Seq2[GetCustomerData, error] {
+	return Paginate(func(nextPage string) ([]GetCustomerData, string, error) {
+		res, err := c.ListCustomers(ctx, reqData, nextPage)
+		if err != nil || res == nil {
+			return nil, "", err
+		}
+		return res.Data, derefPage(res.NextPage), nil
+	})
+}
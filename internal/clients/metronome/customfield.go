@@ -0,0 +1,224 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronome
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrCustomFieldValuesNotListable is returned when the custom field values
+	// of an entity can't be listed, because the client doesn't know how to list
+	// the objects of that entity.
+	ErrCustomFieldValuesNotListable = errors.New("custom field values cannot be listed for entity")
+
+	// ErrCustomFieldValuesArchived is returned when archived objects have a
+	// value for a custom field key. Archived objects can't be changed, so their
+	// values couldn't be set again if the key were removed.
+	ErrCustomFieldValuesArchived = errors.New("custom field values are set on archived objects")
+)
+
+// CustomFieldClient sets the custom field values of any Metronome object.
+type CustomFieldClient interface {
+	SetCustomFieldValues(ctx context.Context, reqData SetCustomFieldValuesRequest) error
+	DeleteCustomFieldValues(ctx context.Context, reqData DeleteCustomFieldValuesRequest) error
+	ListCustomFieldValues(ctx context.Context, entity, key string) (map[string]string, error)
+}
+
+type CustomFieldClientImpl struct {
+	Client *Client
+}
+
+var _ (CustomFieldClient) = (*CustomFieldClientImpl)(nil)
+
+// SetCustomFieldValuesRequest represents the request payload for setting
+// custom field values of an object. Values of keys that aren't included are
+// left unchanged.
+type SetCustomFieldValuesRequest struct {
+	Entity       string            `json:"entity"`
+	EntityID     string            `json:"entity_id"`
+	CustomFields map[string]string `json:"custom_fields"`
+}
+
+// DeleteCustomFieldValuesRequest represents the request payload for deleting
+// custom field values of an object.
+type DeleteCustomFieldValuesRequest struct {
+	Entity   string   `json:"entity"`
+	EntityID string   `json:"entity_id"`
+	Keys     []string `json:"keys"`
+}
+
+// SetCustomFieldValues sets custom field values of an object.
+func (c *CustomFieldClientImpl) SetCustomFieldValues(ctx context.Context, reqData SetCustomFieldValuesRequest) error {
+	url := fmt.Sprintf("%s/v1/customFields/setValues", c.Client.baseURL)
+
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request data: %w", err)
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.Client.do(req, idempotent)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to set custom field values: %w", newAPIError(resp))
+	}
+
+	return nil
+}
+
+// DeleteCustomFieldValues deletes custom field values of an object.
+func (c *CustomFieldClientImpl) DeleteCustomFieldValues(ctx context.Context, reqData DeleteCustomFieldValuesRequest) error {
+	url := fmt.Sprintf("%s/v1/customFields/deleteValues", c.Client.baseURL)
+
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request data: %w", err)
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.Client.do(req, idempotent)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to delete custom field values: %w", newAPIError(resp))
+	}
+
+	return nil
+}
+
+// ListCustomFieldValues returns the values of a custom field key on every
+// object of an entity, keyed by object ID. Metronome has no endpoint for this,
+// so the objects are listed and their custom fields read one by one. Only
+// customers, products, billable metrics and rate cards can be listed; other
+// entities return ErrCustomFieldValuesNotListable. If any archived objects
+// have a value, ErrCustomFieldValuesArchived is returned naming them.
+func (c *CustomFieldClientImpl) ListCustomFieldValues(ctx context.Context, entity, key string) (map[string]string, error) {
+	values := map[string]string{}
+	var archived []string
+	add := func(id string, isArchived bool, fields map[string]string) {
+		v, ok := fields[key]
+		switch {
+		case !ok:
+		case isArchived:
+			archived = append(archived, id)
+		default:
+			values[id] = v
+		}
+	}
+
+	switch entity {
+	case EntityCustomer:
+		for _, req := range []ListCustomersRequest{{}, {OnlyArchived: true}} {
+			for cu, err := range AllCustomers(ctx, c.Client.Customer(), req) {
+				if err != nil {
+					return nil, err
+				}
+				add(cu.ID, req.OnlyArchived, cu.CustomFields)
+			}
+		}
+	case EntityProduct:
+		for p, err := range AllProducts(ctx, c.Client.Product(), ListProductsRequest{ArchiveFilter: "ALL"}) {
+			if err != nil {
+				return nil, err
+			}
+			add(p.ID, p.ArchivedAt != "", p.CustomFields)
+		}
+	case EntityBillableMetric:
+		for m, err := range AllBillableMetrics(ctx, c.Client.BillableMetric(), ListBillableMetricsRequest{IncludeArchived: true}) {
+			if err != nil {
+				return nil, err
+			}
+			add(m.ID, m.ArchivedAt != "", m.CustomFields)
+		}
+	case EntityRateCard:
+		for rc, err := range AllRateCards(ctx, c.Client.RateCard()) {
+			if err != nil {
+				return nil, err
+			}
+			add(rc.ID, false, rc.CustomFields)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrCustomFieldValuesNotListable, entity)
+	}
+
+	if len(archived) > 0 {
+		slices.Sort(archived)
+		return nil, fmt.Errorf("%w: %s %s", ErrCustomFieldValuesArchived, entity, strings.Join(archived, ", "))
+	}
+	return values, nil
+}
+
+// SyncCustomFieldValues sets the custom field values of an object that differ
+// from the observed values, and deletes those that are no longer desired. The
+// owner marker is never deleted.
+func SyncCustomFieldValues(ctx context.Context, c CustomFieldClient, entity, id string, desired, observed map[string]string) error {
+	set := map[string]string{}
+	for k, v := range desired {
+		if ov, ok := observed[k]; !ok || ov != v {
+			set[k] = v
+		}
+	}
+	var remove []string
+	for k := range WithoutOwner(observed) {
+		if _, ok := desired[k]; !ok {
+			remove = append(remove, k)
+		}
+	}
+	slices.Sort(remove)
+
+	if len(set) > 0 {
+		if err := c.SetCustomFieldValues(ctx, SetCustomFieldValuesRequest{
+			Entity:       entity,
+			EntityID:     id,
+			CustomFields: set,
+		}); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if err := c.DeleteCustomFieldValues(ctx, DeleteCustomFieldValuesRequest{
+			Entity:   entity,
+			EntityID: id,
+			Keys:     remove,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
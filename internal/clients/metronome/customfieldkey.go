@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 )
 
@@ -80,17 +81,14 @@ func (c *CustomFieldKeyClientImpl) CreateCustomFieldKey(ctx context.Context, req
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, nonIdempotent)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return fmt.Errorf("failed to create custom field key: %s", c.Message)
-		}
-		return fmt.Errorf("failed to create custom field key: %s", resp.Status)
+		return fmt.Errorf("failed to create custom field key: %w", newAPIError(resp))
 	}
 
 	return nil
@@ -116,17 +114,14 @@ func (c *CustomFieldKeyClientImpl) ListCustomFieldKeys(ctx context.Context, reqD
 	}
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, fmt.Errorf("failed to list custom field keys: %s", c.Message)
-		}
-		return nil, fmt.Errorf("failed to list custom field keys: %s", resp.Status)
+		return nil, fmt.Errorf("failed to list custom field keys: %w", newAPIError(resp))
 	}
 
 	var response ListCustomFieldKeysResponse
@@ -137,6 +132,18 @@ func (c *CustomFieldKeyClientImpl) ListCustomFieldKeys(ctx context.Context, reqD
 	return &response, nil
 }
 
+// AllCustomFieldKeys returns an iterator over the custom field keys on every
+// page of the results of ListCustomFieldKeys.
+func AllCustomFieldKeys(ctx context.Context, c CustomFieldKeyClient, reqData ListCustomFieldKeysRequest) iter.Seq2[CustomFieldKey, error] {
+	return Paginate(func(nextPage string) ([]CustomFieldKey, string, error) {
+		res, err := c.ListCustomFieldKeys(ctx, reqData, nextPage)
+		if err != nil || res == nil {
+			return nil, "", err
+		}
+		return res.Data, res.NextPage, nil
+	})
+}
+
 // DeleteCustomFieldKey deletes a custom field key by ID.
 func (c *CustomFieldKeyClientImpl) DeleteCustomFieldKey(ctx context.Context, reqData DeleteCustomFieldKeyRequest) error {
 	url := fmt.Sprintf("%s/v1/customFields/removeKey", c.Client.baseURL)
@@ -154,7 +161,7 @@ func (c *CustomFieldKeyClientImpl) DeleteCustomFieldKey(ctx context.Context, req
 	}
 
 	// Execute the HTTP request
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -162,10 +169,7 @@ func (c *CustomFieldKeyClientImpl) DeleteCustomFieldKey(ctx context.Context, req
 
 	// Check for a successful response
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return fmt.Errorf("failed to delete custom field key: %s", c.Message)
-		}
-		return fmt.Errorf("failed to delete custom field key: %s", resp.Status)
+		return fmt.Errorf("failed to delete custom field key: %w", newAPIError(resp))
 	}
 
 	return nil
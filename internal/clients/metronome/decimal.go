@@ -0,0 +1,53 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronome
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Decimal is an exact decimal number, such as a price. It is sent to and read
+// from Metronome as a JSON number without going through a float, so sub-cent
+// prices keep every digit.
+type Decimal string
+
+// MarshalJSON writes the decimal as a JSON number. An empty decimal is zero.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d == "" {
+		return []byte("0"), nil
+	}
+	// a valid JSON value that starts like a number is a number
+	if !json.Valid([]byte(d)) || (d[0] != '-' && (d[0] < '0' || d[0] > '9')) {
+		return nil, fmt.Errorf("invalid decimal %q", string(d))
+	}
+	return []byte(d), nil
+}
+
+// UnmarshalJSON reads the decimal from a JSON number, or from a string.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*d = Decimal(n)
+	return nil
+}
@@ -0,0 +1,82 @@
+package metronome
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Decimal_MarshalJSON(t *testing.T) {
+	type want struct {
+		out string
+		err bool
+	}
+	cases := map[string]struct {
+		d Decimal
+		want
+	}{
+		"Empty": {
+			d:    "",
+			want: want{out: "0"},
+		},
+		"SubCent": {
+			d:    "0.000000000123456789012345",
+			want: want{out: "0.000000000123456789012345"},
+		},
+		"Exponent": {
+			d:    "1e-4",
+			want: want{out: "1e-4"},
+		},
+		"NotANumber": {
+			d:    "true",
+			want: want{err: true},
+		},
+		"Quoted": {
+			d:    `"1"`,
+			want: want{err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.d.MarshalJSON()
+			if (err != nil) != tc.want.err {
+				t.Fatalf("d.MarshalJSON(): want error %t, got %v", tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.out, string(got)); diff != "" {
+				t.Errorf("d.MarshalJSON(): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_Decimal_UnmarshalJSON(t *testing.T) {
+	cases := map[string]struct {
+		body string
+		want Decimal
+	}{
+		"Number": {
+			body: `{"price":0.000000000123456789012345}`,
+			want: "0.000000000123456789012345",
+		},
+		"String": {
+			body: `{"price":"12.50"}`,
+			want: "12.50",
+		},
+		"Null": {
+			body: `{"price":null}`,
+			want: "",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got Tier
+			if err := json.Unmarshal([]byte(tc.body), &got); err != nil {
+				t.Fatalf("json.Unmarshal(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Price); diff != "" {
+				t.Errorf("json.Unmarshal(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
@@ -1,28 +1,130 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
 package metronome
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Classes of API errors. Use errors.Is to check whether an error returned by
+// the client belongs to one of these classes.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+)
+
+const (
+	// requestIDHeader is the response header Metronome uses to identify a
+	// request when contacting support.
+	requestIDHeader = "X-Request-Id"
+
+	// maxErrorBodySize limits how much of an error response body is kept.
+	maxErrorBodySize = 64 << 10
 )
 
-type ClientError struct {
-	Message string `json:"message"`
+// APIError is returned when Metronome responds to a request with a non-200
+// status code.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// RequestID is the Metronome request ID of the response, if any.
+	RequestID string
+	// Endpoint is the method and path of the request, e.g.
+	// "POST /v1/contract-pricing/products/get".
+	Endpoint string
+	// Message is the error message returned by Metronome, if any.
+	Message string
+	// Body is the raw response body.
+	Body []byte
+
+	// class overrides the error class derived from the status code, for
+	// responses Metronome doesn't report with a distinguishing status.
+	class error
 }
 
-func (e *ClientError) Error() string {
-	return e.Message
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	s := fmt.Sprintf("%s: %d %s", e.Endpoint, e.StatusCode, msg)
+	if e.RequestID != "" {
+		s += " (request ID " + e.RequestID + ")"
+	}
+	return s
 }
 
-func ParseClientError(response io.Reader) *ClientError {
-	var err ClientError
-	if err := json.NewDecoder(response).Decode(&err); err != nil {
-		return nil // nolint:nilerr // This isn't a client error
+// Is reports whether the error belongs to the given error class.
+func (e *APIError) Is(target error) bool {
+	if e.class != nil {
+		return target == e.class
+	}
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusConflict:
+		return target == ErrConflict
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return target == ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return target == ErrValidation
 	}
-	return &err
+	return false
 }
 
-func UnwrapClientError(res *http.Response) (*ClientError, bool) {
-	c := ParseClientError(res.Body)
-	return c, c != nil
+// newAPIError builds an APIError from a non-200 response, consuming its body.
+func newAPIError(resp *http.Response) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(requestIDHeader),
+	}
+	if resp.Request != nil {
+		e.Endpoint = resp.Request.Method + " " + resp.Request.URL.Path
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	e.Body = body
+
+	var msg struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &msg); err == nil {
+		e.Message = msg.Message
+	}
+	return e
+}
+
+// newAlreadyArchivedError builds an APIError from a failed archive response,
+// classifying it as a conflict if Metronome reports that the object was
+// already archived.
+func newAlreadyArchivedError(resp *http.Response, alreadyArchivedMessage string) *APIError {
+	e := newAPIError(resp)
+	if e.Message == alreadyArchivedMessage {
+		e.class = ErrConflict
+	}
+	return e
 }
@@ -0,0 +1,109 @@
+package metronome
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+)
+
+func Test_NewAPIError(t *testing.T) {
+	classes := []error{ErrNotFound, ErrConflict, ErrUnauthorized, ErrRateLimited, ErrValidation}
+
+	type args struct {
+		status          int
+		body            string
+		alreadyArchived string
+	}
+	type want struct {
+		err   *APIError
+		class error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotFound": {
+			args: args{status: http.StatusNotFound, body: `{"message":"Product not found"}`},
+			want: want{
+				err: &APIError{
+					StatusCode: http.StatusNotFound,
+					RequestID:  "req-1",
+					Endpoint:   "POST /v1/contract-pricing/products/get",
+					Message:    "Product not found",
+					Body:       []byte(`{"message":"Product not found"}`),
+				},
+				class: ErrNotFound,
+			},
+		},
+		"UnparseableBody": {
+			args: args{status: http.StatusBadGateway, body: `<html>`},
+			want: want{
+				err: &APIError{
+					StatusCode: http.StatusBadGateway,
+					RequestID:  "req-1",
+					Endpoint:   "POST /v1/contract-pricing/products/get",
+					Body:       []byte(`<html>`),
+				},
+			},
+		},
+		"Unauthorized": {
+			args: args{status: http.StatusForbidden},
+			want: want{class: ErrUnauthorized},
+		},
+		"RateLimited": {
+			args: args{status: http.StatusTooManyRequests},
+			want: want{class: ErrRateLimited},
+		},
+		"Validation": {
+			args: args{status: http.StatusBadRequest, body: `{"message":"name is required"}`},
+			want: want{class: ErrValidation},
+		},
+		"AlreadyArchivedIsConflict": {
+			args: args{
+				status:          http.StatusBadRequest,
+				body:            `{"message":"Product already archived"}`,
+				alreadyArchived: "Product already archived",
+			},
+			want: want{class: ErrConflict},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tc.args.status,
+				Header:     http.Header{requestIDHeader: []string{"req-1"}},
+				Body:       io.NopCloser(strings.NewReader(tc.args.body)),
+				Request: &http.Request{
+					Method: "POST",
+					URL:    &url.URL{Path: "/v1/contract-pricing/products/get"},
+				},
+			}
+
+			var got *APIError
+			if tc.args.alreadyArchived != "" {
+				got = newAlreadyArchivedError(resp, tc.args.alreadyArchived)
+			} else {
+				got = newAPIError(resp)
+			}
+
+			if tc.want.err != nil {
+				if diff := cmp.Diff(tc.want.err, got, cmpopts.IgnoreUnexported(APIError{}), cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("newAPIError(...): -want, +got: %s", diff)
+				}
+			}
+
+			wrapped := errors.Wrap(got, "failed")
+			for _, c := range classes {
+				if diff := cmp.Diff(c == tc.want.class, errors.Is(wrapped, c)); diff != "" {
+					t.Errorf("errors.Is(err, %q): -want, +got: %s", c, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronome
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	labelOperation      = "operation"
+	labelMethod         = "method"
+	labelProviderConfig = "provider_config"
+	labelCode           = "code"
+
+	// codeError is recorded as the status code of requests that failed
+	// without a response.
+	codeError = "error"
+)
+
+// Metrics records every request sent to the Metronome API. It implements
+// prometheus.Collector so it can be registered with a metrics registry.
+type Metrics struct {
+	requests  *prometheus.CounterVec
+	responses *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+}
+
+var _ prometheus.Collector = (*Metrics)(nil)
+
+// NewMetrics returns a new set of Metronome API metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "metronome_api",
+			Name:      "requests_total",
+			Help:      "The number of requests sent to the Metronome API, including retries.",
+		}, []string{labelOperation, labelMethod, labelProviderConfig}),
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "metronome_api",
+			Name:      "responses_total",
+			Help:      "The number of responses received from the Metronome API, by status code.",
+		}, []string{labelOperation, labelMethod, labelProviderConfig, labelCode}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "metronome_api",
+			Name:      "request_duration_seconds",
+			Help:      "The time taken for the Metronome API to respond to a request.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{labelOperation, labelMethod, labelProviderConfig}),
+	}
+}
+
+// Describe sends the descriptors of the Metronome API metrics.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.responses.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect sends the current values of the Metronome API metrics.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.responses.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// observe records a single request. statusCode is zero if no response was
+// received.
+func (m *Metrics) observe(operation, method, providerConfig string, statusCode int, d time.Duration) {
+	m.requests.WithLabelValues(operation, method, providerConfig).Inc()
+	m.duration.WithLabelValues(operation, method, providerConfig).Observe(d.Seconds())
+
+	code := codeError
+	if statusCode != 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	m.responses.WithLabelValues(operation, method, providerConfig, code).Inc()
+}
+
+// operationName returns a low-cardinality name for the API operation at the
+// given path, e.g. "products/get" for "/v1/contract-pricing/products/get".
+// Path segments that are object IDs are replaced with ":id".
+func operationName(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 0 && len(segments[0]) > 1 && segments[0][0] == 'v' {
+		if _, err := strconv.Atoi(segments[0][1:]); err == nil {
+			segments = segments[1:]
+		}
+	}
+	if len(segments) > 0 && segments[0] == "contract-pricing" {
+		segments = segments[1:]
+	}
+	for i, s := range segments {
+		if IsUUID(s) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,54 @@
+package metronome
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func Test_OperationName(t *testing.T) {
+	cases := map[string]string{
+		"/v1/contract-pricing/products/get":                                   "products/get",
+		"/v1/contract-pricing/rate-cards/getRates":                            "rate-cards/getRates",
+		"/v1/billable-metrics/3f3c2a5e-8f0c-4a53-9a0b-0a1d2e3f4a5b":           "billable-metrics/:id",
+		"/v1/customers/3f3c2a5e-8f0c-4a53-9a0b-0a1d2e3f4a5b/setIngestAliases": "customers/:id/setIngestAliases",
+		"/v2/contracts/list":      "contracts/list",
+		"/v1/customFields/addKey": "customFields/addKey",
+	}
+	for path, want := range cases {
+		t.Run(path, func(t *testing.T) {
+			if diff := cmp.Diff(want, operationName(path)); diff != "" {
+				t.Errorf("operationName(%q): -want, +got: %s", path, diff)
+			}
+		})
+	}
+}
+
+func Test_Metrics_Observe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	m := NewMetrics()
+	c, _ := New(logging.NewNopLogger(), srv.URL, "token", WithMetrics(m, "default"))
+
+	if _, err := c.Product().GetProduct(context.Background(), GetProductRequest{ID: "3f3c2a5e-8f0c-4a53-9a0b-0a1d2e3f4a5b"}); err == nil {
+		t.Fatal("GetProduct(...): expected error")
+	}
+
+	if diff := cmp.Diff(1.0, testutil.ToFloat64(m.requests.WithLabelValues("products/get", "POST", "default"))); diff != "" {
+		t.Errorf("requests: -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(1.0, testutil.ToFloat64(m.responses.WithLabelValues("products/get", "POST", "default", "404"))); diff != "" {
+		t.Errorf("responses: -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(1, testutil.CollectAndCount(m.duration)); diff != "" {
+		t.Errorf("duration: -want, +got: %s", diff)
+	}
+}
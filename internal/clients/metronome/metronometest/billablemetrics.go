@@ -0,0 +1,185 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronometest
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	metronome "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+var aggregationTypes = []string{
+	metronome.AggregationCount,
+	metronome.AggregationLatest,
+	metronome.AggregationMax,
+	metronome.AggregationSum,
+	metronome.AggregationUnique,
+}
+
+type billableMetric struct {
+	metric     metronome.BillableMetric
+	archivedAt time.Time
+}
+
+func (m *billableMetric) view() metronome.BillableMetric {
+	out := m.metric
+	if !m.archivedAt.IsZero() {
+		out.ArchivedAt = formatTime(m.archivedAt)
+	}
+	return out
+}
+
+func (s *Server) registerBillableMetrics(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/billable-metrics/create", s.createBillableMetric)
+	mux.HandleFunc("GET /v1/billable-metrics", s.listBillableMetrics)
+	mux.HandleFunc("GET /v1/billable-metrics/{id}", s.getBillableMetric)
+	mux.HandleFunc("PUT /v1/billable-metrics/{id}", s.updateBillableMetric)
+	mux.HandleFunc("POST /v1/billable-metrics/archive", s.archiveBillableMetric)
+}
+
+func (s *Server) findBillableMetric(id string) *billableMetric {
+	for _, m := range s.billableMetrics {
+		if m.metric.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *Server) createBillableMetric(w http.ResponseWriter, r *http.Request) {
+	var req metronome.CreateBillableMetricRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if req.SQL == "" {
+		agg := strings.ToLower(string(req.AggregationType))
+		if !slices.Contains(aggregationTypes, agg) {
+			writeError(w, http.StatusBadRequest, "aggregation_type must be one of %s", strings.Join(aggregationTypes, ", "))
+			return
+		}
+		if agg != metronome.AggregationCount && req.AggregationKey == "" {
+			writeError(w, http.StatusBadRequest, "aggregation_key is required for %s aggregations", agg)
+			return
+		}
+	}
+
+	if !s.checkCustomFields(w, metronome.EntityBillableMetric, req.CustomFields) {
+		return
+	}
+
+	m := &billableMetric{metric: metronome.BillableMetric{
+		ID:              newID(),
+		Name:            req.Name,
+		AggregationType: req.AggregationType,
+		AggregationKey:  req.AggregationKey,
+		EventTypeFilter: req.EventTypeFilter,
+		PropertyFilters: req.PropertyFilters,
+		GroupKeys:       req.GroupKeys,
+		CustomFields:    req.CustomFields,
+		SQL:             req.SQL,
+	}}
+	s.billableMetrics = append(s.billableMetrics, m)
+
+	writeJSON(w, metronome.CreateBillableMetricResponse{Data: m.view()})
+}
+
+func (s *Server) getBillableMetric(w http.ResponseWriter, r *http.Request) {
+	m := s.findBillableMetric(r.PathValue("id"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "Billable metric not found")
+		return
+	}
+
+	writeJSON(w, metronome.GetBillableMetricResponse{Data: m.view()})
+}
+
+func (s *Server) listBillableMetrics(w http.ResponseWriter, r *http.Request) {
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+
+	var matched []metronome.BillableMetric
+	for _, m := range s.billableMetrics {
+		if !includeArchived && !m.archivedAt.IsZero() {
+			continue
+		}
+		matched = append(matched, m.view())
+	}
+
+	items, next, err := page(s, r, matched)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, metronome.ListBillableMetricsResponse{Data: items, NextPage: nullablePage(next)})
+}
+
+func (s *Server) updateBillableMetric(w http.ResponseWriter, r *http.Request) {
+	var req metronome.UpdateBillableMetricRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	m := s.findBillableMetric(r.PathValue("id"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "Billable metric not found")
+		return
+	}
+	if !m.archivedAt.IsZero() {
+		writeError(w, http.StatusBadRequest, "Cannot update an archived billable metric")
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	m.metric.Name = req.Name
+
+	writeJSON(w, metronome.UpdateBillableMetricResponse{Data: m.view()})
+}
+
+func (s *Server) archiveBillableMetric(w http.ResponseWriter, r *http.Request) {
+	var req metronome.ArchiveBillableMetricRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	m := s.findBillableMetric(req.ID)
+	if m == nil {
+		writeError(w, http.StatusNotFound, "Billable metric not found")
+		return
+	}
+	if !m.archivedAt.IsZero() {
+		writeError(w, http.StatusBadRequest, "Billable metric already archived")
+		return
+	}
+	for _, p := range s.products {
+		if p.archivedAt.IsZero() && p.view(s.now()).Current.BillableMetricID == m.metric.ID {
+			writeError(w, http.StatusBadRequest, "Billable metric is in use by product %s", p.id)
+			return
+		}
+	}
+	m.archivedAt = s.now()
+
+	writeJSON(w, dataID(m.metric.ID))
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronometest
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	metronome "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+func (s *Server) registerContracts(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v2/contracts/get", s.getContract)
+	mux.HandleFunc("POST /v2/contracts/list", s.listContracts)
+	mux.HandleFunc("POST /v1/contracts/create", s.createContract)
+	mux.HandleFunc("POST /v1/contracts/amend", s.amendContract)
+	mux.HandleFunc("POST /v1/contracts/updateEndDate", s.updateContractEndDate)
+	mux.HandleFunc("POST /v1/contracts/archive", s.archiveContract)
+}
+
+var (
+	commitTypes   = []string{"PREPAID", "POSTPAID"}
+	overrideTypes = []string{"MULTIPLIER", "OVERWRITE"}
+)
+
+// AddContract stores a contract on the server, assigning it an ID and
+// creation time if it has none, and returns its ID. The contract's customer
+// must already exist.
+func (s *Server) AddContract(c metronome.Contract) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c.ID == "" {
+		c.ID = newID()
+	}
+	if c.CreatedAt == "" {
+		c.CreatedAt = formatTime(s.now())
+	}
+	s.contracts = append(s.contracts, &c)
+	return c.ID
+}
+
+func (s *Server) findContract(customerID, contractID string) *metronome.Contract {
+	for _, c := range s.contracts {
+		if c.ID == contractID && c.CustomerID == customerID {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *Server) getContract(w http.ResponseWriter, r *http.Request) {
+	var req metronome.GetContractRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if s.findCustomer(req.CustomerID) == nil {
+		writeError(w, http.StatusNotFound, "Customer not found")
+		return
+	}
+	c := s.findContract(req.CustomerID, req.ContractID)
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Contract not found")
+		return
+	}
+
+	writeJSON(w, metronome.GetContractResponse{Data: *c})
+}
+
+func (s *Server) listContracts(w http.ResponseWriter, r *http.Request) {
+	var req metronome.ListContractsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if s.findCustomer(req.CustomerID) == nil {
+		writeError(w, http.StatusNotFound, "Customer not found")
+		return
+	}
+
+	matched := []metronome.Contract{}
+	for _, c := range s.contracts {
+		if c.CustomerID == req.CustomerID {
+			matched = append(matched, *c)
+		}
+	}
+
+	items, next, err := page(s, r, matched)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, metronome.ListContractsResponse{Data: items, NextPage: nullablePage(next)})
+}
+
+func (s *Server) createContract(w http.ResponseWriter, r *http.Request) {
+	var req metronome.CreateContractRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if s.findCustomer(req.CustomerID) == nil {
+		writeError(w, http.StatusBadRequest, "Customer %s not found", req.CustomerID)
+		return
+	}
+	if s.findRateCard(req.RateCardID) == nil {
+		writeError(w, http.StatusBadRequest, "Rate card %s not found", req.RateCardID)
+		return
+	}
+	if req.UniquenessKey != "" && slices.ContainsFunc(s.contracts, func(c *metronome.Contract) bool {
+		return c.UniquenessKey == req.UniquenessKey
+	}) {
+		writeError(w, http.StatusConflict, "A contract with uniqueness key %s already exists", req.UniquenessKey)
+		return
+	}
+	start, err := parseTime(req.StartingAt)
+	if err != nil || start.IsZero() || !onHour(start) {
+		writeError(w, http.StatusBadRequest, "starting_at must be an RFC 3339 timestamp on an hour boundary")
+		return
+	}
+	end, err := parseTime(req.EndingBefore)
+	if err != nil || (!end.IsZero() && (!onHour(end) || !end.After(start))) {
+		writeError(w, http.StatusBadRequest, "ending_before must be an RFC 3339 timestamp on an hour boundary after starting_at")
+		return
+	}
+
+	c := &metronome.Contract{
+		ID:            newID(),
+		CustomerID:    req.CustomerID,
+		RateCardID:    req.RateCardID,
+		Name:          req.Name,
+		StartingAt:    formatTime(start),
+		CreatedAt:     formatTime(s.now()),
+		CreatedBy:     "metronometest",
+		UniquenessKey: req.UniquenessKey,
+		CustomFields:  req.CustomFields,
+	}
+	if !end.IsZero() {
+		c.EndingBefore = formatTime(end)
+	}
+	if req.NetPaymentTermsDays != nil {
+		c.NetPaymentTermsDays = *req.NetPaymentTermsDays
+	}
+	if !s.addTerms(w, c, req.Commits, req.Overrides) {
+		return
+	}
+	s.contracts = append(s.contracts, c)
+
+	writeJSON(w, dataID(c.ID))
+}
+
+// addTerms validates the given commits and overrides and adds them to the
+// contract, responding with a validation error if any are invalid.
+func (s *Server) addTerms(w http.ResponseWriter, c *metronome.Contract, commits []metronome.CommitRequest, overrides []metronome.OverrideRequest) bool {
+	var added []metronome.Commit
+	for _, req := range commits {
+		if !slices.Contains(commitTypes, strings.ToUpper(req.Type)) {
+			writeError(w, http.StatusBadRequest, "commit type must be one of %s", strings.Join(commitTypes, ", "))
+			return false
+		}
+		p := s.findProduct(req.ProductID)
+		if p == nil || !p.archivedAt.IsZero() {
+			writeError(w, http.StatusBadRequest, "Product %s not found", req.ProductID)
+			return false
+		}
+		commit := metronome.Commit{
+			ID:                   newID(),
+			Type:                 strings.ToUpper(req.Type),
+			Name:                 req.Name,
+			Description:          req.Description,
+			Product:              metronome.ProductIdentifier{ID: p.id, Name: p.view(s.now()).Current.Name},
+			AccessSchedule:       req.AccessSchedule,
+			ApplicableProductIDs: req.ApplicableProductIDs,
+		}
+		if req.RolloverFraction != nil {
+			commit.RolloverFraction = *req.RolloverFraction
+		}
+		added = append(added, commit)
+	}
+
+	var addedOverrides []metronome.Override
+	for _, req := range overrides {
+		if !slices.Contains(overrideTypes, strings.ToUpper(req.Type)) {
+			writeError(w, http.StatusBadRequest, "override type must be one of %s", strings.Join(overrideTypes, ", "))
+			return false
+		}
+		p := s.findProduct(req.ProductID)
+		if p == nil || !p.archivedAt.IsZero() {
+			writeError(w, http.StatusBadRequest, "Product %s not found", req.ProductID)
+			return false
+		}
+		start, err := parseTime(req.StartingAt)
+		if err != nil || start.IsZero() || !onHour(start) {
+			writeError(w, http.StatusBadRequest, "override starting_at must be an RFC 3339 timestamp on an hour boundary")
+			return false
+		}
+		override := metronome.Override{
+			ID:            newID(),
+			Product:       metronome.ProductIdentifier{ID: p.id, Name: p.view(s.now()).Current.Name},
+			StartingAt:    formatTime(start),
+			EndingBefore:  req.EndingBefore,
+			Type:          strings.ToUpper(req.Type),
+			OverwriteRate: req.OverwriteRate,
+		}
+		if req.Multiplier != nil {
+			override.Multiplier = *req.Multiplier
+		}
+		addedOverrides = append(addedOverrides, override)
+	}
+
+	c.Commits = append(c.Commits, added...)
+	c.Overrides = append(c.Overrides, addedOverrides...)
+	return true
+}
+
+// findActiveContract returns the contract with the given ID, responding with
+// an error if it doesn't exist or has been archived.
+func (s *Server) findActiveContract(w http.ResponseWriter, customerID, contractID string) *metronome.Contract {
+	c := s.findContract(customerID, contractID)
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Contract not found")
+		return nil
+	}
+	if c.ArchivedAt != "" {
+		writeError(w, http.StatusBadRequest, "Contract already archived")
+		return nil
+	}
+	return c
+}
+
+func (s *Server) amendContract(w http.ResponseWriter, r *http.Request) {
+	var req metronome.AmendContractRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	c := s.findActiveContract(w, req.CustomerID, req.ContractID)
+	if c == nil {
+		return
+	}
+	start, err := parseTime(req.StartingAt)
+	if err != nil || start.IsZero() || !onHour(start) {
+		writeError(w, http.StatusBadRequest, "starting_at must be an RFC 3339 timestamp on an hour boundary")
+		return
+	}
+	if !s.addTerms(w, c, req.Commits, req.Overrides) {
+		return
+	}
+
+	writeJSON(w, dataID(newID()))
+}
+
+func (s *Server) updateContractEndDate(w http.ResponseWriter, r *http.Request) {
+	var req metronome.UpdateContractEndDateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	c := s.findActiveContract(w, req.CustomerID, req.ContractID)
+	if c == nil {
+		return
+	}
+	start, _ := parseTime(c.StartingAt)
+	end, err := parseTime(req.EndingBefore)
+	if err != nil || (!end.IsZero() && (!onHour(end) || !end.After(start))) {
+		writeError(w, http.StatusBadRequest, "ending_before must be an RFC 3339 timestamp on an hour boundary after starting_at")
+		return
+	}
+	c.EndingBefore = ""
+	if !end.IsZero() {
+		c.EndingBefore = formatTime(end)
+	}
+
+	writeJSON(w, dataID(c.ID))
+}
+
+func (s *Server) archiveContract(w http.ResponseWriter, r *http.Request) {
+	var req metronome.ArchiveContractRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	c := s.findActiveContract(w, req.CustomerID, req.ContractID)
+	if c == nil {
+		return
+	}
+	c.ArchivedAt = formatTime(s.now())
+
+	writeJSON(w, dataID(c.ID))
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronometest
+
+import (
+	"net/http"
+	"slices"
+
+	metronome "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+type customer struct {
+	data            metronome.GetCustomerData
+	billingProvider []metronome.CustomerBillingProviderConfiguration
+	archived        bool
+}
+
+func (s *Server) registerCustomers(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/customers", s.createCustomer)
+	mux.HandleFunc("GET /v1/customers", s.listCustomers)
+	mux.HandleFunc("GET /v1/customers/{id}", s.getCustomer)
+	mux.HandleFunc("POST /v1/customers/{id}/setIngestAliases", s.setIngestAliases)
+	mux.HandleFunc("POST /v1/customers/{id}/setName", s.setCustomerName)
+	mux.HandleFunc("POST /v1/customers/archive", s.archiveCustomer)
+	mux.HandleFunc("POST /v1/getCustomerBillingProviderConfigurations", s.getBillingProviderConfigurations)
+	mux.HandleFunc("POST /v1/setCustomerBillingProviderConfigurations", s.setBillingProviderConfigurations)
+}
+
+// findCustomer returns the customer with the given ID. Archived customers
+// are reported as not found.
+func (s *Server) findCustomer(id string) *customer {
+	for _, c := range s.customers {
+		if c.data.ID == id && !c.archived {
+			return c
+		}
+	}
+	return nil
+}
+
+// ingestAliasInUse reports whether another active customer already has one of
+// the given ingest aliases.
+func (s *Server) ingestAliasInUse(aliases []string, except string) (string, bool) {
+	for _, c := range s.customers {
+		if c.archived || c.data.ID == except {
+			continue
+		}
+		for _, a := range aliases {
+			if slices.Contains(c.data.IngestAliases, a) {
+				return a, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (s *Server) createCustomer(w http.ResponseWriter, r *http.Request) {
+	var req metronome.CreateCustomerRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if alias, ok := s.ingestAliasInUse(req.IngestAliases, ""); ok {
+		writeError(w, http.StatusConflict, "Ingest alias %s is already in use", alias)
+		return
+	}
+	if !s.checkCustomFields(w, metronome.EntityCustomer, req.CustomFields) {
+		return
+	}
+
+	c := &customer{data: metronome.GetCustomerData{
+		ID:            newID(),
+		IngestAliases: req.IngestAliases,
+		Name:          req.Name,
+		CustomFields:  req.CustomFields,
+	}}
+	for _, bp := range req.BillingProviderConfigurations {
+		c.billingProvider = append(c.billingProvider, metronome.CustomerBillingProviderConfiguration{
+			ID:              newID(),
+			CustomerID:      c.data.ID,
+			BillingProvider: bp.BillingProvider,
+			DeliveryMethod:  bp.DeliveryMethod,
+			Configuration:   bp.Configuration,
+		})
+	}
+	if len(req.IngestAliases) > 0 {
+		c.data.ExternalID = req.IngestAliases[0]
+	}
+	s.customers = append(s.customers, c)
+
+	writeJSON(w, metronome.CreateCustomerResponse{Data: metronome.CreateCustomerData{
+		ID:            c.data.ID,
+		ExternalID:    c.data.ExternalID,
+		IngestAliases: c.data.IngestAliases,
+		Name:          c.data.Name,
+	}})
+}
+
+func (s *Server) getCustomer(w http.ResponseWriter, r *http.Request) {
+	c := s.findCustomer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Customer not found")
+		return
+	}
+
+	writeJSON(w, metronome.GetCustomerResponse{Data: c.data})
+}
+
+func (s *Server) listCustomers(w http.ResponseWriter, r *http.Request) {
+	onlyArchived := r.URL.Query().Get("only_archived") == "true"
+
+	var matched []metronome.GetCustomerData
+	for _, c := range s.customers {
+		if c.archived == onlyArchived {
+			matched = append(matched, c.data)
+		}
+	}
+
+	items, next, err := page(s, r, matched)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, metronome.ListCustomersResponse{Data: items, NextPage: nullablePage(next)})
+}
+
+func (s *Server) setIngestAliases(w http.ResponseWriter, r *http.Request) {
+	var req metronome.UpdateAliasesRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	c := s.findCustomer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Customer not found")
+		return
+	}
+	if alias, ok := s.ingestAliasInUse(req.IngestAliases, c.data.ID); ok {
+		writeError(w, http.StatusConflict, "Ingest alias %s is already in use", alias)
+		return
+	}
+	c.data.IngestAliases = req.IngestAliases
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) setCustomerName(w http.ResponseWriter, r *http.Request) {
+	var req metronome.UpdateNameRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	c := s.findCustomer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Customer not found")
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	c.data.Name = req.Name
+
+	writeJSON(w, metronome.GetCustomerResponse{Data: c.data})
+}
+
+func (s *Server) archiveCustomer(w http.ResponseWriter, r *http.Request) {
+	var req metronome.ArchiveCustomerRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	i := slices.IndexFunc(s.customers, func(c *customer) bool { return c.data.ID == req.ID })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Customer not found")
+		return
+	}
+	if s.customers[i].archived {
+		writeError(w, http.StatusBadRequest, "Customer already archived")
+		return
+	}
+	s.customers[i].archived = true
+
+	writeJSON(w, dataID(req.ID))
+}
+
+func (s *Server) getBillingProviderConfigurations(w http.ResponseWriter, r *http.Request) {
+	var req metronome.GetBillingProviderConfigurationsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	c := s.findCustomer(req.CustomerID)
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Customer not found")
+		return
+	}
+
+	writeJSON(w, metronome.GetBillingProviderConfigurationsResponse{Data: c.billingProvider})
+}
+
+// setBillingProviderConfigurations adds the configurations, replacing any
+// existing configuration of a customer for the same billing provider and
+// delivery method.
+func (s *Server) setBillingProviderConfigurations(w http.ResponseWriter, r *http.Request) {
+	var req metronome.SetBillingProviderConfigurationsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	for _, bp := range req.Data {
+		c := s.findCustomer(bp.CustomerID)
+		if c == nil {
+			writeError(w, http.StatusBadRequest, "Customer %s not found", bp.CustomerID)
+			return
+		}
+		if bp.BillingProvider == "" || bp.DeliveryMethod == "" {
+			writeError(w, http.StatusBadRequest, "billing_provider and delivery_method are required")
+			return
+		}
+	}
+	for _, bp := range req.Data {
+		c := s.findCustomer(bp.CustomerID)
+		c.billingProvider = slices.DeleteFunc(c.billingProvider, func(e metronome.CustomerBillingProviderConfiguration) bool {
+			return e.BillingProvider == bp.BillingProvider && e.DeliveryMethod == bp.DeliveryMethod
+		})
+		bp.ID = newID()
+		c.billingProvider = append(c.billingProvider, bp)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
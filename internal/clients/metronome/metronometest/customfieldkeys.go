@@ -0,0 +1,198 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronometest
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	metronome "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+var customFieldEntities = []string{
+	"alert",
+	"billable_metric",
+	"charge",
+	"commit",
+	"contract",
+	"contract_credit",
+	"contract_product",
+	"credit_grant",
+	"customer",
+	"customer_plan",
+	"invoice",
+	"plan",
+	"product",
+	"professional_service",
+	"rate_card",
+	"scheduled_charge",
+	"subscription",
+}
+
+func (s *Server) registerCustomFieldKeys(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/customFields/addKey", s.addCustomFieldKey)
+	mux.HandleFunc("POST /v1/customFields/listKeys", s.listCustomFieldKeys)
+	mux.HandleFunc("POST /v1/customFields/removeKey", s.removeCustomFieldKey)
+	mux.HandleFunc("POST /v1/customFields/setValues", s.setCustomFieldValues)
+	mux.HandleFunc("POST /v1/customFields/deleteValues", s.deleteCustomFieldValues)
+}
+
+func (s *Server) findCustomFieldKey(entity, key string) int {
+	return slices.IndexFunc(s.customFieldKeys, func(k metronome.CustomFieldKey) bool {
+		return k.Entity == entity && k.Key == key
+	})
+}
+
+// checkCustomFields responds with a validation error if any of the custom
+// fields doesn't have a key for the entity.
+func (s *Server) checkCustomFields(w http.ResponseWriter, entity string, fields map[string]string) bool {
+	for k := range fields {
+		if s.findCustomFieldKey(entity, k) < 0 {
+			writeError(w, http.StatusBadRequest, "Custom field key %s does not exist for entity %s", k, entity)
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) addCustomFieldKey(w http.ResponseWriter, r *http.Request) {
+	var req metronome.CreateCustomFieldKeyRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if !slices.Contains(customFieldEntities, req.Entity) {
+		writeError(w, http.StatusBadRequest, "entity must be one of %s", strings.Join(customFieldEntities, ", "))
+		return
+	}
+	if req.Key == "" {
+		writeError(w, http.StatusBadRequest, "key is required")
+		return
+	}
+	if s.findCustomFieldKey(req.Entity, req.Key) >= 0 {
+		writeError(w, http.StatusConflict, "Key %s already exists for entity %s", req.Key, req.Entity)
+		return
+	}
+	s.customFieldKeys = append(s.customFieldKeys, metronome.CustomFieldKey(req))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) listCustomFieldKeys(w http.ResponseWriter, r *http.Request) {
+	var req metronome.ListCustomFieldKeysRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	var matched []metronome.CustomFieldKey
+	for _, k := range s.customFieldKeys {
+		if len(req.Entities) > 0 && !slices.Contains(req.Entities, k.Entity) {
+			continue
+		}
+		matched = append(matched, k)
+	}
+
+	items, next, err := page(s, r, matched)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, metronome.ListCustomFieldKeysResponse{Data: items, NextPage: next})
+}
+
+func (s *Server) removeCustomFieldKey(w http.ResponseWriter, r *http.Request) {
+	var req metronome.DeleteCustomFieldKeyRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	i := s.findCustomFieldKey(req.Entity, req.Key)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Key %s not found for entity %s", req.Key, req.Entity)
+		return
+	}
+	s.customFieldKeys = slices.Delete(s.customFieldKeys, i, i+1)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// customFieldsOf returns a pointer to the custom fields of an active object,
+// or nil if there is no such object.
+func (s *Server) customFieldsOf(entity, id string) *map[string]string {
+	switch entity {
+	case metronome.EntityCustomer:
+		if c := s.findCustomer(id); c != nil {
+			return &c.data.CustomFields
+		}
+	case metronome.EntityProduct:
+		if p := s.findProduct(id); p != nil && p.archivedAt.IsZero() {
+			return &p.customFields
+		}
+	case metronome.EntityRateCard:
+		if rc := s.findRateCard(id); rc != nil {
+			return &rc.card.CustomFields
+		}
+	case metronome.EntityBillableMetric:
+		if m := s.findBillableMetric(id); m != nil && m.archivedAt.IsZero() {
+			return &m.metric.CustomFields
+		}
+	}
+	return nil
+}
+
+func (s *Server) setCustomFieldValues(w http.ResponseWriter, r *http.Request) {
+	var req metronome.SetCustomFieldValuesRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	fields := s.customFieldsOf(req.Entity, req.EntityID)
+	if fields == nil {
+		writeError(w, http.StatusNotFound, "%s %s not found", req.Entity, req.EntityID)
+		return
+	}
+	if !s.checkCustomFields(w, req.Entity, req.CustomFields) {
+		return
+	}
+	if *fields == nil {
+		*fields = map[string]string{}
+	}
+	for k, v := range req.CustomFields {
+		(*fields)[k] = v
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deleteCustomFieldValues(w http.ResponseWriter, r *http.Request) {
+	var req metronome.DeleteCustomFieldValuesRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	fields := s.customFieldsOf(req.Entity, req.EntityID)
+	if fields == nil {
+		writeError(w, http.StatusNotFound, "%s %s not found", req.Entity, req.EntityID)
+		return
+	}
+	for _, k := range req.Keys {
+		delete(*fields, k)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronometest
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	metronome "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+const (
+	archiveFilterArchived    = "ARCHIVED"
+	archiveFilterNotArchived = "NOT_ARCHIVED"
+	archiveFilterAll         = "ALL"
+)
+
+var productTypes = []string{"USAGE", "SUBSCRIPTION", "COMPOSITE", "FIXED", "PRO_SERVICE"}
+
+type product struct {
+	id           string
+	typ          string
+	initial      metronome.ProductDetails
+	updates      []metronome.ProductDetails
+	customFields map[string]string
+	archivedAt   time.Time
+}
+
+// view returns the product as Metronome reports it at the given time, with
+// every update that has started applied to its current details.
+func (p *product) view(now time.Time) metronome.Product {
+	updates := slices.Clone(p.updates)
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].StartingAt < updates[j].StartingAt
+	})
+
+	current := p.initial
+	for _, u := range updates {
+		if t, _ := parseTime(u.StartingAt); t.After(now) {
+			break
+		}
+		current = applyProductUpdate(current, u)
+	}
+
+	out := metronome.Product{
+		ID:           p.id,
+		Type:         p.typ,
+		Initial:      p.initial,
+		Current:      current,
+		Updates:      p.updates,
+		CustomFields: p.customFields,
+	}
+	if !p.archivedAt.IsZero() {
+		out.ArchivedAt = formatTime(p.archivedAt)
+	}
+	return out
+}
+
+// applyProductUpdate overlays the fields set in an update onto the details
+// of a product.
+func applyProductUpdate(d, u metronome.ProductDetails) metronome.ProductDetails {
+	if u.Name != "" {
+		d.Name = u.Name
+	}
+	if u.BillableMetricID != "" {
+		d.BillableMetricID = u.BillableMetricID
+	}
+	if u.CompositeProductIDs != nil {
+		d.CompositeProductIDs = u.CompositeProductIDs
+	}
+	if u.CompositeTags != nil {
+		d.CompositeTags = u.CompositeTags
+	}
+	if u.ExcludeFreeUsage {
+		d.ExcludeFreeUsage = u.ExcludeFreeUsage
+	}
+	if u.PresentationGroupKey != nil {
+		d.PresentationGroupKey = u.PresentationGroupKey
+	}
+	if u.PricingGroupKey != nil {
+		d.PricingGroupKey = u.PricingGroupKey
+	}
+	if u.QuantityConversion != nil {
+		d.QuantityConversion = u.QuantityConversion
+	}
+	if u.QuantityRounding != nil {
+		d.QuantityRounding = u.QuantityRounding
+	}
+	if u.Tags != nil {
+		d.Tags = u.Tags
+	}
+	d.StartingAt = u.StartingAt
+	d.CreatedAt = u.CreatedAt
+	d.CreatedBy = u.CreatedBy
+	return d
+}
+
+func (s *Server) registerProducts(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/contract-pricing/products/create", s.createProduct)
+	mux.HandleFunc("POST /v1/contract-pricing/products/get", s.getProduct)
+	mux.HandleFunc("POST /v1/contract-pricing/products/list", s.listProducts)
+	mux.HandleFunc("POST /v1/contract-pricing/products/update", s.updateProduct)
+	mux.HandleFunc("POST /v1/contract-pricing/products/archive", s.archiveProduct)
+}
+
+func (s *Server) findProduct(id string) *product {
+	for _, p := range s.products {
+		if p.id == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *Server) createProduct(w http.ResponseWriter, r *http.Request) {
+	var req metronome.CreateProductRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	typ := strings.ToUpper(req.Type)
+	if !slices.Contains(productTypes, typ) {
+		writeError(w, http.StatusBadRequest, "type must be one of %s", strings.Join(productTypes, ", "))
+		return
+	}
+	if typ == "USAGE" {
+		if req.BillableMetricID == "" {
+			writeError(w, http.StatusBadRequest, "billable_metric_id is required for usage products")
+			return
+		}
+		if s.findBillableMetric(req.BillableMetricID) == nil {
+			writeError(w, http.StatusBadRequest, "Billable metric %s not found", req.BillableMetricID)
+			return
+		}
+	}
+
+	if !s.checkCustomFields(w, metronome.EntityProduct, req.CustomFields) {
+		return
+	}
+
+	now := formatTime(s.now())
+	p := &product{
+		id:           newID(),
+		typ:          typ,
+		customFields: req.CustomFields,
+		initial: metronome.ProductDetails{
+			Name:                 req.Name,
+			BillableMetricID:     req.BillableMetricID,
+			CompositeProductIDs:  req.CompositeProductIDs,
+			CompositeTags:        req.CompositeTags,
+			ExcludeFreeUsage:     req.ExcludeFreeUsage,
+			PresentationGroupKey: req.PresentationGroupKey,
+			PricingGroupKey:      req.PricingGroupKey,
+			QuantityConversion:   req.QuantityConversion,
+			QuantityRounding:     req.QuantityRounding,
+			Tags:                 req.Tags,
+			StartingAt:           now,
+			CreatedAt:            now,
+		},
+	}
+	s.products = append(s.products, p)
+
+	writeJSON(w, dataID(p.id))
+}
+
+func (s *Server) getProduct(w http.ResponseWriter, r *http.Request) {
+	var req metronome.GetProductRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p := s.findProduct(req.ID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "Product not found")
+		return
+	}
+
+	writeJSON(w, metronome.GetProductResponse{Data: p.view(s.now())})
+}
+
+func (s *Server) listProducts(w http.ResponseWriter, r *http.Request) {
+	var req metronome.ListProductsRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	filter := req.ArchiveFilter
+	if filter == "" {
+		filter = archiveFilterNotArchived
+	}
+	if filter != archiveFilterArchived && filter != archiveFilterNotArchived && filter != archiveFilterAll {
+		writeError(w, http.StatusBadRequest, "invalid archive_filter %q", req.ArchiveFilter)
+		return
+	}
+
+	now := s.now()
+	var matched []metronome.Product
+	for _, p := range s.products {
+		archived := !p.archivedAt.IsZero()
+		if (filter == archiveFilterArchived && !archived) || (filter == archiveFilterNotArchived && archived) {
+			continue
+		}
+		matched = append(matched, p.view(now))
+	}
+
+	items, next, err := page(s, r, matched)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, metronome.ListProductsResponse{Data: items, NextPage: next})
+}
+
+func (s *Server) updateProduct(w http.ResponseWriter, r *http.Request) {
+	var req metronome.UpdateProductRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p := s.findProduct(req.ProductID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "Product not found")
+		return
+	}
+	if !p.archivedAt.IsZero() {
+		writeError(w, http.StatusBadRequest, "Cannot update an archived product")
+		return
+	}
+	startingAt, err := parseTime(req.StartingAt)
+	if err != nil || startingAt.IsZero() {
+		writeError(w, http.StatusBadRequest, "starting_at must be an RFC 3339 timestamp")
+		return
+	}
+	if req.BillableMetricID != "" && s.findBillableMetric(req.BillableMetricID) == nil {
+		writeError(w, http.StatusBadRequest, "Billable metric %s not found", req.BillableMetricID)
+		return
+	}
+
+	p.updates = append(p.updates, metronome.ProductDetails{
+		Name:                 req.Name,
+		BillableMetricID:     req.BillableMetricID,
+		CompositeProductIDs:  req.CompositeProductIDs,
+		CompositeTags:        req.CompositeTags,
+		ExcludeFreeUsage:     req.ExcludeFreeUsage,
+		PresentationGroupKey: req.PresentationGroupKey,
+		PricingGroupKey:      req.PricingGroupKey,
+		QuantityConversion:   req.QuantityConversion,
+		QuantityRounding:     req.QuantityRounding,
+		Tags:                 req.Tags,
+		StartingAt:           formatTime(startingAt),
+		CreatedAt:            formatTime(s.now()),
+	})
+
+	writeJSON(w, dataID(p.id))
+}
+
+func (s *Server) archiveProduct(w http.ResponseWriter, r *http.Request) {
+	var req metronome.ArchiveProductRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	p := s.findProduct(req.ProductID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "Product not found")
+		return
+	}
+	if !p.archivedAt.IsZero() {
+		writeError(w, http.StatusBadRequest, "Product already archived")
+		return
+	}
+	p.archivedAt = s.now()
+
+	writeJSON(w, dataID(p.id))
+}
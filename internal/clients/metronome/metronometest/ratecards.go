@@ -0,0 +1,366 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronometest
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	metronome "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+var rateTypes = []string{"FLAT", "PERCENTAGE", "SUBSCRIPTION", "TIERED", "CUSTOM"}
+
+type rateCard struct {
+	card     metronome.RateCard
+	rates    []metronome.Rate
+	archived bool
+}
+
+func (s *Server) registerRateCards(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/contract-pricing/rate-cards/create", s.createRateCard)
+	mux.HandleFunc("POST /v1/contract-pricing/rate-cards/get", s.getRateCard)
+	mux.HandleFunc("POST /v1/contract-pricing/rate-cards/list", s.listRateCards)
+	mux.HandleFunc("POST /v1/contract-pricing/rate-cards/update", s.updateRateCard)
+	mux.HandleFunc("POST /v1/contract-pricing/rate-cards/archive", s.archiveRateCard)
+	mux.HandleFunc("POST /v1/contract-pricing/rate-cards/getRates", s.getRates)
+	mux.HandleFunc("POST /v1/contract-pricing/rate-cards/addRate", s.addRate)
+	mux.HandleFunc("POST /v1/contract-pricing/rate-cards/updateRateEndDate", s.updateRateEndDate)
+}
+
+// findRateCard returns the rate card with the given ID. Archived rate cards
+// are reported as not found.
+func (s *Server) findRateCard(id string) *rateCard {
+	for _, rc := range s.rateCards {
+		if rc.card.ID == id && !rc.archived {
+			return rc
+		}
+	}
+	return nil
+}
+
+// aliasInUse reports whether another active rate card already has one of the
+// given aliases.
+func (s *Server) aliasInUse(aliases []metronome.RateCardAlias, except string) (string, bool) {
+	for _, rc := range s.rateCards {
+		if rc.archived || rc.card.ID == except {
+			continue
+		}
+		for _, a := range aliases {
+			if slices.Contains(rc.card.Aliases, a) {
+				return a.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (s *Server) createRateCard(w http.ResponseWriter, r *http.Request) {
+	var req metronome.CreateRateCardRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if alias, ok := s.aliasInUse(req.Aliases, ""); ok {
+		writeError(w, http.StatusConflict, "Alias %s is already in use", alias)
+		return
+	}
+
+	if !s.checkCustomFields(w, metronome.EntityRateCard, req.CustomFields) {
+		return
+	}
+
+	fiat := metronome.FiatCreditType{ID: USDCreditTypeID, Name: "USD (cents)"}
+	if req.FiatCreditTypeID != "" && req.FiatCreditTypeID != USDCreditTypeID {
+		fiat = metronome.FiatCreditType{ID: req.FiatCreditTypeID, Name: req.FiatCreditTypeID}
+	}
+
+	rc := &rateCard{card: metronome.RateCard{
+		ID:             newID(),
+		Name:           req.Name,
+		Description:    req.Description,
+		FiatCreditType: fiat,
+		CreatedAt:      formatTime(s.now()),
+		Aliases:        req.Aliases,
+		CustomFields:   req.CustomFields,
+	}}
+	s.rateCards = append(s.rateCards, rc)
+
+	writeJSON(w, dataID(rc.card.ID))
+}
+
+func (s *Server) getRateCard(w http.ResponseWriter, r *http.Request) {
+	var req metronome.GetRateCardRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	rc := s.findRateCard(req.ID)
+	if rc == nil {
+		writeError(w, http.StatusNotFound, "Rate card not found")
+		return
+	}
+
+	writeJSON(w, metronome.GetRateCardResponse{Data: rc.card})
+}
+
+func (s *Server) listRateCards(w http.ResponseWriter, r *http.Request) {
+	var matched []metronome.RateCard
+	for _, rc := range s.rateCards {
+		if !rc.archived {
+			matched = append(matched, rc.card)
+		}
+	}
+
+	items, next, err := page(s, r, matched)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, metronome.ListRateCardsResponse{Data: items, NextPage: nullablePage(next)})
+}
+
+func (s *Server) updateRateCard(w http.ResponseWriter, r *http.Request) {
+	var req metronome.UpdateRateCardRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	rc := s.findRateCard(req.RateCardID)
+	if rc == nil {
+		writeError(w, http.StatusNotFound, "Rate card not found")
+		return
+	}
+	if alias, ok := s.aliasInUse(req.Aliases, rc.card.ID); ok {
+		writeError(w, http.StatusConflict, "Alias %s is already in use", alias)
+		return
+	}
+	if req.Name != "" {
+		rc.card.Name = req.Name
+	}
+	rc.card.Description = req.Description
+	if req.Aliases != nil {
+		rc.card.Aliases = req.Aliases
+	}
+
+	writeJSON(w, dataID(rc.card.ID))
+}
+
+func (s *Server) archiveRateCard(w http.ResponseWriter, r *http.Request) {
+	var req metronome.ArchiveRateCardRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	rc := s.findRateCard(req.Data.ID)
+	if rc == nil {
+		writeError(w, http.StatusNotFound, "Rate card not found")
+		return
+	}
+	rc.archived = true
+
+	writeJSON(w, dataID(rc.card.ID))
+}
+
+func (s *Server) getRates(w http.ResponseWriter, r *http.Request) {
+	var req metronome.GetRatesRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	rc := s.findRateCard(req.RateCardID)
+	if rc == nil {
+		writeError(w, http.StatusNotFound, "Rate card not found")
+		return
+	}
+	at, err := parseTime(req.At)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "at must be an RFC 3339 timestamp")
+		return
+	}
+	if at.IsZero() {
+		at = s.now()
+	}
+
+	var matched []metronome.Rate
+	for _, rate := range rc.rates {
+		if !rateActive(rate, at) {
+			continue
+		}
+		if len(req.Selectors) > 0 && !slices.ContainsFunc(req.Selectors, func(sel metronome.RateSelector) bool {
+			return rateSelected(rate, sel)
+		}) {
+			continue
+		}
+		matched = append(matched, rate)
+	}
+
+	items, next, err := page(s, r, matched)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, metronome.GetRatesResponse{Data: items, NextPage: next})
+}
+
+func rateActive(rate metronome.Rate, at time.Time) bool {
+	start, _ := parseTime(rate.StartingAt)
+	end, _ := parseTime(rate.EndingBefore)
+	return !start.After(at) && (end.IsZero() || at.Before(end))
+}
+
+func rateSelected(rate metronome.Rate, sel metronome.RateSelector) bool {
+	if sel.ProductID != "" && sel.ProductID != rate.ProductID {
+		return false
+	}
+	if sel.PricingGroupValues != nil && !maps.Equal(sel.PricingGroupValues, rate.PricingGroupValues) {
+		return false
+	}
+	for k, v := range sel.PartialPricingGroupValues {
+		if rate.PricingGroupValues[k] != v {
+			return false
+		}
+	}
+	if len(sel.ProductTags) > 0 && !slices.ContainsFunc(sel.ProductTags, func(t string) bool {
+		return slices.Contains(rate.ProductTags, t)
+	}) {
+		return false
+	}
+	return true
+}
+
+func (s *Server) addRate(w http.ResponseWriter, r *http.Request) {
+	var req metronome.AddRateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	rc := s.findRateCard(req.RateCardID)
+	if rc == nil {
+		writeError(w, http.StatusNotFound, "Rate card not found")
+		return
+	}
+	p := s.findProduct(req.ProductID)
+	if p == nil || !p.archivedAt.IsZero() {
+		writeError(w, http.StatusBadRequest, "Product %s not found", req.ProductID)
+		return
+	}
+	rateType := strings.ToUpper(req.RateType)
+	if !slices.Contains(rateTypes, rateType) {
+		writeError(w, http.StatusBadRequest, "rate_type must be one of %s", strings.Join(rateTypes, ", "))
+		return
+	}
+	if rateType == "TIERED" && len(req.Tiers) == 0 {
+		writeError(w, http.StatusBadRequest, "tiers are required for tiered rates")
+		return
+	}
+	start, err := parseTime(req.StartingAt)
+	if err != nil || start.IsZero() || !onHour(start) {
+		writeError(w, http.StatusBadRequest, "starting_at must be an RFC 3339 timestamp on an hour boundary")
+		return
+	}
+	end, err := parseTime(req.EndingBefore)
+	if err != nil || (!end.IsZero() && (!onHour(end) || !end.After(start))) {
+		writeError(w, http.StatusBadRequest, "ending_before must be an RFC 3339 timestamp on an hour boundary after starting_at")
+		return
+	}
+
+	creditType := metronome.CreditType{ID: rc.card.FiatCreditType.ID, Name: rc.card.FiatCreditType.Name}
+	if req.CreditTypeID != "" && req.CreditTypeID != creditType.ID {
+		creditType = metronome.CreditType{ID: req.CreditTypeID, Name: req.CreditTypeID}
+	}
+
+	current := p.view(s.now()).Current
+	rate := metronome.Rate{
+		CommitRate: req.CommitRate,
+		Details: metronome.RateDetails{
+			CreditType:         creditType,
+			IsProrated:         req.IsProrated,
+			Price:              req.Price,
+			PricingGroupValues: req.PricingGroupValues,
+			Quantity:           req.Quantity,
+			RateType:           rateType,
+			Tiers:              req.Tiers,
+			UseListPrices:      req.UseListPrices,
+		},
+		Entitled:           req.Entitled,
+		PricingGroupValues: req.PricingGroupValues,
+		ProductCustomField: p.customFields,
+		ProductID:          p.id,
+		ProductName:        current.Name,
+		ProductTags:        current.Tags,
+		StartingAt:         formatTime(start),
+	}
+	if !end.IsZero() {
+		rate.EndingBefore = formatTime(end)
+	}
+	rc.rates = append(rc.rates, rate)
+
+	res := metronome.AddRateResponse{}
+	res.Data.RateType = rateType
+	res.Data.Price = req.Price
+	writeJSON(w, res)
+}
+
+func (s *Server) updateRateEndDate(w http.ResponseWriter, r *http.Request) {
+	var req metronome.UpdateRateEndDateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	rc := s.findRateCard(req.RateCardID)
+	if rc == nil {
+		writeError(w, http.StatusNotFound, "Rate card not found")
+		return
+	}
+	start, err := parseTime(req.StartingAt)
+	if err != nil || start.IsZero() {
+		writeError(w, http.StatusBadRequest, "starting_at must be an RFC 3339 timestamp")
+		return
+	}
+	// a missing ending_before removes the end date
+	var end time.Time
+	if req.EndingBefore != "" {
+		end, err = parseTime(req.EndingBefore)
+		if err != nil || end.IsZero() || !onHour(end) || !end.After(start) {
+			writeError(w, http.StatusBadRequest, "ending_before must be an RFC 3339 timestamp on an hour boundary after starting_at")
+			return
+		}
+	}
+
+	for i, rate := range rc.rates {
+		rateStart, _ := parseTime(rate.StartingAt)
+		if rate.ProductID != req.ProductID || !rateStart.Equal(start) || !maps.Equal(rate.PricingGroupValues, req.PricingGroupValues) {
+			continue
+		}
+		rc.rates[i].EndingBefore = ""
+		if !end.IsZero() {
+			rc.rates[i].EndingBefore = formatTime(end)
+		}
+		writeJSON(w, dataID(rc.card.ID))
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "Rate not found")
+}
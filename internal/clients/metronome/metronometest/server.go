@@ -0,0 +1,228 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metronometest provides an in-process fake of the Metronome API for
+// tests. The fake holds state, so a real metronome.Client can create, read,
+// update and archive objects against it and observe the results.
+package metronometest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/uuid"
+	"k8s.io/utils/clock"
+
+	metronome "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+const (
+	// DefaultAuthToken is the API key the server accepts unless configured
+	// otherwise.
+	DefaultAuthToken = "metronometest-token"
+
+	// DefaultPageSize is the number of items returned per page by list
+	// endpoints unless configured otherwise.
+	DefaultPageSize = 25
+
+	// USDCreditTypeID is the ID of the fiat credit type assigned to rate
+	// cards and rates that don't specify one.
+	USDCreditTypeID = "2714e483-4ff1-48e4-9e25-ac732e8f24f2"
+)
+
+// Server is a fake Metronome API backed by an httptest.Server. Its zero value
+// is not usable; create one with NewServer.
+type Server struct {
+	*httptest.Server
+
+	authToken string
+	pageSize  int
+	clock     clock.PassiveClock
+
+	mu              sync.Mutex
+	products        []*product
+	billableMetrics []*billableMetric
+	rateCards       []*rateCard
+	customFieldKeys []metronome.CustomFieldKey
+	customers       []*customer
+	contracts       []*metronome.Contract
+}
+
+// An Option configures a Server.
+type Option func(*Server)
+
+// WithAuthToken sets the API key the server accepts. Requests with any other
+// key are rejected as unauthorized.
+func WithAuthToken(token string) Option {
+	return func(s *Server) {
+		s.authToken = token
+	}
+}
+
+// WithPageSize sets the number of items returned per page by list endpoints.
+func WithPageSize(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.pageSize = n
+		}
+	}
+}
+
+// WithClock sets the clock used to timestamp objects and to decide which
+// scheduled product updates and rates are in effect.
+func WithClock(c clock.PassiveClock) Option {
+	return func(s *Server) {
+		s.clock = c
+	}
+}
+
+// NewServer starts a new fake Metronome API. Callers should call Close when
+// finished with it.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		authToken: DefaultAuthToken,
+		pageSize:  DefaultPageSize,
+		clock:     clock.RealClock{},
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	mux := http.NewServeMux()
+	s.registerProducts(mux)
+	s.registerBillableMetrics(mux)
+	s.registerRateCards(mux)
+	s.registerCustomFieldKeys(mux)
+	s.registerCustomers(mux)
+	s.registerContracts(mux)
+
+	s.Server = httptest.NewServer(s.authenticate(mux))
+	return s
+}
+
+// NewClient returns a metronome.Client configured to send requests to the
+// server with its accepted API key.
+func (s *Server) NewClient(opts ...metronome.Option) *metronome.Client {
+	c, _ := metronome.New(logging.NewNopLogger(), s.URL, s.authToken, opts...)
+	return c
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+s.authToken {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) now() time.Time {
+	return s.clock.Now().UTC()
+}
+
+func newID() string {
+	return uuid.NewString()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseTime parses an RFC 3339 timestamp sent by the client. An empty string
+// parses as the zero time.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func onHour(t time.Time) bool {
+	return t.Truncate(time.Hour).Equal(t)
+}
+
+// decode reads the JSON request body into v, responding with a validation
+// error if it can't be parsed.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: %s", err)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError responds in the same format Metronome uses for errors.
+func writeError(w http.ResponseWriter, code int, format string, args ...any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": fmt.Sprintf(format, args...)})
+}
+
+func dataID(id string) metronome.DataID {
+	return metronome.DataID{Data: metronome.IDOnly{ID: id}}
+}
+
+// page returns the page of items selected by the next_page and limit query
+// parameters of the request, and the cursor of the following page, which is
+// empty on the last page.
+func page[T any](s *Server, r *http.Request, items []T) ([]T, string, error) {
+	size := s.pageSize
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 {
+			return nil, "", fmt.Errorf("invalid limit %q", l)
+		}
+		size = n
+	}
+
+	start := 0
+	if c := r.URL.Query().Get("next_page"); c != "" {
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 0 || n > len(items) {
+			return nil, "", fmt.Errorf("invalid next_page %q", c)
+		}
+		start = n
+	}
+
+	end := min(start+size, len(items))
+	next := ""
+	if end < len(items) {
+		next = strconv.Itoa(end)
+	}
+	return items[start:end], next, nil
+}
+
+func nullablePage(next string) *string {
+	if next == "" {
+		return nil
+	}
+	return &next
+}
@@ -0,0 +1,408 @@
+package metronometest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	metronome "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestServer(t *testing.T, opts ...Option) (*Server, *metronome.Client) {
+	t.Helper()
+	s := NewServer(append([]Option{WithClock(clocktesting.NewFakePassiveClock(now))}, opts...)...)
+	t.Cleanup(s.Close)
+	return s, s.NewClient(metronome.WithMaxAttempts(1))
+}
+
+func TestUnauthorized(t *testing.T) {
+	s := NewServer(WithAuthToken("secret"))
+	defer s.Close()
+
+	c, _ := metronome.New(logging.NewNopLogger(), s.URL, "wrong")
+	_, err := c.Product().ListProduct(context.Background(), metronome.ListProductsRequest{}, "")
+	if !errors.Is(err, metronome.ErrUnauthorized) {
+		t.Errorf("ListProduct(...): want ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestProducts(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t, WithPageSize(2))
+	bm, pc := c.BillableMetric(), c.Product()
+
+	if _, err := pc.CreateProduct(ctx, metronome.CreateProductRequest{Name: "usage", Type: "USAGE"}); !errors.Is(err, metronome.ErrValidation) {
+		t.Fatalf("CreateProduct(...): want ErrValidation without billable metric, got %v", err)
+	}
+
+	metric, err := bm.CreateBillableMetric(ctx, metronome.CreateBillableMetricRequest{Name: "calls", AggregationType: metronome.AggregationCount})
+	if err != nil {
+		t.Fatalf("CreateBillableMetric(...): %v", err)
+	}
+
+	var ids []string
+	for _, name := range []string{"a", "b", "c"} {
+		res, err := pc.CreateProduct(ctx, metronome.CreateProductRequest{Name: name, Type: "USAGE", BillableMetricID: metric.Data.ID})
+		if err != nil {
+			t.Fatalf("CreateProduct(...): %v", err)
+		}
+		ids = append(ids, res.Data.ID)
+	}
+
+	// a future update is listed but not yet current
+	if _, err := pc.UpdateProduct(ctx, metronome.UpdateProductRequest{ProductID: ids[0], StartingAt: now.Add(time.Hour).Format(time.RFC3339), Name: "later"}); err != nil {
+		t.Fatalf("UpdateProduct(...): %v", err)
+	}
+	if _, err := pc.UpdateProduct(ctx, metronome.UpdateProductRequest{ProductID: ids[0], StartingAt: now.Format(time.RFC3339), Tags: []string{"x"}}); err != nil {
+		t.Fatalf("UpdateProduct(...): %v", err)
+	}
+	got, err := pc.GetProduct(ctx, metronome.GetProductRequest{ID: ids[0]})
+	if err != nil {
+		t.Fatalf("GetProduct(...): %v", err)
+	}
+	if diff := cmp.Diff("a", got.Data.Current.Name); diff != "" {
+		t.Errorf("Current.Name: -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"x"}, got.Data.Current.Tags); diff != "" {
+		t.Errorf("Current.Tags: -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(2, len(got.Data.Updates)); diff != "" {
+		t.Errorf("len(Updates): -want, +got: %s", diff)
+	}
+
+	if _, err := pc.ArchiveProduct(ctx, metronome.ArchiveProductRequest{ProductID: ids[1]}); err != nil {
+		t.Fatalf("ArchiveProduct(...): %v", err)
+	}
+	if _, err := pc.ArchiveProduct(ctx, metronome.ArchiveProductRequest{ProductID: ids[1]}); !errors.Is(err, metronome.ErrConflict) {
+		t.Errorf("ArchiveProduct(...): want ErrConflict when already archived, got %v", err)
+	}
+
+	var listed []string
+	for p, err := range metronome.AllProducts(ctx, pc, metronome.ListProductsRequest{}) {
+		if err != nil {
+			t.Fatalf("AllProducts(...): %v", err)
+		}
+		listed = append(listed, p.ID)
+	}
+	if diff := cmp.Diff([]string{ids[0], ids[2]}, listed); diff != "" {
+		t.Errorf("AllProducts(...): -want, +got: %s", diff)
+	}
+
+	listed = nil
+	for p, err := range metronome.AllProducts(ctx, pc, metronome.ListProductsRequest{ArchiveFilter: "ALL"}) {
+		if err != nil {
+			t.Fatalf("AllProducts(...): %v", err)
+		}
+		listed = append(listed, p.ID)
+	}
+	if diff := cmp.Diff(ids, listed); diff != "" {
+		t.Errorf("AllProducts(ALL): -want, +got: %s", diff)
+	}
+
+	if _, err := pc.GetProduct(ctx, metronome.GetProductRequest{ID: "3f3c2a5e-8f0c-4a53-9a0b-0a1d2e3f4a5b"}); !errors.Is(err, metronome.ErrNotFound) {
+		t.Errorf("GetProduct(...): want ErrNotFound, got %v", err)
+	}
+}
+
+func TestBillableMetrics(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t, WithPageSize(1))
+	bm := c.BillableMetric()
+
+	if _, err := bm.CreateBillableMetric(ctx, metronome.CreateBillableMetricRequest{Name: "sum", AggregationType: metronome.AggregationSum}); !errors.Is(err, metronome.ErrValidation) {
+		t.Fatalf("CreateBillableMetric(...): want ErrValidation without aggregation key, got %v", err)
+	}
+
+	a, err := bm.CreateBillableMetric(ctx, metronome.CreateBillableMetricRequest{Name: "a", AggregationType: metronome.AggregationCount})
+	if err != nil {
+		t.Fatalf("CreateBillableMetric(...): %v", err)
+	}
+	b, err := bm.CreateBillableMetric(ctx, metronome.CreateBillableMetricRequest{Name: "b", AggregationType: metronome.AggregationSum, AggregationKey: "bytes"})
+	if err != nil {
+		t.Fatalf("CreateBillableMetric(...): %v", err)
+	}
+
+	if _, err := bm.UpdateBillableMetric(ctx, b.Data.ID, metronome.UpdateBillableMetricRequest{Name: "renamed"}); err != nil {
+		t.Fatalf("UpdateBillableMetric(...): %v", err)
+	}
+
+	var names []string
+	for m, err := range metronome.AllBillableMetrics(ctx, bm, metronome.ListBillableMetricsRequest{}) {
+		if err != nil {
+			t.Fatalf("AllBillableMetrics(...): %v", err)
+		}
+		names = append(names, m.Name)
+	}
+	if diff := cmp.Diff([]string{"a", "renamed"}, names); diff != "" {
+		t.Errorf("AllBillableMetrics(...): -want, +got: %s", diff)
+	}
+
+	if _, err := bm.ArchiveBillableMetric(ctx, a.Data.ID); err != nil {
+		t.Fatalf("ArchiveBillableMetric(...): %v", err)
+	}
+	if _, err := bm.ArchiveBillableMetric(ctx, a.Data.ID); !errors.Is(err, metronome.ErrConflict) {
+		t.Errorf("ArchiveBillableMetric(...): want ErrConflict when already archived, got %v", err)
+	}
+	got, err := bm.GetBillableMetric(ctx, a.Data.ID)
+	if err != nil {
+		t.Fatalf("GetBillableMetric(...): %v", err)
+	}
+	if diff := cmp.Diff(now.Format(time.RFC3339), got.Data.ArchivedAt); diff != "" {
+		t.Errorf("ArchivedAt: -want, +got: %s", diff)
+	}
+}
+
+func TestRateCards(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	rcc, rc := c.RateCard(), c.Rate()
+
+	card, err := rcc.CreateRateCard(ctx, metronome.CreateRateCardRequest{Name: "card", Aliases: []metronome.RateCardAlias{{Name: "default"}}})
+	if err != nil {
+		t.Fatalf("CreateRateCard(...): %v", err)
+	}
+	if _, err := rcc.CreateRateCard(ctx, metronome.CreateRateCardRequest{Name: "other", Aliases: []metronome.RateCardAlias{{Name: "default"}}}); !errors.Is(err, metronome.ErrConflict) {
+		t.Errorf("CreateRateCard(...): want ErrConflict for duplicate alias, got %v", err)
+	}
+
+	product, err := c.Product().CreateProduct(ctx, metronome.CreateProductRequest{Name: "seats", Type: "SUBSCRIPTION"})
+	if err != nil {
+		t.Fatalf("CreateProduct(...): %v", err)
+	}
+
+	add := metronome.AddRateRequest{
+		RateCardID: card.Data.ID,
+		ProductID:  product.Data.ID,
+		RateType:   "FLAT",
+		Price:      "100",
+		StartingAt: now.Add(-time.Hour).Format(time.RFC3339),
+	}
+	if _, err := rc.AddRate(ctx, add); err != nil {
+		t.Fatalf("AddRate(...): %v", err)
+	}
+
+	offHour := add
+	offHour.StartingAt = now.Add(time.Minute).Format(time.RFC3339)
+	if _, err := rc.AddRate(ctx, offHour); !errors.Is(err, metronome.ErrValidation) {
+		t.Errorf("AddRate(...): want ErrValidation for starting_at off the hour, got %v", err)
+	}
+
+	future := add
+	future.StartingAt = now.Add(2 * time.Hour).Format(time.RFC3339)
+	future.Price = "200"
+	if _, err := rc.AddRate(ctx, future); err != nil {
+		t.Fatalf("AddRate(...): %v", err)
+	}
+
+	rates, err := rc.GetRates(ctx, metronome.GetRatesRequest{RateCardID: card.Data.ID, Selectors: []metronome.RateSelector{{ProductID: product.Data.ID}}}, "")
+	if err != nil {
+		t.Fatalf("GetRates(...): %v", err)
+	}
+	if len(rates.Data) != 1 || rates.Data[0].Details.Price != "100" || rates.Data[0].ProductName != "seats" {
+		t.Errorf("GetRates(...): want only the current rate, got %+v", rates.Data)
+	}
+
+	if _, err := rcc.UpdateRateCard(ctx, metronome.UpdateRateCardRequest{RateCardID: card.Data.ID, Name: "renamed", Description: "d"}); err != nil {
+		t.Fatalf("UpdateRateCard(...): %v", err)
+	}
+	got, err := rcc.GetRateCard(ctx, metronome.GetRateCardRequest{ID: card.Data.ID})
+	if err != nil {
+		t.Fatalf("GetRateCard(...): %v", err)
+	}
+	if diff := cmp.Diff(USDCreditTypeID, got.Data.FiatCreditType.ID); diff != "" {
+		t.Errorf("FiatCreditType.ID: -want, +got: %s", diff)
+	}
+	if got.Data.Name != "renamed" || got.Data.Description != "d" {
+		t.Errorf("GetRateCard(...): want updated name and description, got %+v", got.Data)
+	}
+
+	archive := metronome.ArchiveRateCardRequest{Data: metronome.IDOnly{ID: card.Data.ID}}
+	if _, err := rcc.ArchiveRateCard(ctx, archive); err != nil {
+		t.Fatalf("ArchiveRateCard(...): %v", err)
+	}
+	if _, err := rcc.GetRateCard(ctx, metronome.GetRateCardRequest{ID: card.Data.ID}); !errors.Is(err, metronome.ErrNotFound) {
+		t.Errorf("GetRateCard(...): want ErrNotFound once archived, got %v", err)
+	}
+}
+
+func TestCustomFieldKeys(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	cfk := c.CustomFieldKey()
+
+	if err := cfk.CreateCustomFieldKey(ctx, metronome.CreateCustomFieldKeyRequest{Entity: "nope", Key: "k"}); !errors.Is(err, metronome.ErrValidation) {
+		t.Errorf("CreateCustomFieldKey(...): want ErrValidation for unknown entity, got %v", err)
+	}
+	for _, k := range []metronome.CustomFieldKey{{Entity: "product", Key: "team"}, {Entity: "customer", Key: "tier", EnforceUniqueness: true}} {
+		if err := cfk.CreateCustomFieldKey(ctx, metronome.CreateCustomFieldKeyRequest(k)); err != nil {
+			t.Fatalf("CreateCustomFieldKey(...): %v", err)
+		}
+	}
+	if err := cfk.CreateCustomFieldKey(ctx, metronome.CreateCustomFieldKeyRequest{Entity: "product", Key: "team"}); !errors.Is(err, metronome.ErrConflict) {
+		t.Errorf("CreateCustomFieldKey(...): want ErrConflict for existing key, got %v", err)
+	}
+
+	res, err := cfk.ListCustomFieldKeys(ctx, metronome.ListCustomFieldKeysRequest{Entities: []string{"customer"}}, "")
+	if err != nil {
+		t.Fatalf("ListCustomFieldKeys(...): %v", err)
+	}
+	if diff := cmp.Diff([]metronome.CustomFieldKey{{Entity: "customer", Key: "tier", EnforceUniqueness: true}}, res.Data); diff != "" {
+		t.Errorf("ListCustomFieldKeys(...): -want, +got: %s", diff)
+	}
+
+	if err := cfk.DeleteCustomFieldKey(ctx, metronome.DeleteCustomFieldKeyRequest{Entity: "product", Key: "team"}); err != nil {
+		t.Fatalf("DeleteCustomFieldKey(...): %v", err)
+	}
+	if err := cfk.DeleteCustomFieldKey(ctx, metronome.DeleteCustomFieldKeyRequest{Entity: "product", Key: "team"}); !errors.Is(err, metronome.ErrNotFound) {
+		t.Errorf("DeleteCustomFieldKey(...): want ErrNotFound once deleted, got %v", err)
+	}
+
+	var ids []string
+	for _, tier := range []string{"gold", "silver"} {
+		cu, err := c.Customer().CreateCustomer(ctx, metronome.CreateCustomerRequest{Name: tier, CustomFields: map[string]string{"tier": tier}})
+		if err != nil {
+			t.Fatalf("CreateCustomer(...): %v", err)
+		}
+		ids = append(ids, cu.Data.ID)
+	}
+	values, err := c.CustomField().ListCustomFieldValues(ctx, metronome.EntityCustomer, "tier")
+	if err != nil {
+		t.Fatalf("ListCustomFieldValues(...): %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{ids[0]: "gold", ids[1]: "silver"}, values); diff != "" {
+		t.Errorf("ListCustomFieldValues(...): -want, +got: %s", diff)
+	}
+	if err := c.Customer().ArchiveCustomer(ctx, ids[1]); err != nil {
+		t.Fatalf("ArchiveCustomer(...): %v", err)
+	}
+	if _, err := c.CustomField().ListCustomFieldValues(ctx, metronome.EntityCustomer, "tier"); !errors.Is(err, metronome.ErrCustomFieldValuesArchived) {
+		t.Errorf("ListCustomFieldValues(...): want ErrCustomFieldValuesArchived with a value on an archived customer, got %v", err)
+	}
+}
+
+func TestCustomersAndContracts(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestServer(t, WithPageSize(1))
+	cc := c.Customer()
+
+	a, err := cc.CreateCustomer(ctx, metronome.CreateCustomerRequest{Name: "a", IngestAliases: []string{"a@example.com"}})
+	if err != nil {
+		t.Fatalf("CreateCustomer(...): %v", err)
+	}
+	b, err := cc.CreateCustomer(ctx, metronome.CreateCustomerRequest{Name: "b"})
+	if err != nil {
+		t.Fatalf("CreateCustomer(...): %v", err)
+	}
+	if err := cc.UpdateCustomerAliases(ctx, b.Data.ID, metronome.UpdateAliasesRequest{IngestAliases: []string{"a@example.com"}}); !errors.Is(err, metronome.ErrConflict) {
+		t.Errorf("UpdateCustomerAliases(...): want ErrConflict for alias in use, got %v", err)
+	}
+
+	var names []string
+	for cu, err := range metronome.AllCustomers(ctx, cc, metronome.ListCustomersRequest{}) {
+		if err != nil {
+			t.Fatalf("AllCustomers(...): %v", err)
+		}
+		names = append(names, cu.Name)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, names); diff != "" {
+		t.Errorf("AllCustomers(...): -want, +got: %s", diff)
+	}
+
+	id := s.AddContract(metronome.Contract{CustomerID: a.Data.ID, StartingAt: now.Format(time.RFC3339)})
+	got, err := c.Contract().GetContract(ctx, metronome.GetContractRequest{CustomerID: a.Data.ID, ContractID: id})
+	if err != nil {
+		t.Fatalf("GetContract(...): %v", err)
+	}
+	if diff := cmp.Diff(now.Format(time.RFC3339), got.Data.CreatedAt); diff != "" {
+		t.Errorf("CreatedAt: -want, +got: %s", diff)
+	}
+	if _, err := c.Contract().GetContract(ctx, metronome.GetContractRequest{CustomerID: b.Data.ID, ContractID: id}); !errors.Is(err, metronome.ErrNotFound) {
+		t.Errorf("GetContract(...): want ErrNotFound for another customer, got %v", err)
+	}
+	list, err := c.Contract().ListContracts(ctx, metronome.ListContractsRequest{CustomerID: b.Data.ID}, "")
+	if err != nil {
+		t.Fatalf("ListContracts(...): %v", err)
+	}
+	if len(list.Data) != 0 {
+		t.Errorf("ListContracts(...): want no contracts, got %d", len(list.Data))
+	}
+}
+
+func TestCustomerUpdates(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	cc := c.Customer()
+
+	if err := c.CustomFieldKey().CreateCustomFieldKey(ctx, metronome.CreateCustomFieldKeyRequest{Entity: metronome.EntityCustomer, Key: "tier"}); err != nil {
+		t.Fatalf("CreateCustomFieldKey(...): %v", err)
+	}
+	if _, err := cc.CreateCustomer(ctx, metronome.CreateCustomerRequest{Name: "a", CustomFields: map[string]string{"region": "us"}}); !errors.Is(err, metronome.ErrValidation) {
+		t.Errorf("CreateCustomer(...): want ErrValidation for unknown custom field key, got %v", err)
+	}
+	a, err := cc.CreateCustomer(ctx, metronome.CreateCustomerRequest{
+		Name:         "a",
+		CustomFields: map[string]string{"tier": "gold"},
+		BillingProviderConfigurations: []metronome.BillingProviderConfiguration{{
+			BillingProvider: "stripe",
+			DeliveryMethod:  "direct_to_billing_provider",
+			Configuration:   metronome.BillingConfiguration{StripeCustomerID: "cus_1"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("CreateCustomer(...): %v", err)
+	}
+	id := a.Data.ID
+
+	if err := cc.UpdateCustomerName(ctx, id, metronome.UpdateNameRequest{Name: "renamed"}); err != nil {
+		t.Fatalf("UpdateCustomerName(...): %v", err)
+	}
+	if err := c.CustomField().DeleteCustomFieldValues(ctx, metronome.DeleteCustomFieldValuesRequest{Entity: metronome.EntityCustomer, EntityID: id, Keys: []string{"tier"}}); err != nil {
+		t.Fatalf("DeleteCustomFieldValues(...): %v", err)
+	}
+	if err := c.CustomField().SetCustomFieldValues(ctx, metronome.SetCustomFieldValuesRequest{Entity: metronome.EntityCustomer, EntityID: id, CustomFields: map[string]string{"tier": "silver"}}); err != nil {
+		t.Fatalf("SetCustomFieldValues(...): %v", err)
+	}
+	if err := cc.SetBillingProviderConfigurations(ctx, metronome.SetBillingProviderConfigurationsRequest{Data: []metronome.CustomerBillingProviderConfiguration{{
+		CustomerID:      id,
+		BillingProvider: "stripe",
+		DeliveryMethod:  "direct_to_billing_provider",
+		Configuration:   metronome.BillingConfiguration{StripeCustomerID: "cus_2"},
+	}}}); err != nil {
+		t.Fatalf("SetBillingProviderConfigurations(...): %v", err)
+	}
+
+	got, err := cc.GetCustomer(ctx, id)
+	if err != nil {
+		t.Fatalf("GetCustomer(...): %v", err)
+	}
+	if got.Data.Name != "renamed" {
+		t.Errorf("GetCustomer(...): want name renamed, got %s", got.Data.Name)
+	}
+	if diff := cmp.Diff(map[string]string{"tier": "silver"}, got.Data.CustomFields); diff != "" {
+		t.Errorf("CustomFields: -want, +got: %s", diff)
+	}
+	bp, err := cc.GetBillingProviderConfigurations(ctx, id)
+	if err != nil {
+		t.Fatalf("GetBillingProviderConfigurations(...): %v", err)
+	}
+	if len(bp.Data) != 1 || bp.Data[0].Configuration.StripeCustomerID != "cus_2" {
+		t.Errorf("GetBillingProviderConfigurations(...): want the replaced stripe configuration, got %+v", bp.Data)
+	}
+
+	if err := cc.ArchiveCustomer(ctx, id); err != nil {
+		t.Fatalf("ArchiveCustomer(...): %v", err)
+	}
+	if err := cc.ArchiveCustomer(ctx, id); !errors.Is(err, metronome.ErrConflict) {
+		t.Errorf("ArchiveCustomer(...): want ErrConflict when already archived, got %v", err)
+	}
+	if _, err := cc.GetCustomer(ctx, id); !errors.Is(err, metronome.ErrNotFound) {
+		t.Errorf("GetCustomer(...): want ErrNotFound once archived, got %v", err)
+	}
+}
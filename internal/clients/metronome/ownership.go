@@ -0,0 +1,85 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronome
+
+import (
+	"context"
+	"maps"
+
+	"github.com/pkg/errors"
+)
+
+// OwnerCustomFieldKey is the custom field that marks Metronome objects with
+// the UID of the managed resource that created them, so that an object whose
+// ID was never recorded can be found and adopted instead of created again.
+// Objects are only marked when adoption is enabled.
+const OwnerCustomFieldKey = "crossplane_uid"
+
+// Custom field entities of the objects managed by the provider.
+const (
+	EntityBillableMetric = "billable_metric"
+	EntityCustomer       = "customer"
+	EntityProduct        = "contract_product"
+	EntityRateCard       = "rate_card"
+)
+
+// EnsureOwnerCustomFieldKey creates OwnerCustomFieldKey for the given entity
+// if it doesn't already exist. Metronome rejects custom fields whose key
+// hasn't been created.
+func EnsureOwnerCustomFieldKey(ctx context.Context, c CustomFieldKeyClient, entity string) error {
+	for k, err := range AllCustomFieldKeys(ctx, c, ListCustomFieldKeysRequest{Entities: []string{entity}}) {
+		if err != nil {
+			return err
+		}
+		if k.Key == OwnerCustomFieldKey {
+			return nil
+		}
+	}
+	err := c.CreateCustomFieldKey(ctx, CreateCustomFieldKeyRequest{Entity: entity, Key: OwnerCustomFieldKey})
+	if errors.Is(err, ErrConflict) {
+		// created concurrently by another reconcile
+		return nil
+	}
+	return err
+}
+
+// WithOwner returns a copy of the custom fields with the owner marker set to
+// the given UID.
+func WithOwner(customFields map[string]string, uid string) map[string]string {
+	out := maps.Clone(customFields)
+	if out == nil {
+		out = map[string]string{}
+	}
+	out[OwnerCustomFieldKey] = uid
+	return out
+}
+
+// WithoutOwner returns a copy of the custom fields without the owner marker.
+func WithoutOwner(customFields map[string]string) map[string]string {
+	if _, ok := customFields[OwnerCustomFieldKey]; !ok {
+		return customFields
+	}
+	out := maps.Clone(customFields)
+	delete(out, OwnerCustomFieldKey)
+	return out
+}
+
+// IsOwnedBy reports whether the custom fields mark an object as created by
+// the managed resource with the given UID.
+func IsOwnedBy(customFields map[string]string, uid string) bool {
+	return uid != "" && customFields[OwnerCustomFieldKey] == uid
+}
@@ -0,0 +1,72 @@
+package metronome
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Ownership(t *testing.T) {
+	type want struct {
+		with    map[string]string
+		without map[string]string
+		owned   bool
+	}
+	cases := map[string]struct {
+		customFields map[string]string
+		uid          string
+		want
+	}{
+		"NoCustomFields": {
+			uid: "uid",
+			want: want{
+				with:  map[string]string{OwnerCustomFieldKey: "uid"},
+				owned: false,
+			},
+		},
+		"OwnedBySameUID": {
+			customFields: map[string]string{"team": "billing", OwnerCustomFieldKey: "uid"},
+			uid:          "uid",
+			want: want{
+				with:    map[string]string{"team": "billing", OwnerCustomFieldKey: "uid"},
+				without: map[string]string{"team": "billing"},
+				owned:   true,
+			},
+		},
+		"OwnedByOtherUID": {
+			customFields: map[string]string{OwnerCustomFieldKey: "other"},
+			uid:          "uid",
+			want: want{
+				with:    map[string]string{OwnerCustomFieldKey: "uid"},
+				without: map[string]string{},
+				owned:   false,
+			},
+		},
+		"EmptyUIDNeverOwns": {
+			customFields: map[string]string{OwnerCustomFieldKey: ""},
+			want: want{
+				with:    map[string]string{OwnerCustomFieldKey: ""},
+				without: map[string]string{},
+				owned:   false,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			original := cmp.Diff(map[string]string(nil), tc.customFields)
+
+			if diff := cmp.Diff(tc.want.with, WithOwner(tc.customFields, tc.uid)); diff != "" {
+				t.Errorf("WithOwner(...): -want, +got: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.without, WithoutOwner(tc.customFields)); diff != "" {
+				t.Errorf("WithoutOwner(...): -want, +got: %s", diff)
+			}
+			if got := IsOwnedBy(tc.customFields, tc.uid); got != tc.want.owned {
+				t.Errorf("IsOwnedBy(...): want %t, got %t", tc.want.owned, got)
+			}
+			if diff := cmp.Diff(map[string]string(nil), tc.customFields); diff != original {
+				t.Errorf("custom fields were modified: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronome
+
+import (
+	"iter"
+)
+
+// A PageFunc fetches the page of results starting at the given cursor. It
+// returns the items in the page and the cursor of the following page, which
+// is empty once there are no more pages.
+type PageFunc[T any] func(nextPage string) ([]T, string, error)
+
+// Paginate returns an iterator over every item of every page returned by
+// fetch, starting from the first page. If fetching a page fails, the error is
+// yielded with the zero value of T and iteration stops.
+func Paginate[T any](fetch PageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		nextPage := ""
+		for {
+			items, next, err := fetch(nextPage)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			// guard against the API handing back the same cursor forever
+			if next == "" || next == nextPage {
+				return
+			}
+			nextPage = next
+		}
+	}
+}
+
+// derefPage returns the cursor of APIs that use a null next page to signal
+// the end of the results.
+func derefPage(nextPage *string) string {
+	if nextPage == nil {
+		return ""
+	}
+	return *nextPage
+}
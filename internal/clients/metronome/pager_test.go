@@ -0,0 +1,90 @@
+package metronome
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func Test_Paginate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type page struct {
+		items []int
+		next  string
+		err   error
+	}
+	type want struct {
+		items []int
+		err   error
+	}
+	cases := map[string]struct {
+		pages map[string]page
+		stop  int
+		want
+	}{
+		"SinglePage": {
+			pages: map[string]page{"": {items: []int{1, 2}}},
+			want:  want{items: []int{1, 2}},
+		},
+		"MultiplePages": {
+			pages: map[string]page{
+				"":  {items: []int{1, 2}, next: "a"},
+				"a": {items: []int{3}, next: "b"},
+				"b": {items: []int{4}},
+			},
+			want: want{items: []int{1, 2, 3, 4}},
+		},
+		"RepeatedCursorStops": {
+			pages: map[string]page{
+				"":  {items: []int{1}, next: "a"},
+				"a": {items: []int{2}, next: "a"},
+			},
+			want: want{items: []int{1, 2}},
+		},
+		"ErrorOnLaterPage": {
+			pages: map[string]page{
+				"":  {items: []int{1}, next: "a"},
+				"a": {err: errBoom},
+			},
+			want: want{items: []int{1}, err: errBoom},
+		},
+		"StopsWhenConsumerBreaks": {
+			pages: map[string]page{
+				"":  {items: []int{1, 2}, next: "a"},
+				"a": {err: errBoom},
+			},
+			stop: 2,
+			want: want{items: []int{1, 2}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			seq := Paginate(func(nextPage string) ([]int, string, error) {
+				p := tc.pages[nextPage]
+				return p.items, p.next, p.err
+			})
+
+			var got []int
+			var gotErr error
+			for v, err := range seq {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, v)
+				if len(got) == tc.stop {
+					break
+				}
+			}
+			if diff := cmp.Diff(tc.want.items, got); diff != "" {
+				t.Errorf("Paginate(...): -want items, +got items: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Errorf("Paginate(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
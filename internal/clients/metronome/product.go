@@ -20,14 +20,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/pkg/errors"
 )
 
 var (
-	ErrProductInvalidName     = errors.New("invalid product name")
-	ErrProductAlreadyArchived = errors.New("product already archived")
+	ErrProductInvalidName = errors.New("invalid product name")
 )
 
 const (
@@ -66,6 +66,7 @@ type CreateProductRequest struct {
 	QuantityConversion   *QuantityConversion `json:"quantity_conversion,omitempty"`
 	QuantityRounding     *QuantityRounding   `json:"quantity_rounding,omitempty"`
 	Tags                 []string            `json:"tags,omitempty"`
+	CustomFields         map[string]string   `json:"custom_fields,omitempty"`
 }
 
 type CreateProductResponse DataID
@@ -158,20 +159,14 @@ func (c *ProductClientImpl) ArchiveProduct(ctx context.Context, reqData ArchiveP
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			if c.Message == errProductAlreadyArchived {
-				return nil, ErrProductAlreadyArchived
-			}
-			return nil, errors.Wrap(c, "failed to archive product")
-		}
-		return nil, errors.New("failed to archive product: " + resp.Status)
+		return nil, errors.Wrap(newAlreadyArchivedError(resp, errProductAlreadyArchived), "failed to archive product")
 	}
 
 	var response ArchiveProductResponse
@@ -194,17 +189,14 @@ func (c *ProductClientImpl) CreateProduct(ctx context.Context, reqData CreatePro
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, nonIdempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to create product")
-		}
-		return nil, errors.New("failed to create product: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to create product")
 	}
 
 	var response CreateProductResponse
@@ -232,17 +224,14 @@ func (c *ProductClientImpl) GetProduct(ctx context.Context, reqData GetProductRe
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to get product")
-		}
-		return nil, errors.New("failed to get product: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to get product")
 	}
 
 	var response GetProductResponse
@@ -271,17 +260,14 @@ func (c *ProductClientImpl) ListProduct(ctx context.Context, reqData ListProduct
 	}
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to list product")
-		}
-		return nil, errors.New("failed to list product: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to list product")
 	}
 
 	var response ListProductsResponse
@@ -292,6 +278,18 @@ func (c *ProductClientImpl) ListProduct(ctx context.Context, reqData ListProduct
 	return &response, nil
 }
 
+// AllProducts returns an iterator over the products on every page of the
+// results of ListProduct.
+func AllProducts(ctx context.Context, c ProductClient, reqData ListProductsRequest) iter.Seq2[Product, error] {
+	return Paginate(func(nextPage string) ([]Product, string, error) {
+		res, err := c.ListProduct(ctx, reqData, nextPage)
+		if err != nil || res == nil {
+			return nil, "", err
+		}
+		return res.Data, res.NextPage, nil
+	})
+}
+
 func (c *ProductClientImpl) UpdateProduct(ctx context.Context, reqData UpdateProductRequest) (*UpdateProductResponse, error) {
 	url := fmt.Sprintf("%s/v1/contract-pricing/products/update", c.Client.baseURL)
 
@@ -309,17 +307,14 @@ func (c *ProductClientImpl) UpdateProduct(ctx context.Context, reqData UpdatePro
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, nonIdempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to update product")
-		}
-		return nil, errors.New("failed to update product: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to update product")
 	}
 
 	var response UpdateProductResponse
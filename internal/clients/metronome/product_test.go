@@ -0,0 +1,64 @@
+package metronome
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/redbackthomson/provider-metronome/internal/clients/metronome/cassette"
+)
+
+// newCassetteClient returns a client that replays the named cassette. When
+// recording, requests are sent to the Metronome API at METRONOME_BASE_URL
+// using the API key in METRONOME_AUTH_TOKEN.
+func newCassetteClient(t *testing.T, name string) *Client {
+	t.Helper()
+	rec := cassette.Start(t, name)
+
+	baseURL, token := "https://api.metronome.com", "test-token"
+	if os.Getenv(cassette.ModeEnvVar) == string(cassette.ModeRecord) {
+		baseURL, token = os.Getenv("METRONOME_BASE_URL"), os.Getenv("METRONOME_AUTH_TOKEN")
+	}
+
+	c, err := New(logging.NewNopLogger(), baseURL, token, WithHTTPClient(rec.Client()), WithMaxAttempts(1))
+	if err != nil {
+		t.Fatalf("New(...): %v", err)
+	}
+	return c
+}
+
+func Test_ProductClient_Cassette(t *testing.T) {
+	ctx := context.Background()
+	pc := newCassetteClient(t, "products").Product()
+
+	created, err := pc.CreateProduct(ctx, CreateProductRequest{
+		Name: "Seats",
+		Type: "SUBSCRIPTION",
+		Tags: []string{"team-a"},
+	})
+	if err != nil {
+		t.Fatalf("CreateProduct(...): %v", err)
+	}
+
+	got, err := pc.GetProduct(ctx, GetProductRequest{ID: created.Data.ID})
+	if err != nil {
+		t.Fatalf("GetProduct(...): %v", err)
+	}
+	if diff := cmp.Diff("Seats", got.Data.Current.Name); diff != "" {
+		t.Errorf("GetProduct(...): -want name, +got name: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"team-a"}, got.Data.Current.Tags); diff != "" {
+		t.Errorf("GetProduct(...): -want tags, +got tags: %s", diff)
+	}
+
+	if _, err := pc.ArchiveProduct(ctx, ArchiveProductRequest{ProductID: created.Data.ID}); err != nil {
+		t.Fatalf("ArchiveProduct(...): %v", err)
+	}
+	if _, err := pc.ArchiveProduct(ctx, ArchiveProductRequest{ProductID: created.Data.ID}); !errors.Is(err, ErrConflict) {
+		t.Errorf("ArchiveProduct(...): want ErrConflict when already archived, got %v", err)
+	}
+}
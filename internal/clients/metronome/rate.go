@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/pkg/errors"
@@ -32,6 +33,7 @@ var (
 type RateClient interface {
 	GetRates(ctx context.Context, reqData GetRatesRequest, nextPage string) (*GetRatesResponse, error)
 	AddRate(ctx context.Context, reqData AddRateRequest) (*AddRateResponse, error)
+	UpdateRateEndDate(ctx context.Context, reqData UpdateRateEndDateRequest) error
 }
 
 type RateClientImpl struct {
@@ -74,7 +76,7 @@ type AddRateRequest struct {
 	EndingBefore       string            `json:"ending_before,omitempty"`
 	Entitled           bool              `json:"entitled"`
 	IsProrated         bool              `json:"is_prorated,omitempty"`
-	Price              float64           `json:"price,omitempty"`
+	Price              Decimal           `json:"price,omitempty"`
 	PricingGroupValues map[string]string `json:"pricing_group_values,omitempty"`
 	ProductID          string            `json:"product_id"`
 	Quantity           float64           `json:"quantity,omitempty"`
@@ -88,18 +90,29 @@ type AddRateRequest struct {
 type AddRateResponse struct {
 	Data struct {
 		RateType string  `json:"rate_type"`
-		Price    float64 `json:"price"`
+		Price    Decimal `json:"price"`
 	} `json:"data"`
 }
 
+// UpdateRateEndDateRequest represents the request payload for changing when a
+// rate ends. Rates have no ID, so the rate is identified by its product,
+// pricing group values and start time.
+type UpdateRateEndDateRequest struct {
+	RateCardID         string            `json:"rate_card_id"`
+	ProductID          string            `json:"product_id"`
+	PricingGroupValues map[string]string `json:"pricing_group_values,omitempty"`
+	StartingAt         string            `json:"starting_at"`
+	EndingBefore       string            `json:"ending_before,omitempty"`
+}
+
 type Tier struct {
-	Price float64 `json:"price"`
+	Price Decimal `json:"price"`
 	Size  float64 `json:"size,omitempty"`
 }
 
 type CommitRate struct {
 	RateType string  `json:"rate_type"`
-	Price    float64 `json:"price"`
+	Price    Decimal `json:"price"`
 	Tiers    []Tier  `json:"tiers"`
 }
 
@@ -119,7 +132,7 @@ type Rate struct {
 type RateDetails struct {
 	CreditType         CreditType        `json:"credit_type,omitempty"`
 	IsProrated         bool              `json:"is_prorated,omitempty"`
-	Price              float64           `json:"price,omitempty"`
+	Price              Decimal           `json:"price,omitempty"`
 	PricingGroupValues map[string]string `json:"pricing_group_values,omitempty"`
 	Quantity           float64           `json:"quantity,omitempty"`
 	RateType           string            `json:"rate_type"`
@@ -156,17 +169,14 @@ func (c *RateClientImpl) GetRates(ctx context.Context, reqData GetRatesRequest,
 	}
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to get rates")
-		}
-		return nil, errors.New("failed to get rates: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to get rates")
 	}
 
 	var response GetRatesResponse
@@ -177,6 +187,18 @@ func (c *RateClientImpl) GetRates(ctx context.Context, reqData GetRatesRequest,
 	return &response, nil
 }
 
+// AllRates returns an iterator over the rates on every page of the results of
+// GetRates.
+func AllRates(ctx context.Context, c RateClient, reqData GetRatesRequest) iter.Seq2[Rate, error] {
+	return Paginate(func(nextPage string) ([]Rate, string, error) {
+		res, err := c.GetRates(ctx, reqData, nextPage)
+		if err != nil || res == nil {
+			return nil, "", err
+		}
+		return res.Data, res.NextPage, nil
+	})
+}
+
 func (c *RateClientImpl) AddRate(ctx context.Context, reqData AddRateRequest) (*AddRateResponse, error) {
 	url := fmt.Sprintf("%s/v1/contract-pricing/rate-cards/addRate", c.Client.baseURL)
 
@@ -197,17 +219,14 @@ func (c *RateClientImpl) AddRate(ctx context.Context, reqData AddRateRequest) (*
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, nonIdempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to add rate")
-		}
-		return nil, errors.New("failed to add rate: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to add rate")
 	}
 
 	var response AddRateResponse
@@ -217,3 +236,36 @@ func (c *RateClientImpl) AddRate(ctx context.Context, reqData AddRateRequest) (*
 
 	return &response, nil
 }
+
+func (c *RateClientImpl) UpdateRateEndDate(ctx context.Context, reqData UpdateRateEndDateRequest) error {
+	url := fmt.Sprintf("%s/v1/contract-pricing/rate-cards/updateRateEndDate", c.Client.baseURL)
+
+	if !IsUUID(reqData.RateCardID) {
+		return ErrRateInvalidName
+	}
+	if !IsUUID(reqData.ProductID) {
+		return ErrProductInvalidName
+	}
+
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, jsonData)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Client.do(req, idempotent)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrap(newAPIError(resp), "failed to update rate end date")
+	}
+
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/pkg/errors"
@@ -31,6 +32,7 @@ var (
 
 type RateCardClient interface {
 	GetRateCard(ctx context.Context, reqData GetRateCardRequest) (*GetRateCardResponse, error)
+	ListRateCards(ctx context.Context, nextPage string) (*ListRateCardsResponse, error)
 	CreateRateCard(ctx context.Context, reqData CreateRateCardRequest) (*CreateRateCardResponse, error)
 	UpdateRateCard(ctx context.Context, reqData UpdateRateCardRequest) (*UpdateRateCardResponse, error)
 	ArchiveRateCard(ctx context.Context, reqData ArchiveRateCardRequest) (*ArchiveRateCardResponse, error)
@@ -50,6 +52,11 @@ type GetRateCardResponse struct {
 	Data RateCard `json:"data"`
 }
 
+type ListRateCardsResponse struct {
+	Data     []RateCard `json:"data"`
+	NextPage *string    `json:"next_page"`
+}
+
 type RateCard struct {
 	ID             string            `json:"id"`
 	Name           string            `json:"name"`
@@ -81,10 +88,13 @@ type CreateRateCardResponse struct {
 	} `json:"data"`
 }
 
+// UpdateRateCardRequest represents the request payload for updating a rate
+// card. The aliases of the rate card are replaced by Aliases.
 type UpdateRateCardRequest struct {
-	RateCardID  string `json:"rate_card_id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	RateCardID  string          `json:"rate_card_id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Aliases     []RateCardAlias `json:"aliases"`
 }
 
 type UpdateRateCardResponse DataID
@@ -119,17 +129,14 @@ func (c *RateCardClientImpl) GetRateCard(ctx context.Context, reqData GetRateCar
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to get rate card")
-		}
-		return nil, errors.New("failed to get rate card: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to get rate card")
 	}
 
 	var response GetRateCardResponse
@@ -140,6 +147,50 @@ func (c *RateCardClientImpl) GetRateCard(ctx context.Context, reqData GetRateCar
 	return &response, nil
 }
 
+func (c *RateCardClientImpl) ListRateCards(ctx context.Context, nextPage string) (*ListRateCardsResponse, error) {
+	url := fmt.Sprintf("%s/v1/contract-pricing/rate-cards/list", c.Client.baseURL)
+
+	req, err := c.Client.newAuthenticatedRequest(ctx, "POST", url, []byte("{}"))
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	if nextPage != "" {
+		q.Add("next_page", nextPage)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.Client.do(req, idempotent)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrap(newAPIError(resp), "failed to list rate cards")
+	}
+
+	var response ListRateCardsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// AllRateCards returns an iterator over the rate cards on every page of the
+// results of ListRateCards.
+func AllRateCards(ctx context.Context, c RateCardClient) iter.Seq2[RateCard, error] {
+	return Paginate(func(nextPage string) ([]RateCard, string, error) {
+		res, err := c.ListRateCards(ctx, nextPage)
+		if err != nil || res == nil {
+			return nil, "", err
+		}
+		return res.Data, derefPage(res.NextPage), nil
+	})
+}
+
 func (c *RateCardClientImpl) CreateRateCard(ctx context.Context, reqData CreateRateCardRequest) (*CreateRateCardResponse, error) {
 	url := fmt.Sprintf("%s/v1/contract-pricing/rate-cards/create", c.Client.baseURL)
 	jsonData, err := json.Marshal(reqData)
@@ -152,17 +203,14 @@ func (c *RateCardClientImpl) CreateRateCard(ctx context.Context, reqData CreateR
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, nonIdempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to create rate card")
-		}
-		return nil, errors.New("failed to create rate card: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to create rate card")
 	}
 
 	var response CreateRateCardResponse
@@ -190,17 +238,14 @@ func (c *RateCardClientImpl) UpdateRateCard(ctx context.Context, reqData UpdateR
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to update rate card")
-		}
-		return nil, errors.New("failed to update rate card: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to update rate card")
 	}
 
 	var response UpdateRateCardResponse
@@ -228,17 +273,14 @@ func (c *RateCardClientImpl) ArchiveRateCard(ctx context.Context, reqData Archiv
 		return nil, err
 	}
 
-	resp, err := c.Client.httpClient.Do(req)
+	resp, err := c.Client.do(req, idempotent)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close() // nolint:errcheck // Read-only stream
 
 	if resp.StatusCode != http.StatusOK {
-		if c := ParseClientError(resp.Body); c != nil {
-			return nil, errors.Wrap(c, "failed to archive rate card")
-		}
-		return nil, errors.New("failed to archive rate card: " + resp.Status)
+		return nil, errors.Wrap(newAPIError(resp), "failed to archive rate card")
 	}
 
 	var response ArchiveRateCardResponse
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronome
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiters holds a token bucket rate limiter for each Metronome API key and
+// rate limit setting, so that every client using the same key with the same
+// setting shares a single request budget, regardless of which controller
+// created it. Clients using the same key with different settings get separate
+// limiters, so that neither setting overrides the other.
+type RateLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*sharedLimiter
+
+	requestsPerSecond float64
+	burst             int
+}
+
+type sharedLimiter struct {
+	limiter *rate.Limiter
+	refs    int
+}
+
+// NewRateLimiters returns a set of rate limiters that allow requestsPerSecond
+// requests per second, with bursts of up to burst requests, for each API key
+// by default. A requestsPerSecond of zero or less disables rate limiting.
+func NewRateLimiters(requestsPerSecond float64, burst int) *RateLimiters {
+	return &RateLimiters{
+		limiters:          map[string]*sharedLimiter{},
+		requestsPerSecond: requestsPerSecond,
+		burst:             burst,
+	}
+}
+
+// For returns the rate limiter shared by all clients using the given API key
+// and setting. Non-nil requestsPerSecond and burst values override the
+// defaults. The returned function must be called once the limiter is no
+// longer used, and the limiter is removed once every user has released it.
+func (r *RateLimiters) For(authToken string, requestsPerSecond *float64, burst *int) (*rate.Limiter, func()) {
+	rps, b := r.requestsPerSecond, r.burst
+	if requestsPerSecond != nil {
+		rps = *requestsPerSecond
+	}
+	if burst != nil {
+		b = *burst
+	}
+
+	limit := rate.Limit(rps)
+	if rps <= 0 {
+		limit = rate.Inf
+	}
+
+	// never hold on to the API key itself
+	sum := sha256.Sum256([]byte(authToken))
+	key := fmt.Sprintf("%s/%v/%d", hex.EncodeToString(sum[:]), limit, b)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l, ok := r.limiters[key]
+	if !ok {
+		l = &sharedLimiter{limiter: rate.NewLimiter(limit, b)}
+		r.limiters[key] = l
+	}
+	l.refs++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			l.refs--
+			if l.refs == 0 && r.limiters[key] == l {
+				delete(r.limiters, key)
+			}
+		})
+	}
+	return l.limiter, release
+}
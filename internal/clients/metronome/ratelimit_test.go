@@ -0,0 +1,92 @@
+package metronome
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+	"k8s.io/utils/ptr"
+)
+
+func Test_RateLimiters_For(t *testing.T) {
+	type call struct {
+		token string
+		rps   *float64
+		burst *int
+	}
+	type want struct {
+		limit  rate.Limit
+		burst  int
+		shared bool
+	}
+	cases := map[string]struct {
+		calls []call
+		want
+	}{
+		"Defaults": {
+			calls: []call{{token: "a"}},
+			want:  want{limit: 10, burst: 20},
+		},
+		"SameKeyIsShared": {
+			calls: []call{{token: "a"}, {token: "a"}},
+			want:  want{limit: 10, burst: 20, shared: true},
+		},
+		"DifferentKeysAreNotShared": {
+			calls: []call{{token: "a"}, {token: "b"}},
+			want:  want{limit: 10, burst: 20, shared: false},
+		},
+		"DifferentSettingsAreNotShared": {
+			calls: []call{{token: "a"}, {token: "a", rps: ptr.To(2.5), burst: ptr.To(5)}},
+			want:  want{limit: 2.5, burst: 5, shared: false},
+		},
+		"SameOverrideIsShared": {
+			calls: []call{{token: "a", rps: ptr.To(2.5)}, {token: "a", rps: ptr.To(2.5)}},
+			want:  want{limit: 2.5, burst: 20, shared: true},
+		},
+		"ZeroDisablesLimit": {
+			calls: []call{{token: "a", rps: ptr.To(0.0)}},
+			want:  want{limit: rate.Inf, burst: 20},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRateLimiters(10, 20)
+			var got []*rate.Limiter
+			for _, c := range tc.calls {
+				l, _ := r.For(c.token, c.rps, c.burst)
+				got = append(got, l)
+			}
+			last := got[len(got)-1]
+			if diff := cmp.Diff(tc.want.limit, last.Limit()); diff != "" {
+				t.Errorf("For(...): -want limit, +got limit: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.burst, last.Burst()); diff != "" {
+				t.Errorf("For(...): -want burst, +got burst: %s", diff)
+			}
+			if len(got) > 1 {
+				if diff := cmp.Diff(tc.want.shared, got[0] == got[1]); diff != "" {
+					t.Errorf("For(...): -want shared, +got shared: %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_RateLimiters_Release(t *testing.T) {
+	r := NewRateLimiters(10, 20)
+
+	l1, release1 := r.For("a", nil, nil)
+	_, release2 := r.For("a", nil, nil)
+
+	release1()
+	release1()
+	l3, release3 := r.For("a", nil, nil)
+	if l3 != l1 {
+		t.Errorf("For(...): limiter removed while still in use")
+	}
+	release2()
+	release3()
+	if got := len(r.limiters); got != 0 {
+		t.Errorf("release(): want no limiters, got %d", got)
+	}
+}
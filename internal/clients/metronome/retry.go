@@ -0,0 +1,208 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronome
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultMaxAttempts  = 4
+	defaultRetryBudget  = 30 * time.Second
+	defaultBackoffBase  = 250 * time.Millisecond
+	defaultBackoffLimit = 10 * time.Second
+)
+
+// requestKind describes whether a request may safely be sent to Metronome
+// more than once.
+type requestKind int
+
+const (
+	// idempotent requests (reads, archives, and updates that set absolute
+	// values) are retried on any transient failure.
+	idempotent requestKind = iota
+	// nonIdempotent requests (creates and appends) are only retried when
+	// Metronome has rejected them without processing them, i.e. when
+	// throttled.
+	nonIdempotent
+)
+
+// retryConfig controls how failed requests are retried.
+type retryConfig struct {
+	// maxAttempts is the total number of times a request is sent, including
+	// the first attempt.
+	maxAttempts int
+	// budget is the maximum amount of time spent waiting between attempts
+	// for a single request.
+	budget time.Duration
+	// backoffBase is the delay before the first retry. Each subsequent
+	// retry doubles it, up to backoffLimit.
+	backoffBase  time.Duration
+	backoffLimit time.Duration
+}
+
+func defaultRetryConfig() retryConfig {
+	return retryConfig{
+		maxAttempts:  defaultMaxAttempts,
+		budget:       defaultRetryBudget,
+		backoffBase:  defaultBackoffBase,
+		backoffLimit: defaultBackoffLimit,
+	}
+}
+
+// do sends the request, retrying transient failures according to the
+// client's retry configuration. The response of the final attempt is returned
+// to the caller unmodified, so callers handle non-200 responses as usual.
+func (c *Client) do(req *http.Request, kind requestKind) (*http.Response, error) {
+	ctx, span := c.startSpan(req)
+	resp, attempts, err := c.send(req.WithContext(ctx), kind)
+	endSpan(span, resp, attempts, err)
+	return resp, err
+}
+
+// send sends the request until it succeeds or should no longer be retried,
+// returning the final response and the number of attempts made.
+func (c *Client) send(req *http.Request, kind requestKind) (*http.Response, int, error) {
+	ctx := req.Context()
+	var waited time.Duration
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, attempt, err
+			}
+			req.Body = body
+		}
+
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, attempt, err
+			}
+		}
+
+		start := time.Now()
+		resp, err := c.httpClient.Do(req)
+		if c.metrics != nil {
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			c.metrics.observe(operationName(req.URL.Path), req.Method, c.providerConfig, status, time.Since(start))
+		}
+
+		if attempt >= c.retry.maxAttempts || !shouldRetry(kind, resp, err) {
+			return resp, attempt, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				delay = after
+			}
+		}
+		if waited+delay > c.retry.budget {
+			return resp, attempt, err
+		}
+
+		if resp != nil {
+			// drain the body so the underlying connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close() // nolint:errcheck // Read-only stream
+		}
+
+		c.logger.Debug("Retrying Metronome request",
+			"method", req.Method, "url", req.URL.Path, "attempt", attempt, "delay", delay)
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, attempt, err
+		}
+		waited += delay
+	}
+}
+
+// shouldRetry reports whether a request of the given kind should be retried
+// after receiving the given response or error.
+func shouldRetry(kind requestKind, resp *http.Response, err error) bool {
+	if err != nil {
+		// the request may have reached Metronome before the connection
+		// failed, so only idempotent requests are safe to resend
+		return kind == idempotent && !isContextError(err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return kind == idempotent
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry attempt, using
+// exponential backoff with full jitter.
+func (r retryConfig) backoff(attempt int) time.Duration {
+	d := r.backoffBase << (attempt - 1)
+	if d <= 0 || d > r.backoffLimit {
+		d = r.backoffLimit
+	}
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d) // nolint:gosec // Jitter doesn't need a secure source
+}
+
+// retryAfter parses the Retry-After header of a response, which may either be
+// a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,127 @@
+package metronome
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Client_Do(t *testing.T) {
+	type args struct {
+		kind     requestKind
+		statuses []int
+		header   http.Header
+		opts     []Option
+	}
+	type want struct {
+		status   int
+		attempts int32
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"SuccessIsNotRetried": {
+			args: args{
+				kind:     idempotent,
+				statuses: []int{http.StatusOK},
+			},
+			want: want{status: http.StatusOK, attempts: 1},
+		},
+		"ServerErrorIsRetried": {
+			args: args{
+				kind:     idempotent,
+				statuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			},
+			want: want{status: http.StatusOK, attempts: 3},
+		},
+		"ServerErrorIsNotRetriedForNonIdempotent": {
+			args: args{
+				kind:     nonIdempotent,
+				statuses: []int{http.StatusInternalServerError, http.StatusOK},
+			},
+			want: want{status: http.StatusInternalServerError, attempts: 1},
+		},
+		"ThrottledIsRetriedForNonIdempotent": {
+			args: args{
+				kind:     nonIdempotent,
+				statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+			},
+			want: want{status: http.StatusOK, attempts: 2},
+		},
+		"ClientErrorIsNotRetried": {
+			args: args{
+				kind:     idempotent,
+				statuses: []int{http.StatusBadRequest, http.StatusOK},
+			},
+			want: want{status: http.StatusBadRequest, attempts: 1},
+		},
+		"StopsAtMaxAttempts": {
+			args: args{
+				kind:     idempotent,
+				statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+				opts:     []Option{WithMaxAttempts(2)},
+			},
+			want: want{status: http.StatusBadGateway, attempts: 2},
+		},
+		"RetryAfterExceedsBudget": {
+			args: args{
+				kind:     idempotent,
+				statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+				header:   http.Header{"Retry-After": []string{"120"}},
+			},
+			want: want{status: http.StatusTooManyRequests, attempts: 1},
+		},
+		"RetryAfterWithinBudget": {
+			args: args{
+				kind:     idempotent,
+				statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+				header:   http.Header{"Retry-After": []string{"0"}},
+			},
+			want: want{status: http.StatusOK, attempts: 2},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+				if b, _ := io.ReadAll(r.Body); string(b) != `{"id":"1"}` {
+					t.Errorf("unexpected body on attempt %d: %s", n, b)
+				}
+				for k, v := range tc.args.header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(tc.args.statuses[n-1])
+			}))
+			defer srv.Close()
+
+			opts := append([]Option{WithRetryBackoff(time.Millisecond, 5*time.Millisecond)}, tc.args.opts...)
+			c, _ := New(logging.NewNopLogger(), srv.URL, "token", opts...)
+
+			req, err := c.newAuthenticatedRequest(context.Background(), "POST", srv.URL, []byte(`{"id":"1"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := c.do(req, tc.args.kind)
+			if err != nil {
+				t.Fatalf("do(...): unexpected error: %v", err)
+			}
+			defer resp.Body.Close() // nolint:errcheck // Read-only stream
+
+			if diff := cmp.Diff(tc.want.status, resp.StatusCode); diff != "" {
+				t.Errorf("do(...): -want status, +got status: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.attempts, attempts.Load()); diff != "" {
+				t.Errorf("do(...): -want attempts, +got attempts: %s", diff)
+			}
+		})
+	}
+}
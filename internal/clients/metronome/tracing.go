@@ -0,0 +1,60 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronome
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+
+// startSpan starts a client span for a request to the Metronome API, as a
+// child of any span in the request's context.
+func (c *Client) startSpan(req *http.Request) (context.Context, trace.Span) {
+	return c.tracer.Start(req.Context(), "metronome "+operationName(req.URL.Path),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+			attribute.String("metronome.provider_config", c.providerConfig),
+		),
+	)
+}
+
+// endSpan records the outcome of a request on its span and ends it.
+func endSpan(span trace.Span, resp *http.Response, attempts int, err error) {
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("metronome.attempts", attempts))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	if id := resp.Header.Get(requestIDHeader); id != "" {
+		span.SetAttributes(attribute.String("metronome.request_id", id))
+	}
+}
@@ -0,0 +1,72 @@
+package metronome
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_Client_Tracing(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set(requestIDHeader, "req-123")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+
+	c, _ := New(logging.NewNopLogger(), srv.URL, "token",
+		WithTracerProvider(tp), WithMetrics(NewMetrics(), "default"), WithRetryBackoff(0, 0))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	if _, err := c.Product().GetProduct(ctx, GetProductRequest{ID: "3f3c2a5e-8f0c-4a53-9a0b-0a1d2e3f4a5b"}); err == nil {
+		t.Fatal("GetProduct(...): expected error")
+	}
+	parent.End()
+
+	spans := exp.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("GetSpans(): want 2 spans, got %d", len(spans))
+	}
+	s := spans[0]
+	if diff := cmp.Diff("metronome products/get", s.Name); diff != "" {
+		t.Errorf("span name: -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(parent.SpanContext().SpanID(), s.Parent.SpanID()); diff != "" {
+		t.Errorf("span parent: -want, +got: %s", diff)
+	}
+	if diff := cmp.Diff(codes.Error, s.Status.Code); diff != "" {
+		t.Errorf("span status: -want, +got: %s", diff)
+	}
+
+	want := map[attribute.Key]attribute.Value{
+		"http.request.method":       attribute.StringValue(http.MethodPost),
+		"url.path":                  attribute.StringValue("/v1/contract-pricing/products/get"),
+		"metronome.provider_config": attribute.StringValue("default"),
+		"metronome.attempts":        attribute.IntValue(2),
+		"http.response.status_code": attribute.IntValue(http.StatusNotFound),
+		"metronome.request_id":      attribute.StringValue("req-123"),
+	}
+	got := map[attribute.Key]attribute.Value{}
+	for _, kv := range s.Attributes {
+		got[kv.Key] = kv.Value
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(attribute.Value{})); diff != "" {
+		t.Errorf("span attributes: -want, +got: %s", diff)
+	}
+}
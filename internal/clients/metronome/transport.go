@@ -0,0 +1,188 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metronome
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultRequestTimeout is the time limit of a single request sent to
+// Metronome, including reading the response body, when none is configured.
+const DefaultRequestTimeout = 30 * time.Second
+
+const (
+	errParseProxyURL   = "cannot parse proxy URL"
+	errParseCABundle   = "CA bundle does not contain any PEM encoded certificates"
+	errLoadClientCert  = "cannot load client certificate"
+	errClientCertOrKey = "client certificate and key must be set together"
+)
+
+// TransportConfig configures how requests are sent to Metronome.
+type TransportConfig struct {
+	// Timeout of each request. DefaultRequestTimeout is used if zero.
+	Timeout time.Duration
+
+	// ProxyURL of the proxy requests are sent through. The proxy is chosen
+	// from the HTTPS_PROXY and NO_PROXY environment variables if empty.
+	ProxyURL string
+
+	// CABundle of PEM encoded certificates trusted in addition to the system
+	// roots.
+	CABundle []byte
+
+	// ClientCert and ClientKey are the PEM encoded certificate and key
+	// presented to Metronome, or a proxy, for mutual TLS.
+	ClientCert []byte
+	ClientKey  []byte
+}
+
+// hash identifies the config without holding on to the key material in it.
+func (c TransportConfig) hash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s;", c.Timeout)
+	for _, b := range [][]byte{[]byte(c.ProxyURL), c.CABundle, c.ClientCert, c.ClientKey} {
+		// length prefix every field so that they can't run into each other
+		fmt.Fprintf(h, "%d:", len(b))
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NewHTTPClient returns an HTTP client with its own connection pool that
+// sends requests as configured.
+func NewHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.ProxyURL != "" {
+		u, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseProxyURL)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	if len(cfg.CABundle) > 0 || len(cfg.ClientCert) > 0 || len(cfg.ClientKey) > 0 {
+		t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	if len(cfg.CABundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(cfg.CABundle) {
+			return nil, errors.New(errParseCABundle)
+		}
+		t.TLSClientConfig.RootCAs = pool
+	}
+
+	if len(cfg.ClientCert) > 0 || len(cfg.ClientKey) > 0 {
+		if len(cfg.ClientCert) == 0 || len(cfg.ClientKey) == 0 {
+			return nil, errors.New(errClientCertOrKey)
+		}
+		cert, err := tls.X509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, errLoadClientCert)
+		}
+		t.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	return &http.Client{Transport: t, Timeout: timeout}, nil
+}
+
+// HTTPClients holds an HTTP client for each ProviderConfig, so that every
+// client created for the same ProviderConfig shares a single connection pool,
+// regardless of which controller created it.
+type HTTPClients struct {
+	mu      sync.Mutex
+	clients map[string]*pooledClient
+
+	timeout time.Duration
+}
+
+type pooledClient struct {
+	hash   string
+	client *http.Client
+	refs   int
+}
+
+// NewHTTPClients returns a set of HTTP clients whose requests time out after
+// the given duration by default.
+func NewHTTPClients(timeout time.Duration) *HTTPClients {
+	return &HTTPClients{
+		clients: map[string]*pooledClient{},
+		timeout: timeout,
+	}
+}
+
+// For returns the HTTP client shared by all clients created for the named
+// ProviderConfig. A new client replaces the existing one when the config
+// changes, and the idle connections of the old client are closed. The
+// returned function must be called once the client is no longer used, and
+// the client is removed once every user has released it, so that clients of
+// deleted ProviderConfigs aren't kept.
+func (h *HTTPClients) For(providerConfig string, cfg TransportConfig) (*http.Client, func(), error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = h.timeout
+	}
+	hash := cfg.hash()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	pc, ok := h.clients[providerConfig]
+	if !ok || pc.hash != hash {
+		hc, err := NewHTTPClient(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			pc.client.CloseIdleConnections()
+		}
+		pc = &pooledClient{hash: hash, client: hc}
+		h.clients[providerConfig] = pc
+	}
+	pc.refs++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			pc.refs--
+			if pc.refs == 0 && h.clients[providerConfig] == pc {
+				delete(h.clients, providerConfig)
+				pc.client.CloseIdleConnections()
+			}
+		})
+	}
+	return pc.client, release, nil
+}
@@ -0,0 +1,174 @@
+package metronome
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func Test_NewHTTPClient(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer tlsServer.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	type want struct {
+		timeout time.Duration
+		err     error
+		getErr  bool
+	}
+	cases := map[string]struct {
+		cfg TransportConfig
+		url string
+		want
+	}{
+		"DefaultTimeout": {
+			cfg: TransportConfig{},
+			url: proxy.URL,
+			want: want{
+				timeout: DefaultRequestTimeout,
+			},
+		},
+		"UntrustedServer": {
+			cfg: TransportConfig{Timeout: time.Second},
+			url: tlsServer.URL,
+			want: want{
+				timeout: time.Second,
+				getErr:  true,
+			},
+		},
+		"TrustedCABundle": {
+			cfg: TransportConfig{Timeout: time.Second, CABundle: caBundle},
+			url: tlsServer.URL,
+			want: want{
+				timeout: time.Second,
+			},
+		},
+		"Proxy": {
+			cfg: TransportConfig{ProxyURL: proxy.URL},
+			url: "http://api.metronome.invalid/v1/contract-pricing/products/list",
+			want: want{
+				timeout: DefaultRequestTimeout,
+			},
+		},
+		"InvalidProxyURL": {
+			cfg: TransportConfig{ProxyURL: "://proxy"},
+			want: want{
+				err: errors.Wrap(errors.New(`parse "://proxy": missing protocol scheme`), errParseProxyURL),
+			},
+		},
+		"InvalidCABundle": {
+			cfg: TransportConfig{CABundle: []byte("not a certificate")},
+			want: want{
+				err: errors.New(errParseCABundle),
+			},
+		},
+		"ClientCertWithoutKey": {
+			cfg: TransportConfig{ClientCert: caBundle},
+			want: want{
+				err: errors.New(errClientCertOrKey),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hc, err := NewHTTPClient(tc.cfg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("NewHTTPClient(...): -want error, +got error: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if hc.Timeout != tc.want.timeout {
+				t.Errorf("NewHTTPClient(...): want timeout %s, got %s", tc.want.timeout, hc.Timeout)
+			}
+
+			proxied = ""
+			resp, err := hc.Get(tc.url)
+			if (err != nil) != tc.want.getErr {
+				t.Fatalf("Get(%s): want error %t, got %v", tc.url, tc.want.getErr, err)
+			}
+			if err == nil {
+				resp.Body.Close() //nolint:errcheck
+			}
+			if tc.cfg.ProxyURL != "" && proxied != tc.url {
+				t.Errorf("Get(%s): want request sent through proxy, proxy got %q", tc.url, proxied)
+			}
+		})
+	}
+}
+
+func Test_HTTPClients_For(t *testing.T) {
+	h := NewHTTPClients(time.Minute)
+
+	a, _, err := h.For("pc-a", TransportConfig{})
+	if err != nil {
+		t.Fatalf("For(...): %v", err)
+	}
+	if a.Timeout != time.Minute {
+		t.Errorf("For(...): want default timeout %s, got %s", time.Minute, a.Timeout)
+	}
+
+	if again, _, _ := h.For("pc-a", TransportConfig{}); again != a {
+		t.Error("For(...): want the same client for an unchanged config")
+	}
+	if other, _, _ := h.For("pc-b", TransportConfig{}); other == a {
+		t.Error("For(...): want a separate client for another ProviderConfig")
+	}
+
+	changed, _, err := h.For("pc-a", TransportConfig{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("For(...): %v", err)
+	}
+	if changed == a || changed.Timeout != time.Second {
+		t.Errorf("For(...): want a new client with timeout %s after the config changed", time.Second)
+	}
+
+	if _, _, err := h.For("pc-a", TransportConfig{CABundle: []byte("invalid")}); err == nil {
+		t.Error("For(...): want error for an invalid config")
+	}
+	if kept, _, _ := h.For("pc-a", TransportConfig{Timeout: time.Second}); kept != changed {
+		t.Error("For(...): want the existing client kept after an invalid config")
+	}
+}
+
+func Test_HTTPClients_Release(t *testing.T) {
+	h := NewHTTPClients(time.Minute)
+
+	c1, release1, _ := h.For("pc-a", TransportConfig{})
+	_, release2, _ := h.For("pc-a", TransportConfig{})
+
+	release1()
+	release1()
+	c3, release3, _ := h.For("pc-a", TransportConfig{})
+	if c3 != c1 {
+		t.Errorf("For(...): client removed while still in use")
+	}
+
+	// releasing a replaced client doesn't remove its replacement
+	_, releaseChanged, _ := h.For("pc-a", TransportConfig{Timeout: time.Second})
+	release2()
+	release3()
+	if got := len(h.clients); got != 1 {
+		t.Errorf("release(): want the replacement client kept, got %d clients", got)
+	}
+
+	releaseChanged()
+	if got := len(h.clients); got != 0 {
+		t.Errorf("release(): want no clients, got %d", got)
+	}
+}
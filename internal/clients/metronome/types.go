@@ -16,6 +16,8 @@ limitations under the License.
 
 package metronome
 
+import "time"
+
 type DataID struct {
 	Data IDOnly `json:"data"`
 }
@@ -23,3 +25,17 @@ type DataID struct {
 type IDOnly struct {
 	ID string `json:"id"`
 }
+
+// SameTime reports whether a and b are the same RFC 3339 timestamp, even if
+// formatted differently.
+func SameTime(a, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	ta, errA := time.Parse(time.RFC3339, a)
+	tb, errB := time.Parse(time.RFC3339, b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return ta.Equal(tb)
+}
@@ -0,0 +1,25 @@
+package metronome
+
+import "testing"
+
+func Test_SameTime(t *testing.T) {
+	cases := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"Equal":            {a: "2025-01-01T00:00:00Z", b: "2025-01-01T00:00:00Z", want: true},
+		"DifferentFormat":  {a: "2025-01-01T00:00:00Z", b: "2025-01-01T01:00:00.000+01:00", want: true},
+		"DifferentTime":    {a: "2025-01-01T00:00:00Z", b: "2025-01-02T00:00:00Z", want: false},
+		"BothEmpty":        {want: true},
+		"OneEmpty":         {a: "2025-01-01T00:00:00Z", want: false},
+		"UnparsableEqual":  {a: "tomorrow", b: "tomorrow", want: true},
+		"UnparsableDiffer": {a: "tomorrow", b: "2025-01-01T00:00:00Z", want: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := SameTime(tc.a, tc.b); got != tc.want {
+				t.Errorf("SameTime(%q, %q): want %t, got %t", tc.a, tc.b, tc.want, got)
+			}
+		})
+	}
+}
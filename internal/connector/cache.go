@@ -0,0 +1,224 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	metronomev1alpha1 "github.com/redbackthomson/provider-metronome/apis/v1alpha1"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+const (
+	errGetSecret = "cannot get secret %s/%s"
+)
+
+// ClientCache holds a Metronome client for each ProviderConfig, shared by the
+// Connectors of every controller, so that credentials aren't read and clients
+// aren't built on every reconcile.
+//
+// A client is rebuilt when the version of its ProviderConfig changes. The
+// version covers the spec of the ProviderConfig and the Secrets it
+// references, so rotated credentials are picked up on the next connect.
+type ClientCache struct {
+	mu      sync.Mutex
+	clients map[types.UID]*cachedClient
+	// builds are the clients being built, at most one per ProviderConfig.
+	builds map[types.UID]*pendingBuild
+}
+
+type cachedClient struct {
+	version string
+	client  *metronomeClient.Client
+
+	// refs counts the external clients using the client that haven't been
+	// disconnected yet.
+	refs int
+	// retired is set once the client has been replaced by a newer version.
+	retired bool
+}
+
+// pendingBuild is a client being built without holding the lock, which other
+// callers for the same ProviderConfig wait for.
+type pendingBuild struct {
+	version string
+	done    chan struct{}
+	err     error
+}
+
+// NewClientCache returns an empty ClientCache.
+func NewClientCache() *ClientCache {
+	return &ClientCache{
+		clients: map[types.UID]*cachedClient{},
+		builds:  map[types.UID]*pendingBuild{},
+	}
+}
+
+// acquire returns the cached client of the ProviderConfig with the given UID
+// if it was built for the same version, and otherwise builds and caches a new
+// one. Clients are built without holding the lock, so only callers for the
+// same ProviderConfig wait on a build. The returned function must be called
+// once the client is no longer in use.
+func (c *ClientCache) acquire(uid types.UID, version string, build func() (*metronomeClient.Client, error)) (*metronomeClient.Client, func(), error) {
+	for {
+		c.mu.Lock()
+		if cc, ok := c.clients[uid]; ok && cc.version == version {
+			release := c.use(cc)
+			c.mu.Unlock()
+			return cc.client, release, nil
+		}
+		b, ok := c.builds[uid]
+		if !ok {
+			break
+		}
+		c.mu.Unlock()
+
+		<-b.done
+		if b.version == version && b.err != nil {
+			return nil, nil, b.err
+		}
+	}
+
+	// the lock is still held from the loop
+	b := &pendingBuild{version: version, done: make(chan struct{})}
+	c.builds[uid] = b
+	c.mu.Unlock()
+
+	m, err := build()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.builds, uid)
+	b.err = err
+	close(b.done)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if old, ok := c.clients[uid]; ok {
+		c.retire(old)
+	}
+	cc := &cachedClient{version: version, client: m}
+	c.clients[uid] = cc
+	return m, c.use(cc), nil
+}
+
+// use adds a reference to a cached client, and returns the function that
+// removes it. The caller must hold the lock.
+func (c *ClientCache) use(cc *cachedClient) func() {
+	cc.refs++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			cc.refs--
+			if cc.retired && cc.refs == 0 {
+				cc.client.Close()
+			}
+		})
+	}
+}
+
+// Remove drops the client of a ProviderConfig that is being deleted, closing
+// it once it is no longer in use.
+func (c *ClientCache) Remove(uid types.UID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cc, ok := c.clients[uid]; ok {
+		c.retire(cc)
+		delete(c.clients, uid)
+	}
+}
+
+// retire marks a replaced client, closing its connections if it is no longer
+// in use. The caller must hold the lock.
+func (c *ClientCache) retire(cc *cachedClient) {
+	cc.retired = true
+	if cc.refs == 0 {
+		cc.client.Close()
+	}
+}
+
+// providerConfigVersion identifies the settings and credentials a client for
+// the ProviderConfig is built from. The Secrets it references are identified
+// by their resource version, which is read from the metadata cache of the
+// kube client, since Secrets themselves aren't cached. Credentials from any
+// other source are read and hashed.
+func providerConfigVersion(ctx context.Context, kube client.Client, pc *metronomev1alpha1.ProviderConfig) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d;", pc.GetGeneration())
+
+	var refs []xpv1.SecretReference
+	cd := pc.Spec.Credentials
+	if cd.Source == xpv1.CredentialsSourceSecret && cd.SecretRef != nil {
+		refs = append(refs, cd.SecretRef.SecretReference)
+	} else {
+		kc, err := resource.CommonCredentialExtractor(ctx, cd.Source, kube, cd.CommonCredentialSelectors)
+		if err != nil {
+			return "", errors.Wrap(err, errGetCreds)
+		}
+		fmt.Fprintf(h, "%d:", len(kc))
+		h.Write(kc)
+	}
+	if t := pc.Spec.Transport; t != nil {
+		if t.CABundleSecretRef != nil {
+			refs = append(refs, t.CABundleSecretRef.SecretReference)
+		}
+		if t.ClientCertSecretRef != nil {
+			refs = append(refs, *t.ClientCertSecretRef)
+		}
+	}
+
+	for _, ref := range refs {
+		s := &metav1.PartialObjectMetadata{}
+		s.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return "", errors.Wrapf(err, errGetSecret, ref.Namespace, ref.Name)
+		}
+		fmt.Fprintf(h, "%s/%s@%s;", ref.Namespace, ref.Name, s.GetResourceVersion())
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// releasingExternal releases the cached client an ExternalClient was created
+// with when it is disconnected.
+type releasingExternal struct {
+	managed.ExternalClient
+	release func()
+}
+
+func (r *releasingExternal) Disconnect(ctx context.Context) error {
+	r.release()
+	return r.ExternalClient.Disconnect(ctx)
+}
@@ -0,0 +1,187 @@
+package connector
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	metronomev1alpha1 "github.com/redbackthomson/provider-metronome/apis/v1alpha1"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+func Test_Connector_ConnectCached(t *testing.T) {
+	secretVersion := "1"
+	kube := &test.MockClient{
+		MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *metronomev1alpha1.ProviderConfig:
+				o.SetName(providerConfigName)
+				o.SetUID(types.UID("pc-uid"))
+				o.Spec.Credentials = metronomev1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+						SecretRef: &xpv1.SecretKeySelector{
+							SecretReference: xpv1.SecretReference{Name: "creds", Namespace: testNamespace},
+							Key:             "auth",
+						},
+					},
+				}
+			case *metav1.PartialObjectMetadata:
+				o.SetResourceVersion(secretVersion)
+			case *corev1.Secret:
+				o.SetResourceVersion(secretVersion)
+				o.Data = map[string][]byte{"auth": []byte("token-" + secretVersion)}
+			}
+			return nil
+		},
+	}
+
+	var tokens []string
+	c := &Connector[*expectedResource, *mockExternalClient]{
+		Logger:  logging.NewNopLogger(),
+		Client:  kube,
+		Usage:   resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+		Clients: NewClientCache(),
+		NewMetronomeClientFn: func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error) {
+			tokens = append(tokens, authToken)
+			return metronomeClient.New(log, baseURL, authToken, opts...)
+		},
+		NewExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *mockExternalClient {
+			return &mockExternalClient{}
+		},
+	}
+	connect := func() {
+		t.Helper()
+		e, err := c.Connect(context.Background(), billableMetric())
+		if err != nil {
+			t.Fatalf("Connect(...): %v", err)
+		}
+		if err := e.Disconnect(context.Background()); err != nil {
+			t.Fatalf("Disconnect(...): %v", err)
+		}
+	}
+
+	connect()
+	connect()
+	if diff := cmp.Diff([]string{"token-1"}, tokens); diff != "" {
+		t.Errorf("Connect(...): -want clients built, +got clients built: %s", diff)
+	}
+
+	// a rotated secret has a new resource version
+	secretVersion = "2"
+	connect()
+	connect()
+	if diff := cmp.Diff([]string{"token-1", "token-2"}, tokens); diff != "" {
+		t.Errorf("Connect(...): -want clients built, +got clients built: %s", diff)
+	}
+}
+
+func Test_ClientCache_Acquire(t *testing.T) {
+	build := func() (*metronomeClient.Client, error) {
+		return metronomeClient.New(logging.NewNopLogger(), "", "token")
+	}
+	c := NewClientCache()
+
+	m1, release1, err := c.acquire("uid", "v1", build)
+	if err != nil {
+		t.Fatalf("acquire(...): %v", err)
+	}
+	m2, release2, _ := c.acquire("uid", "v1", build)
+	if m1 != m2 {
+		t.Errorf("acquire(...): got a new client for the same version")
+	}
+	m3, release3, _ := c.acquire("uid", "v2", build)
+	if m3 == m1 {
+		t.Errorf("acquire(...): got the cached client for a new version")
+	}
+
+	release1()
+	release1()
+	if got := c.clients["uid"].refs; got != 1 {
+		t.Errorf("release(): want 1 reference to the current client, got %d", got)
+	}
+	release2()
+	release3()
+	if got := c.clients["uid"].refs; got != 0 {
+		t.Errorf("release(): want 0 references to the current client, got %d", got)
+	}
+}
+
+func Test_ClientCache_Remove(t *testing.T) {
+	closed := 0
+	build := func() (*metronomeClient.Client, error) {
+		return metronomeClient.New(logging.NewNopLogger(), "", "token", metronomeClient.WithOnClose(func() { closed++ }))
+	}
+	c := NewClientCache()
+
+	_, release, _ := c.acquire("uid", "v1", build)
+	c.Remove("uid")
+	if closed != 0 {
+		t.Errorf("Remove(...): closed a client still in use")
+	}
+	release()
+	if closed != 1 {
+		t.Errorf("release(): want the removed client closed, closed %d clients", closed)
+	}
+	if _, ok := c.clients["uid"]; ok {
+		t.Errorf("Remove(...): client still cached")
+	}
+}
+
+func Test_ClientCache_ConcurrentBuilds(t *testing.T) {
+	c := NewClientCache()
+	unblock := make(chan struct{})
+	var builds atomic.Int32
+	slow := func() (*metronomeClient.Client, error) {
+		builds.Add(1)
+		<-unblock
+		return metronomeClient.New(logging.NewNopLogger(), "", "slow")
+	}
+	fast := func() (*metronomeClient.Client, error) {
+		return metronomeClient.New(logging.NewNopLogger(), "", "fast")
+	}
+
+	var wg sync.WaitGroup
+	got := make([]*metronomeClient.Client, 2)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i], _, _ = c.acquire("slow", "v1", slow)
+		}()
+	}
+
+	// a slow build for one ProviderConfig doesn't block the others
+	done := make(chan struct{})
+	go func() {
+		_, _, _ = c.acquire("fast", "v1", fast)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("acquire(...): blocked by the build of another ProviderConfig")
+	}
+
+	close(unblock)
+	wg.Wait()
+	if got[0] != got[1] {
+		t.Errorf("acquire(...): callers for the same version got different clients")
+	}
+	if n := builds.Load(); n != 1 {
+		t.Errorf("acquire(...): want 1 build for the same version, got %d", n)
+	}
+}
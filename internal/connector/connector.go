@@ -3,10 +3,13 @@ package connector
 import (
 	"context"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -21,15 +24,60 @@ const (
 	errGetCreds             = "failed to create credentials from provider config"
 	errFailedToTrackUsage   = "cannot track provider config usage"
 	errConnectToMetronome   = "error connecting to Metronome"
+	errGetCABundle          = "cannot get CA bundle secret"
+	errGetClientCert        = "cannot get client certificate secret"
+	errNewHTTPClient        = "cannot create HTTP client from transport settings"
+	errInvalidCredentials   = "provider config %s has invalid credentials: %s"
 )
 
-type Connector[R resource.Managed, T managed.ExternalClient] struct {
+// Options are the settings shared by the Connectors of every controller.
+type Options struct {
+	// BaseURL of the Metronome API.
 	BaseURL string
-	Logger  logging.Logger
-	Client  client.Client
-	Usage   resource.Tracker
 
-	NewMetronomeClientFn func(log logging.Logger, baseURL, authToken string) (*metronomeClient.Client, error)
+	// RateLimiters limit the rate of requests sent to Metronome for each API
+	// key, across every controller.
+	RateLimiters *metronomeClient.RateLimiters
+
+	// Metrics record every request sent to Metronome.
+	Metrics *metronomeClient.Metrics
+
+	// TracerProvider records spans for every external client operation and
+	// every request sent to Metronome. Tracing is disabled if nil.
+	TracerProvider trace.TracerProvider
+
+	// HTTPClients are reused by every client created for the same
+	// ProviderConfig, across every controller.
+	HTTPClients *metronomeClient.HTTPClients
+
+	// Clients caches a Metronome client for each ProviderConfig, across every
+	// controller. A new client is built on every connect if nil.
+	Clients *ClientCache
+
+	// ValidateCustomFieldKeys makes controllers check that every custom field
+	// set by a managed resource has a CustomFieldKey resource for its entity.
+	ValidateCustomFieldKeys bool
+
+	// AdoptCreatedObjects makes controllers mark the objects they create in
+	// Metronome with the UID of their managed resource, creating the
+	// OwnerCustomFieldKey custom field key if needed. An object whose create
+	// failed or was interrupted before its ID was recorded is then adopted
+	// rather than created again.
+	AdoptCreatedObjects bool
+}
+
+type Connector[R resource.Managed, T managed.ExternalClient] struct {
+	BaseURL        string
+	RateLimiters   *metronomeClient.RateLimiters
+	Metrics        *metronomeClient.Metrics
+	TracerProvider trace.TracerProvider
+	HTTPClients    *metronomeClient.HTTPClients
+	Clients        *ClientCache
+	Logger         logging.Logger
+	Client         client.Client
+	Usage          resource.Tracker
+
+	NewMetronomeClientFn func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error)
 	NewExternalClientFn  func(log logging.Logger, client *metronomeClient.Client) T
 }
 
@@ -57,16 +105,151 @@ func (c *Connector[R, T]) Connect(ctx context.Context, mg resource.Managed) (man
 		return nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
+	// fail fast rather than sending requests Metronome is known to reject
+	if cond := pc.GetCondition(metronomev1alpha1.TypeCredentialsValid); cond.Status == corev1.ConditionFalse && cond.Reason == metronomev1alpha1.ReasonUnauthorized {
+		return nil, errors.Errorf(errInvalidCredentials, pc.GetName(), cond.Message)
+	}
+
+	o := Options{
+		BaseURL:        c.BaseURL,
+		RateLimiters:   c.RateLimiters,
+		Metrics:        c.Metrics,
+		TracerProvider: c.TracerProvider,
+		HTTPClients:    c.HTTPClients,
+	}
+	build := func() (*metronomeClient.Client, error) {
+		return newClient(ctx, c.Client, pc, o, c.Logger, c.NewMetronomeClientFn)
+	}
+
+	// without a cache the client is only used by this external client, and
+	// is closed once it is disconnected
+	if c.Clients == nil {
+		m, err := build()
+		if err != nil {
+			return nil, err
+		}
+		return &releasingExternal{ExternalClient: c.external(m), release: m.Close}, nil
+	}
+
+	version, err := providerConfigVersion(ctx, c.Client, pc)
+	if err != nil {
+		return nil, err
+	}
+	m, release, err := c.Clients.acquire(pc.GetUID(), version, build)
+	if err != nil {
+		return nil, err
+	}
+	return &releasingExternal{ExternalClient: c.external(m), release: release}, nil
+}
+
+// external returns the ExternalClient of a Metronome client, traced if
+// tracing is enabled.
+func (c *Connector[R, T]) external(m *metronomeClient.Client) managed.ExternalClient {
+	e := c.NewExternalClientFn(c.Logger, m)
+	if c.TracerProvider == nil {
+		return e
+	}
+	return &tracedExternal{external: e, tracer: c.TracerProvider.Tracer(tracerName)}
+}
+
+// NewClient returns a Metronome client for the ProviderConfig, configured the
+// same way as the clients of the managed resources that use it.
+func NewClient(ctx context.Context, kube client.Client, pc *metronomev1alpha1.ProviderConfig, o Options, log logging.Logger) (*metronomeClient.Client, error) {
+	return newClient(ctx, kube, pc, o, log, metronomeClient.New)
+}
+
+func newClient(ctx context.Context, kube client.Client, pc *metronomev1alpha1.ProviderConfig, o Options, log logging.Logger, newFn func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error)) (*metronomeClient.Client, error) {
 	cd := pc.Spec.Credentials
-	kc, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.Client, cd.CommonCredentialSelectors)
+	kc, err := resource.CommonCredentialExtractor(ctx, cd.Source, kube, cd.CommonCredentialSelectors)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	m, err := c.NewMetronomeClientFn(c.Logger, c.BaseURL, string(kc))
+	var opts []metronomeClient.Option
+	if o.Metrics != nil {
+		opts = append(opts, metronomeClient.WithMetrics(o.Metrics, pc.GetName()))
+	}
+
+	if o.TracerProvider != nil {
+		opts = append(opts, metronomeClient.WithTracerProvider(o.TracerProvider))
+	}
+
+	// the HTTP client and limiter are released when the client is closed
+	var releases []func()
+	release := func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+
+	if o.HTTPClients != nil {
+		tc, err := transportConfig(ctx, kube, pc.Spec.Transport)
+		if err != nil {
+			return nil, err
+		}
+		hc, r, err := o.HTTPClients.For(pc.GetName(), tc)
+		if err != nil {
+			return nil, errors.Wrap(err, errNewHTTPClient)
+		}
+		opts = append(opts, metronomeClient.WithHTTPClient(hc), metronomeClient.WithOnClose(r))
+		releases = append(releases, r)
+	}
+
+	baseURL := o.BaseURL
+	if pc.Spec.BaseURL != nil && *pc.Spec.BaseURL != "" {
+		baseURL = *pc.Spec.BaseURL
+	}
+
+	if o.RateLimiters != nil {
+		var rps *float64
+		var burst *int
+		if rl := pc.Spec.RateLimit; rl != nil {
+			rps, burst = rl.RequestsPerSecond, rl.Burst
+		}
+		l, r := o.RateLimiters.For(string(kc), rps, burst)
+		opts = append(opts, metronomeClient.WithRateLimiter(l), metronomeClient.WithOnClose(r))
+		releases = append(releases, r)
+	}
+
+	m, err := newFn(log, baseURL, string(kc), opts...)
 	if err != nil {
+		release()
 		return nil, errors.Wrap(err, errConnectToMetronome)
 	}
+	return m, nil
+}
+
+// transportConfig reads the transport settings of a ProviderConfig, including
+// any certificates from the Secrets they reference.
+func transportConfig(ctx context.Context, kube client.Client, t *metronomev1alpha1.Transport) (metronomeClient.TransportConfig, error) {
+	tc := metronomeClient.TransportConfig{}
+	if t == nil {
+		return tc, nil
+	}
+
+	if t.Timeout != nil {
+		tc.Timeout = t.Timeout.Duration
+	}
+	if t.ProxyURL != nil {
+		tc.ProxyURL = *t.ProxyURL
+	}
+
+	if ref := t.CABundleSecretRef; ref != nil {
+		ca, err := resource.ExtractSecret(ctx, kube, xpv1.CommonCredentialSelectors{SecretRef: ref})
+		if err != nil {
+			return tc, errors.Wrap(err, errGetCABundle)
+		}
+		tc.CABundle = ca
+	}
+
+	if ref := t.ClientCertSecretRef; ref != nil {
+		s := &corev1.Secret{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return tc, errors.Wrap(err, errGetClientCert)
+		}
+		tc.ClientCert = s.Data[corev1.TLSCertKey]
+		tc.ClientKey = s.Data[corev1.TLSPrivateKeyKey]
+	}
 
-	return c.NewExternalClientFn(c.Logger, m), nil
+	return tc, nil
 }
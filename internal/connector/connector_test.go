@@ -3,11 +3,13 @@ package connector
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -29,6 +31,19 @@ const (
 
 var (
 	errBoom = errors.New("boom")
+
+	testCABundle = []byte(`-----BEGIN CERTIFICATE-----
+MIIBkDCCATWgAwIBAgIUHFdYCd55kXJyRXd+OH+zBccQ9PAwCgYIKoZIzj0EAwIw
+HDEaMBgGA1UEAwwRbWV0cm9ub21lLXRlc3QtY2EwIBcNMjYxMDE3MDY0NDI5WhgP
+MjEyNjA5MjMwNjQ0MjlaMBwxGjAYBgNVBAMMEW1ldHJvbm9tZS10ZXN0LWNhMFkw
+EwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAERltg5ZdX6zx3t/ynNtFlzUCVV9ecQJEp
+4r9hFAvy6gMXMpEyEt33+0r0580WrDIQkzeEhbleJlZTR1/I/okQqaNTMFEwHQYD
+VR0OBBYEFOYakfdQ/uG1Y1cS2xgnnKT49zwKMB8GA1UdIwQYMBaAFOYakfdQ/uG1
+Y1cS2xgnnKT49zwKMA8GA1UdEwEB/wQFMAMBAf8wCgYIKoZIzj0EAwIDSQAwRgIh
+ALbl3OxEiRaNvhpoYPhH0iqmehECTcN1PLm9D+Mpo5j5AiEArmymvIKGcWjqVccX
+pBvnL0jOulurSH9fI0HT93hfKs0=
+-----END CERTIFICATE-----
+`)
 )
 
 // use billable metric for testing expected resource
@@ -107,7 +122,9 @@ func Test_Connector_Connect(t *testing.T) {
 		mg     resource.Managed
 
 		baseURL              string
-		newMetronomeClientFn func(log logging.Logger, baseURL, authToken string) (*metronomeClient.Client, error)
+		rateLimiters         *metronomeClient.RateLimiters
+		httpClients          *metronomeClient.HTTPClients
+		newMetronomeClientFn func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error)
 		newExternalClientFn  func(log logging.Logger, client *metronomeClient.Client) *mockExternalClient
 	}
 	type want struct {
@@ -152,6 +169,29 @@ func Test_Connector_Connect(t *testing.T) {
 				err: errors.Wrap(errBoom, errGetProviderConfig),
 			},
 		},
+		"KnownInvalidCredentials": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if pc, ok := obj.(*metronomev1alpha1.ProviderConfig); ok {
+							*pc = *providerConfig.DeepCopy()
+							pc.SetConditions(metronomev1alpha1.CredentialsInvalid(metronomev1alpha1.ReasonUnauthorized, "revoked"))
+							return nil
+						}
+						return errBoom
+					},
+				},
+				newMetronomeClientFn: func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error) {
+					t.Error("unexpected Metronome client for invalid credentials")
+					return nil, errBoom
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    billableMetric(),
+			},
+			want: want{
+				err: errors.Errorf(errInvalidCredentials, providerConfigName, "revoked"),
+			},
+		},
 		"FailedToCreateNewMetronomeClient": {
 			args: args{
 				client: &test.MockClient{
@@ -174,7 +214,7 @@ func Test_Connector_Connect(t *testing.T) {
 						return nil
 					},
 				},
-				newMetronomeClientFn: func(log logging.Logger, baseURL, authToken string) (*metronomeClient.Client, error) {
+				newMetronomeClientFn: func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error) {
 					return nil, errBoom
 				},
 				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
@@ -207,7 +247,7 @@ func Test_Connector_Connect(t *testing.T) {
 					},
 				},
 				baseURL: "abc123",
-				newMetronomeClientFn: func(log logging.Logger, baseURL, authToken string) (*metronomeClient.Client, error) {
+				newMetronomeClientFn: func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error) {
 					if baseURL != "abc123" {
 						t.Errorf("unexpected base URL: %s", baseURL)
 					}
@@ -226,6 +266,223 @@ func Test_Connector_Connect(t *testing.T) {
 				err: nil,
 			},
 		},
+		"ProviderConfigBaseURL": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						switch t := obj.(type) {
+						case *metronomev1alpha1.ProviderConfig:
+							*t = *providerConfig.DeepCopy()
+							t.Spec.BaseURL = ptr.To("https://sandbox.example.com")
+						case *corev1.Secret:
+							*t = corev1.Secret{
+								Data: map[string][]byte{
+									"auth": []byte("def456"),
+								},
+							}
+						default:
+							return errBoom
+						}
+						return nil
+					},
+				},
+				baseURL: "abc123",
+				newMetronomeClientFn: func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error) {
+					if baseURL != "https://sandbox.example.com" {
+						t.Errorf("unexpected base URL: %s", baseURL)
+					}
+					return &metronomeClient.Client{}, nil
+				},
+				newExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *mockExternalClient {
+					return &mockExternalClient{}
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    billableMetric(),
+			},
+			want: want{
+				err: nil,
+			},
+		},
+		"RateLimited": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						switch t := obj.(type) {
+						case *metronomev1alpha1.ProviderConfig:
+							*t = *providerConfig.DeepCopy()
+							t.Spec.RateLimit = &metronomev1alpha1.RateLimit{Burst: ptr.To(3)}
+						case *corev1.Secret:
+							*t = corev1.Secret{
+								Data: map[string][]byte{
+									"auth": []byte("def456"),
+								},
+							}
+						default:
+							return errBoom
+						}
+						return nil
+					},
+				},
+				rateLimiters: metronomeClient.NewRateLimiters(10, 20),
+				newMetronomeClientFn: func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error) {
+					if len(opts) != 2 {
+						t.Errorf("expected rate limiter and release options, got %d options", len(opts))
+					}
+					return &metronomeClient.Client{}, nil
+				},
+				newExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *mockExternalClient {
+					return &mockExternalClient{}
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    billableMetric(),
+			},
+			want: want{
+				err: nil,
+			},
+		},
+		"FailedToGetCABundle": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						switch t := obj.(type) {
+						case *metronomev1alpha1.ProviderConfig:
+							*t = *providerConfig.DeepCopy()
+							t.Spec.Transport = &metronomev1alpha1.Transport{
+								Timeout:  &metav1.Duration{Duration: 5 * time.Second},
+								ProxyURL: ptr.To("http://proxy.example.com:3128"),
+								CABundleSecretRef: &xpv1.SecretKeySelector{
+									SecretReference: xpv1.SecretReference{Name: "ca", Namespace: testNamespace},
+									Key:             "ca.crt",
+								},
+							}
+						case *corev1.Secret:
+							if key.Name == "ca" {
+								return errBoom
+							}
+							*t = corev1.Secret{
+								Data: map[string][]byte{
+									"auth": []byte("def456"),
+								},
+							}
+						default:
+							return errBoom
+						}
+						return nil
+					},
+				},
+				httpClients: metronomeClient.NewHTTPClients(time.Minute),
+				newMetronomeClientFn: func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error) {
+					if len(opts) != 2 {
+						t.Errorf("expected HTTP client and release options, got %d options", len(opts))
+					}
+					return &metronomeClient.Client{}, nil
+				},
+				newExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *mockExternalClient {
+					return &mockExternalClient{}
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    billableMetric(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot get credentials secret"), errGetCABundle),
+			},
+		},
+		"InvalidCABundle": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						switch t := obj.(type) {
+						case *metronomev1alpha1.ProviderConfig:
+							*t = *providerConfig.DeepCopy()
+							t.Spec.Transport = &metronomev1alpha1.Transport{
+								Timeout:  &metav1.Duration{Duration: 5 * time.Second},
+								ProxyURL: ptr.To("http://proxy.example.com:3128"),
+								CABundleSecretRef: &xpv1.SecretKeySelector{
+									SecretReference: xpv1.SecretReference{Name: "ca", Namespace: testNamespace},
+									Key:             "ca.crt",
+								},
+							}
+						case *corev1.Secret:
+							if key.Name == "ca" {
+								*t = corev1.Secret{Data: map[string][]byte{"ca.crt": []byte("not a certificate")}}
+								return nil
+							}
+							*t = corev1.Secret{
+								Data: map[string][]byte{
+									"auth": []byte("def456"),
+								},
+							}
+						default:
+							return errBoom
+						}
+						return nil
+					},
+				},
+				httpClients: metronomeClient.NewHTTPClients(time.Minute),
+				newMetronomeClientFn: func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error) {
+					if len(opts) != 2 {
+						t.Errorf("expected HTTP client and release options, got %d options", len(opts))
+					}
+					return &metronomeClient.Client{}, nil
+				},
+				newExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *mockExternalClient {
+					return &mockExternalClient{}
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    billableMetric(),
+			},
+			want: want{
+				err: errors.Wrap(errors.New("CA bundle does not contain any PEM encoded certificates"), errNewHTTPClient),
+			},
+		},
+		"Transport": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						switch t := obj.(type) {
+						case *metronomev1alpha1.ProviderConfig:
+							*t = *providerConfig.DeepCopy()
+							t.Spec.Transport = &metronomev1alpha1.Transport{
+								Timeout:  &metav1.Duration{Duration: 5 * time.Second},
+								ProxyURL: ptr.To("http://proxy.example.com:3128"),
+								CABundleSecretRef: &xpv1.SecretKeySelector{
+									SecretReference: xpv1.SecretReference{Name: "ca", Namespace: testNamespace},
+									Key:             "ca.crt",
+								},
+							}
+						case *corev1.Secret:
+							if key.Name == "ca" {
+								*t = corev1.Secret{Data: map[string][]byte{"ca.crt": testCABundle}}
+								return nil
+							}
+							*t = corev1.Secret{
+								Data: map[string][]byte{
+									"auth": []byte("def456"),
+								},
+							}
+						default:
+							return errBoom
+						}
+						return nil
+					},
+				},
+				httpClients: metronomeClient.NewHTTPClients(time.Minute),
+				newMetronomeClientFn: func(log logging.Logger, baseURL, authToken string, opts ...metronomeClient.Option) (*metronomeClient.Client, error) {
+					if len(opts) != 2 {
+						t.Errorf("expected HTTP client and release options, got %d options", len(opts))
+					}
+					return &metronomeClient.Client{}, nil
+				},
+				newExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *mockExternalClient {
+					return &mockExternalClient{}
+				},
+				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
+				mg:    billableMetric(),
+			},
+			want: want{
+				err: nil,
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -235,6 +492,9 @@ func Test_Connector_Connect(t *testing.T) {
 				Usage:   tc.usage,
 				BaseURL: tc.baseURL,
 
+				RateLimiters: tc.rateLimiters,
+				HTTPClients:  tc.httpClients,
+
 				NewMetronomeClientFn: tc.newMetronomeClientFn,
 				NewExternalClientFn:  tc.newExternalClientFn,
 			}
@@ -0,0 +1,79 @@
+package connector
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/redbackthomson/provider-metronome/internal/connector"
+
+// tracedExternal wraps an ExternalClient, recording a span for each of its
+// operations.
+type tracedExternal struct {
+	external managed.ExternalClient
+	tracer   trace.Tracer
+}
+
+var _ managed.ExternalClient = (*tracedExternal)(nil)
+
+func (t *tracedExternal) start(ctx context.Context, op string, mg resource.Managed) (context.Context, trace.Span) {
+	// the TypeMeta of objects read from the cache is often empty, so the kind
+	// is taken from the Go type instead
+	kind := reflect.TypeOf(mg).Elem().Name()
+	return t.tracer.Start(ctx, kind+"."+op, trace.WithAttributes(
+		attribute.String("crossplane.resource.kind", kind),
+		attribute.String("crossplane.resource.name", mg.GetName()),
+		attribute.String("crossplane.resource.external_name", meta.GetExternalName(mg)),
+	))
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (t *tracedExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, span := t.start(ctx, "Observe", mg)
+	o, err := t.external.Observe(ctx, mg)
+	span.SetAttributes(
+		attribute.Bool("crossplane.observation.exists", o.ResourceExists),
+		attribute.Bool("crossplane.observation.up_to_date", o.ResourceUpToDate),
+	)
+	end(span, err)
+	return o, err
+}
+
+func (t *tracedExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, span := t.start(ctx, "Create", mg)
+	c, err := t.external.Create(ctx, mg)
+	end(span, err)
+	return c, err
+}
+
+func (t *tracedExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, span := t.start(ctx, "Update", mg)
+	u, err := t.external.Update(ctx, mg)
+	end(span, err)
+	return u, err
+}
+
+func (t *tracedExternal) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	ctx, span := t.start(ctx, "Delete", mg)
+	d, err := t.external.Delete(ctx, mg)
+	end(span, err)
+	return d, err
+}
+
+func (t *tracedExternal) Disconnect(ctx context.Context) error {
+	return t.external.Disconnect(ctx)
+}
@@ -0,0 +1,94 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingExternalClient struct {
+	mockExternalClient
+}
+
+func (c *failingExternalClient) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, errBoom
+}
+
+func Test_TracedExternal(t *testing.T) {
+	type want struct {
+		name   string
+		status codes.Code
+	}
+	cases := map[string]struct {
+		external managed.ExternalClient
+		op       func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error
+		want
+	}{
+		"Observe": {
+			external: &mockExternalClient{},
+			op: func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+				_, err := e.Observe(ctx, mg)
+				return err
+			},
+			want: want{name: "BillableMetric.Observe", status: codes.Unset},
+		},
+		"CreateFailed": {
+			external: &failingExternalClient{},
+			op: func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+				_, err := e.Create(ctx, mg)
+				return err
+			},
+			want: want{name: "BillableMetric.Create", status: codes.Error},
+		},
+		"Update": {
+			external: &mockExternalClient{},
+			op: func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+				_, err := e.Update(ctx, mg)
+				return err
+			},
+			want: want{name: "BillableMetric.Update", status: codes.Unset},
+		},
+		"Delete": {
+			external: &mockExternalClient{},
+			op: func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+				_, err := e.Delete(ctx, mg)
+				return err
+			},
+			want: want{name: "BillableMetric.Delete", status: codes.Unset},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			exp := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+			e := &tracedExternal{external: tc.external, tracer: tp.Tracer(tracerName)}
+
+			mg := billableMetric()
+			meta.SetExternalName(mg, "abc123")
+			_ = tc.op(context.Background(), e, mg)
+
+			spans := exp.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("GetSpans(): want 1 span, got %d", len(spans))
+			}
+			if diff := cmp.Diff(tc.want.name, spans[0].Name); diff != "" {
+				t.Errorf("span name: -want, +got: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.status, spans[0].Status.Code); diff != "" {
+				t.Errorf("span status: -want, +got: %s", diff)
+			}
+			attrs := attribute.NewSet(spans[0].Attributes...)
+			if v, _ := attrs.Value("crossplane.resource.external_name"); v.AsString() != "abc123" {
+				t.Errorf("span external name: want abc123, got %q", v.AsString())
+			}
+		})
+	}
+}
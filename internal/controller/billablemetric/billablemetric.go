@@ -18,12 +18,18 @@ package billablemetric
 
 import (
 	"context"
+	"fmt"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
@@ -36,6 +42,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
 
 	"github.com/redbackthomson/provider-metronome/apis/billablemetric/v1alpha1"
+	productv1alpha1 "github.com/redbackthomson/provider-metronome/apis/product/v1alpha1"
 	metronomev1alpha1 "github.com/redbackthomson/provider-metronome/apis/v1alpha1"
 	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
 	"github.com/redbackthomson/provider-metronome/internal/connector"
@@ -47,10 +54,20 @@ const (
 	errGetBillableMetric     = "failed to get billable metric"
 	errCreateBillableMetric  = "failed to create billable metric"
 	errArchiveBillableMetric = "failed to archive billable metric"
+	errAdoptBillableMetric   = "failed to find billable metric previously created for this resource"
+	errEnsureOwnerKey        = "failed to create owner custom field key"
+	errUpdateNotSupported    = "updating a billable metric is not supported, set spec.replacement.policy to Replace to replace it instead"
+	errFindReplacement       = "failed to find billable metric previously created to replace this one"
+	errListProducts          = "failed to list products"
+	errGetProduct            = "failed to get product"
+	errUpdateProductSpec     = "failed to update the billable metric of product"
+	errParseStartingAt       = "failed to parse the starting time of the replacement"
+	errSaveReplacedID        = "failed to record the ID of the replaced billable metric"
+	errSwapExternalName      = "failed to record the ID of the replacement billable metric"
 )
 
 // Setup adds a controller that reconciles BillableMetric managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
+func Setup(mgr ctrl.Manager, o controller.Options, co connector.Options) error {
 	name := managed.ControllerName(v1alpha1.BillableMetricGroupKind)
 
 	reconcilerOptions := []managed.ReconcilerOption{
@@ -59,12 +76,22 @@ func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
 				Logger:               o.Logger,
 				Client:               mgr.GetClient(),
 				Usage:                resource.NewProviderConfigUsageTracker(mgr.GetClient(), &metronomev1alpha1.ProviderConfigUsage{}),
-				BaseURL:              baseUrl,
+				BaseURL:              co.BaseURL,
+				RateLimiters:         co.RateLimiters,
+				Metrics:              co.Metrics,
+				TracerProvider:       co.TracerProvider,
+				HTTPClients:          co.HTTPClients,
+				Clients:              co.Clients,
 				NewMetronomeClientFn: metronomeClient.New,
 				NewExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *metronomeExternal {
 					return &metronomeExternal{
 						logger:    o.Logger,
 						metronome: client.BillableMetric(),
+						keys:      client.CustomFieldKey(),
+						products:  client.Product(),
+						kube:      mgr.GetClient(),
+						clock:     clock.RealClock{},
+						adopt:     co.AdoptCreatedObjects,
 					}
 				},
 			}),
@@ -98,6 +125,14 @@ func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
 type metronomeExternal struct {
 	logger    logging.Logger
 	metronome metronomeClient.BillableMetricClient
+	keys      metronomeClient.CustomFieldKeyClient
+	products  metronomeClient.ProductClient
+	kube      client.Client
+	clock     clock.PassiveClock
+
+	// adopt marks the objects created in Metronome with the UID of their
+	// managed resource, and adopts them if their ID was never recorded.
+	adopt bool
 }
 
 func (e *metronomeExternal) Disconnect(ctx context.Context) error {
@@ -113,14 +148,26 @@ func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (m
 	e.logger.Debug("Observing")
 
 	id := meta.GetExternalName(cr)
+	adopted := false
 	if id == "" {
-		return managed.ExternalObservation{}, nil
+		// a previous reconcile may have created the billable metric without
+		// recording its ID
+		owned, err := e.findOwned(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errAdoptBillableMetric)
+		}
+		if owned == "" {
+			return managed.ExternalObservation{}, nil
+		}
+		e.logger.Debug("Adopting billable metric", "id", owned)
+		meta.SetExternalName(cr, owned)
+		id, adopted = owned, true
 	}
 
 	res, err := e.metronome.GetBillableMetric(ctx, id)
 	if err != nil {
-		// the external name isn't valid
-		if errors.Is(err, metronomeClient.ErrBillableMetricInvalidName) {
+		// the external name isn't valid, or the metric no longer exists
+		if errors.Is(err, metronomeClient.ErrBillableMetricInvalidName) || errors.Is(err, metronomeClient.ErrNotFound) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetBillableMetric)
@@ -142,13 +189,73 @@ func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (m
 
 	upToDate, diff := isUpToDate(cr, metric)
 
+	// replaced billable metrics are archived by Update, unless archiving them
+	// was refused and the products that kept them from being archived are
+	// still using them
+	nothingToArchive := len(cr.Status.ReplacedIDs) == 0
+	if !nothingToArchive && inUse(cr) {
+		if nothingToArchive, err = e.stillBlocked(ctx, cr); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetProduct)
+		}
+	}
+
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: upToDate,
-		Diff:             diff,
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate && nothingToArchive,
+		ResourceLateInitialized: adopted,
+		Diff:                    diff,
 	}, nil
 }
 
+// inUse reports whether archiving the billable metrics the managed resource
+// replaced was last refused because they are in use.
+func inUse(cr *v1alpha1.BillableMetric) bool {
+	c := cr.GetCondition(metronomev1alpha1.TypeChangeBlocked)
+	return c.Status == corev1.ConditionTrue && c.Reason == metronomev1alpha1.ReasonReplacedBillableMetricInUse
+}
+
+// stillBlocked reports whether every product that kept a replaced billable
+// metric from being archived still uses one. Without such products, nothing
+// short of a change to the managed resource unblocks archiving.
+func (e *metronomeExternal) stillBlocked(ctx context.Context, cr *v1alpha1.BillableMetric) (bool, error) {
+	for _, pid := range cr.Status.BlockingProductIDs {
+		res, err := e.products.GetProduct(ctx, metronomeClient.GetProductRequest{ID: pid})
+		if errors.Is(err, metronomeClient.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if res.Data.ArchivedAt != "" || !slices.Contains(cr.Status.ReplacedIDs, res.Data.Current.BillableMetricID) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// findOwned returns the ID of the billable metric marked as created by the managed
+// resource, or an empty string if there is none.
+func (e *metronomeExternal) findOwned(ctx context.Context, cr *v1alpha1.BillableMetric) (string, error) {
+	// only a create that was attempted can have lost the ID of the object
+	// it created
+	uid := string(cr.GetUID())
+	if !e.adopt || uid == "" || meta.GetExternalCreatePending(cr).IsZero() {
+		return "", nil
+	}
+	for obj, err := range metronomeClient.AllBillableMetrics(ctx, e.metronome, metronomeClient.ListBillableMetricsRequest{}) {
+		if err != nil {
+			return "", err
+		}
+		if obj.ArchivedAt != "" {
+			continue
+		}
+		if metronomeClient.IsOwnedBy(obj.CustomFields, uid) && obj.Name == cr.Spec.ForProvider.Name {
+			return obj.ID, nil
+		}
+	}
+	return "", nil
+}
+
 func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.BillableMetric)
 	if !ok {
@@ -157,31 +264,241 @@ func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (ma
 
 	e.logger.Debug("Creating")
 
+	id, err := e.create(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	meta.SetExternalName(cr, id)
+
+	return managed.ExternalCreation{}, nil
+}
+
+// create creates a billable metric matching the spec and returns its ID.
+func (e *metronomeExternal) create(ctx context.Context, cr *v1alpha1.BillableMetric) (string, error) {
 	converter := &converters.BillableMetricConverterImpl{}
 	req := converter.FromBillableMetricSpec(&cr.Spec.ForProvider)
 
+	// mark the billable metric so it can be adopted if its ID is lost
+	if uid := string(cr.GetUID()); e.adopt && uid != "" {
+		if err := metronomeClient.EnsureOwnerCustomFieldKey(ctx, e.keys, metronomeClient.EntityBillableMetric); err != nil {
+			return "", errors.Wrap(err, errEnsureOwnerKey)
+		}
+		req.CustomFields = metronomeClient.WithOwner(req.CustomFields, uid)
+	}
+
 	res, err := e.metronome.CreateBillableMetric(ctx, *req)
 	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errCreateBillableMetric)
+		return "", errors.Wrap(err, errCreateBillableMetric)
 	}
 	if res.Data.ID == "" {
-		return managed.ExternalCreation{}, errors.New("billable metric ID is missing")
+		return "", errors.New("billable metric ID is missing")
 	}
-
-	meta.SetExternalName(cr, res.Data.ID)
-
-	return managed.ExternalCreation{}, nil
+	return res.Data.ID, nil
 }
 
+// Update replaces the billable metric if its replacement policy allows it,
+// since Metronome doesn't allow billable metrics to be changed. A new billable
+// metric is created, the Products that use the old one are moved to it, and
+// the old one is archived once no product uses it.
 func (e *metronomeExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	_, ok := mg.(*v1alpha1.BillableMetric)
+	cr, ok := mg.(*v1alpha1.BillableMetric)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotBillableMetric)
 	}
 
 	e.logger.Debug("Updating")
 
-	return managed.ExternalUpdate{}, errors.New("updating a billable metric is not supported")
+	res, err := e.metronome.GetBillableMetric(ctx, meta.GetExternalName(cr))
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errGetBillableMetric)
+	}
+
+	if upToDate, _ := isUpToDate(cr, &res.Data); !upToDate {
+		if cr.Spec.Replacement == nil || cr.Spec.Replacement.Policy != v1alpha1.ReplacementPolicyReplace {
+			return managed.ExternalUpdate{}, errors.New(errUpdateNotSupported)
+		}
+		if err := e.replace(ctx, cr); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
+	return managed.ExternalUpdate{}, e.archiveReplaced(ctx, cr)
+}
+
+// replace creates a billable metric matching the spec, moves the Products
+// that use the current one to it, and records its ID as the external name.
+func (e *metronomeExternal) replace(ctx context.Context, cr *v1alpha1.BillableMetric) error {
+	old := meta.GetExternalName(cr)
+
+	// a previous reconcile may have created the replacement without recording
+	// its ID
+	id, err := e.findReplacement(ctx, cr, old)
+	if err != nil {
+		return errors.Wrap(err, errFindReplacement)
+	}
+	if id == "" {
+		if id, err = e.create(ctx, cr); err != nil {
+			return err
+		}
+	}
+
+	startingAt := e.clock.Now().UTC().Format(time.RFC3339)
+	if cr.Spec.Replacement.StartingAt != "" {
+		startingAt = cr.Spec.Replacement.StartingAt
+	}
+	if err := e.repointProducts(ctx, cr, old, id, startingAt); err != nil {
+		return err
+	}
+
+	// the old billable metric would never be archived if its ID were lost
+	// once the external name no longer refers to it
+	if !slices.Contains(cr.Status.ReplacedIDs, old) {
+		cr.Status.ReplacedIDs = append(cr.Status.ReplacedIDs, old)
+		if err := e.kube.Status().Update(ctx, cr); err != nil {
+			return errors.Wrap(err, errSaveReplacedID)
+		}
+	}
+
+	e.logger.Debug("Replacing billable metric", "old", old, "new", id)
+	meta.SetExternalName(cr, id)
+	if err := managed.NewRetryingCriticalAnnotationUpdater(e.kube).UpdateCriticalAnnotations(ctx, cr); err != nil {
+		return errors.Wrap(err, errSwapExternalName)
+	}
+	return nil
+}
+
+// findReplacement returns the ID of a billable metric marked as created by
+// the managed resource that matches its spec, other than the current one, or
+// an empty string if there is none.
+func (e *metronomeExternal) findReplacement(ctx context.Context, cr *v1alpha1.BillableMetric, current string) (string, error) {
+	uid := string(cr.GetUID())
+	if !e.adopt || uid == "" {
+		return "", nil
+	}
+	for obj, err := range metronomeClient.AllBillableMetrics(ctx, e.metronome, metronomeClient.ListBillableMetricsRequest{}) {
+		if err != nil {
+			return "", err
+		}
+		if obj.ArchivedAt != "" || obj.ID == current || !metronomeClient.IsOwnedBy(obj.CustomFields, uid) {
+			continue
+		}
+		if upToDate, _ := isUpToDate(cr, &obj); upToDate {
+			return obj.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// repointProducts moves every Product that references or uses the billable
+// metric, and follows its replacements, to its replacement at the given
+// time. Only their specs are changed; the product controller submits the
+// change to Metronome. Other Products are left to be moved by their owners,
+// and are reported by archiveReplaced while they use the old one.
+func (e *metronomeExternal) repointProducts(ctx context.Context, cr *v1alpha1.BillableMetric, old, id, startingAt string) error {
+	products := &productv1alpha1.ProductList{}
+	if err := e.kube.List(ctx, products); err != nil {
+		return errors.Wrap(err, errListProducts)
+	}
+
+	start, err := time.Parse(time.RFC3339, startingAt)
+	if err != nil {
+		return errors.Wrap(err, errParseStartingAt)
+	}
+	future := start.After(e.clock.Now())
+
+	for i := range products.Items {
+		p := &products.Items[i]
+		params := &p.Spec.ForProvider
+		ref := params.BillableMetricRef
+		if params.BillableMetricID != old && (ref == nil || ref.Name != cr.GetName()) {
+			continue
+		}
+		if p.GetAnnotations()[productv1alpha1.AnnotationKeyFollowReplacements] != "true" {
+			continue
+		}
+
+		switch {
+		case params.BillableMetricID == id:
+			continue
+		case future:
+			entry := productv1alpha1.ProductScheduleEntry{StartingAt: startingAt, BillableMetricID: id}
+			if slices.ContainsFunc(params.Schedule, func(s productv1alpha1.ProductScheduleEntry) bool {
+				return s.StartingAt == entry.StartingAt && s.BillableMetricID == entry.BillableMetricID
+			}) {
+				continue
+			}
+			params.Schedule = append(params.Schedule, entry)
+		default:
+			params.BillableMetricID = id
+		}
+		if err := e.kube.Update(ctx, p); err != nil {
+			return errors.Wrapf(err, "%s %s", errUpdateProductSpec, p.GetName())
+		}
+	}
+	return nil
+}
+
+// archiveReplaced archives the billable metrics the managed resource replaced.
+// Metronome refuses to archive a billable metric a product still uses, so
+// those are left to be archived by a later reconcile, and the products using
+// them are reported in a condition.
+func (e *metronomeExternal) archiveReplaced(ctx context.Context, cr *v1alpha1.BillableMetric) error {
+	current := meta.GetExternalName(cr)
+	var remaining []string
+	for i, id := range cr.Status.ReplacedIDs {
+		if id == current {
+			// the replacement was interrupted before the external name changed
+			continue
+		}
+		_, err := e.metronome.ArchiveBillableMetric(ctx, id)
+		switch {
+		case err == nil, errors.Is(err, metronomeClient.ErrConflict), errors.Is(err, metronomeClient.ErrNotFound):
+		case errors.Is(err, metronomeClient.ErrValidation):
+			e.logger.Debug("Replaced billable metric is still in use", "id", id)
+			remaining = append(remaining, id)
+		default:
+			cr.Status.ReplacedIDs = append(remaining, cr.Status.ReplacedIDs[i:]...)
+			return errors.Wrap(err, errArchiveBillableMetric)
+		}
+	}
+	cr.Status.ReplacedIDs = remaining
+
+	if len(remaining) == 0 {
+		cr.Status.BlockingProductIDs = nil
+		if cr.GetCondition(metronomev1alpha1.TypeChangeBlocked).Status != corev1.ConditionUnknown {
+			cr.SetConditions(metronomev1alpha1.ChangeUnblocked())
+		}
+		return nil
+	}
+
+	blocking, err := e.productsUsing(ctx, remaining)
+	if err != nil {
+		return errors.Wrap(err, errListProducts)
+	}
+	cr.Status.BlockingProductIDs = blocking
+	msg := fmt.Sprintf("replaced billable metrics %s are still in use", strings.Join(remaining, ", "))
+	if len(blocking) > 0 {
+		msg = fmt.Sprintf("replaced billable metrics %s are still used by products %s, which must be moved to billable metric %s", strings.Join(remaining, ", "), strings.Join(blocking, ", "), current)
+	}
+	cr.SetConditions(metronomev1alpha1.ChangeBlocked(metronomev1alpha1.ReasonReplacedBillableMetricInUse, msg))
+	return nil
+}
+
+// productsUsing returns the IDs of the products that currently use one of the
+// billable metrics.
+func (e *metronomeExternal) productsUsing(ctx context.Context, ids []string) ([]string, error) {
+	var using []string
+	for p, err := range metronomeClient.AllProducts(ctx, e.products, metronomeClient.ListProductsRequest{ArchiveFilter: "NOT_ARCHIVED"}) {
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(ids, p.Current.BillableMetricID) {
+			using = append(using, p.ID)
+		}
+	}
+	slices.Sort(using)
+	return using, nil
 }
 
 func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
@@ -199,7 +516,8 @@ func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (ma
 
 	_, err := e.metronome.ArchiveBillableMetric(ctx, id)
 	if err != nil {
-		if errors.Is(err, metronomeClient.ErrBillableMetricAlreadyArchived) {
+		// the metric has already been archived or no longer exists
+		if errors.Is(err, metronomeClient.ErrConflict) || errors.Is(err, metronomeClient.ErrNotFound) {
 			return managed.ExternalDelete{}, nil
 		}
 		return managed.ExternalDelete{}, errors.Wrap(err, errArchiveBillableMetric)
@@ -213,6 +531,7 @@ func isUpToDate(cr *v1alpha1.BillableMetric, metric *metronomeClient.BillableMet
 
 	converter := &converters.BillableMetricConverterImpl{}
 	params := converter.FromBillableMetricToParameters(metric)
+	params.CustomFields = metronomeClient.WithoutOwner(params.CustomFields)
 
 	sortPropertyFilter := func(a, b v1alpha1.PropertyFilter) int {
 		if a.Name < b.Name {
@@ -2,12 +2,19 @@ package billablemetric
 
 import (
 	"context"
+	"net/http"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -17,7 +24,10 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/redbackthomson/provider-metronome/apis/billablemetric/v1alpha1"
+	productv1alpha1 "github.com/redbackthomson/provider-metronome/apis/product/v1alpha1"
+	metronomev1alpha1 "github.com/redbackthomson/provider-metronome/apis/v1alpha1"
 	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/clients/metronome/metronometest"
 )
 
 const (
@@ -66,7 +76,7 @@ type MockBillableMetricClient struct {
 	ArchiveBillableMetricFn func(ctx context.Context, id string) (*metronomeClient.ArchiveBillableMetricResponse, error)
 	CreateBillableMetricFn  func(ctx context.Context, reqData metronomeClient.CreateBillableMetricRequest) (*metronomeClient.CreateBillableMetricResponse, error)
 	GetBillableMetricFn     func(ctx context.Context, id string) (*metronomeClient.GetBillableMetricResponse, error)
-	ListBillableMetricsFn   func(ctx context.Context) (*metronomeClient.ListBillableMetricsResponse, error)
+	ListBillableMetricsFn   func(ctx context.Context, reqData metronomeClient.ListBillableMetricsRequest, nextPage string) (*metronomeClient.ListBillableMetricsResponse, error)
 	UpdateBillableMetricFn  func(ctx context.Context, id string, reqData metronomeClient.UpdateBillableMetricRequest) (*metronomeClient.UpdateBillableMetricResponse, error)
 }
 
@@ -82,8 +92,8 @@ func (m *MockBillableMetricClient) GetBillableMetric(ctx context.Context, id str
 	return m.GetBillableMetricFn(ctx, id)
 }
 
-func (m *MockBillableMetricClient) ListBillableMetrics(ctx context.Context) (*metronomeClient.ListBillableMetricsResponse, error) {
-	return m.ListBillableMetricsFn(ctx)
+func (m *MockBillableMetricClient) ListBillableMetrics(ctx context.Context, reqData metronomeClient.ListBillableMetricsRequest, nextPage string) (*metronomeClient.ListBillableMetricsResponse, error) {
+	return m.ListBillableMetricsFn(ctx, reqData, nextPage)
 }
 
 func (m *MockBillableMetricClient) UpdateBillableMetric(ctx context.Context, id string, reqData metronomeClient.UpdateBillableMetricRequest) (*metronomeClient.UpdateBillableMetricResponse, error) {
@@ -92,9 +102,53 @@ func (m *MockBillableMetricClient) UpdateBillableMetric(ctx context.Context, id
 
 var _ (metronomeClient.BillableMetricClient) = (*MockBillableMetricClient)(nil)
 
+type MockProductClient struct {
+	ArchiveProductFn func(ctx context.Context, reqData metronomeClient.ArchiveProductRequest) (*metronomeClient.ArchiveProductResponse, error)
+	CreateProductFn  func(ctx context.Context, reqData metronomeClient.CreateProductRequest) (*metronomeClient.CreateProductResponse, error)
+	GetProductFn     func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error)
+	ListProductFn    func(ctx context.Context, reqData metronomeClient.ListProductsRequest, nextPage string) (*metronomeClient.ListProductsResponse, error)
+	UpdateProductFn  func(ctx context.Context, reqData metronomeClient.UpdateProductRequest) (*metronomeClient.UpdateProductResponse, error)
+}
+
+func (m *MockProductClient) ArchiveProduct(ctx context.Context, reqData metronomeClient.ArchiveProductRequest) (*metronomeClient.ArchiveProductResponse, error) {
+	return m.ArchiveProductFn(ctx, reqData)
+}
+
+func (m *MockProductClient) CreateProduct(ctx context.Context, reqData metronomeClient.CreateProductRequest) (*metronomeClient.CreateProductResponse, error) {
+	return m.CreateProductFn(ctx, reqData)
+}
+
+func (m *MockProductClient) GetProduct(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+	return m.GetProductFn(ctx, reqData)
+}
+
+func (m *MockProductClient) ListProduct(ctx context.Context, reqData metronomeClient.ListProductsRequest, nextPage string) (*metronomeClient.ListProductsResponse, error) {
+	return m.ListProductFn(ctx, reqData, nextPage)
+}
+
+func (m *MockProductClient) UpdateProduct(ctx context.Context, reqData metronomeClient.UpdateProductRequest) (*metronomeClient.UpdateProductResponse, error) {
+	return m.UpdateProductFn(ctx, reqData)
+}
+
+var _ (metronomeClient.ProductClient) = (*MockProductClient)(nil)
+
 func Test_External_Observe(t *testing.T) {
+	current := func(ctx context.Context, id string) (*metronomeClient.GetBillableMetricResponse, error) {
+		return &metronomeClient.GetBillableMetricResponse{Data: metronomeClient.BillableMetric{ID: id}}, nil
+	}
+	inUse := func(mg *v1alpha1.BillableMetric) {
+		mg.Status.ReplacedIDs = []string{"replaced"}
+		mg.SetConditions(metronomev1alpha1.ChangeBlocked(metronomev1alpha1.ReasonReplacedBillableMetricInUse, "in use"))
+	}
+	blocked := func(mg *v1alpha1.BillableMetric) {
+		inUse(mg)
+		mg.Status.BlockingProductIDs = []string{"p1", "p2"}
+	}
+
 	type args struct {
+		adopt     bool
 		metronome metronomeClient.BillableMetricClient
+		products  metronomeClient.ProductClient
 		mg        resource.Managed
 	}
 	type want struct {
@@ -143,6 +197,121 @@ func Test_External_Observe(t *testing.T) {
 				err: nil,
 			},
 		},
+		"AdoptionDisabled": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					ListBillableMetricsFn: func(ctx context.Context, reqData metronomeClient.ListBillableMetricsRequest, nextPage string) (*metronomeClient.ListBillableMetricsResponse, error) {
+						t.Errorf("ListBillableMetrics(...): want no lookup")
+						return nil, errBoom
+					},
+				},
+				mg: billableMetric(func(mg *v1alpha1.BillableMetric) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"CreateNotAttempted": {
+			args: args{
+				adopt: true,
+				metronome: &MockBillableMetricClient{
+					ListBillableMetricsFn: func(ctx context.Context, reqData metronomeClient.ListBillableMetricsRequest, nextPage string) (*metronomeClient.ListBillableMetricsResponse, error) {
+						t.Errorf("ListBillableMetrics(...): want no lookup")
+						return nil, errBoom
+					},
+				},
+				mg: billableMetric(func(mg *v1alpha1.BillableMetric) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToFindOwnedBillableMetric": {
+			args: args{
+				adopt: true,
+				metronome: &MockBillableMetricClient{
+					ListBillableMetricsFn: func(ctx context.Context, reqData metronomeClient.ListBillableMetricsRequest, nextPage string) (*metronomeClient.ListBillableMetricsResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: billableMetric(func(mg *v1alpha1.BillableMetric) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errAdoptBillableMetric),
+			},
+		},
+		"NoOwnedBillableMetric": {
+			args: args{
+				adopt: true,
+				metronome: &MockBillableMetricClient{
+					ListBillableMetricsFn: func(ctx context.Context, reqData metronomeClient.ListBillableMetricsRequest, nextPage string) (*metronomeClient.ListBillableMetricsResponse, error) {
+						return &metronomeClient.ListBillableMetricsResponse{
+							Data: []metronomeClient.BillableMetric{
+								{ID: "id1", Name: "name"},
+								{ID: "id2", Name: "other-name", CustomFields: map[string]string{metronomeClient.OwnerCustomFieldKey: "uid"}},
+								{ID: "id3", Name: "name", CustomFields: map[string]string{metronomeClient.OwnerCustomFieldKey: "uid"}, ArchivedAt: "2025-01-01T00:00:00Z"},
+							},
+						}, nil
+					},
+				},
+				mg: billableMetric(func(mg *v1alpha1.BillableMetric) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+					mg.Spec.ForProvider.Name = "name"
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"AdoptsOwnedBillableMetric": {
+			args: args{
+				adopt: true,
+				metronome: &MockBillableMetricClient{
+					ListBillableMetricsFn: func(ctx context.Context, reqData metronomeClient.ListBillableMetricsRequest, nextPage string) (*metronomeClient.ListBillableMetricsResponse, error) {
+						return &metronomeClient.ListBillableMetricsResponse{
+							Data: []metronomeClient.BillableMetric{
+								{ID: "id1", Name: "name", AggregationType: metronomeClient.AggregationCount, CustomFields: map[string]string{metronomeClient.OwnerCustomFieldKey: "uid"}},
+							},
+						}, nil
+					},
+					GetBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.GetBillableMetricResponse, error) {
+						if id != "id1" {
+							return nil, errBoom
+						}
+						return &metronomeClient.GetBillableMetricResponse{
+							Data: metronomeClient.BillableMetric{
+								ID: "id1", Name: "name", AggregationType: metronomeClient.AggregationCount, CustomFields: map[string]string{metronomeClient.OwnerCustomFieldKey: "uid"},
+							},
+						}, nil
+					},
+				},
+				mg: billableMetric(func(mg *v1alpha1.BillableMetric) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+					mg.Spec.ForProvider = v1alpha1.BillableMetricParameters{
+						Name:            "name",
+						AggregationType: v1alpha1.AggregationTypeCount,
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
 		"InvalidName": {
 			args: args{
 				metronome: &MockBillableMetricClient{
@@ -214,12 +383,96 @@ func Test_External_Observe(t *testing.T) {
 				err: nil,
 			},
 		},
+		"ReplacedNotArchived": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: current,
+				},
+				mg: billableMetric(func(mg *v1alpha1.BillableMetric) {
+					mg.Status.ReplacedIDs = []string{"replaced"}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"ReplacedInUseByUnknown": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: current,
+				},
+				mg: billableMetric(inUse),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"ReplacedStillBlocked": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: current,
+				},
+				products: &MockProductClient{
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						return &metronomeClient.GetProductResponse{Data: metronomeClient.Product{
+							ID:      reqData.ID,
+							Current: metronomeClient.ProductDetails{BillableMetricID: "replaced"},
+						}}, nil
+					},
+				},
+				mg: billableMetric(blocked),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"BlockingProductMoved": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: current,
+				},
+				products: &MockProductClient{
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						id := "replaced"
+						if reqData.ID == "p2" {
+							id = "external-name"
+						}
+						return &metronomeClient.GetProductResponse{Data: metronomeClient.Product{
+							ID:      reqData.ID,
+							Current: metronomeClient.ProductDetails{BillableMetricID: id},
+						}}, nil
+					},
+				},
+				mg: billableMetric(blocked),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"FailedToGetBlockingProduct": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: current,
+				},
+				products: &MockProductClient{
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: billableMetric(blocked),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetProduct),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			e := &metronomeExternal{
+				adopt:     tc.args.adopt,
 				logger:    logging.NewNopLogger(),
 				metronome: tc.args.metronome,
+				products:  tc.args.products,
 			}
 			got, gotErr := e.Observe(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
@@ -318,3 +571,476 @@ func Test_External_Create(t *testing.T) {
 		})
 	}
 }
+
+func Test_External_Update(t *testing.T) {
+	errInUse := &metronomeClient.APIError{StatusCode: http.StatusBadRequest, Message: "Billable metric is in use"}
+	replace := func(mg *v1alpha1.BillableMetric) {
+		mg.Spec.Replacement = &v1alpha1.Replacement{Policy: v1alpha1.ReplacementPolicyReplace}
+	}
+	changed := func(ctx context.Context, id string) (*metronomeClient.GetBillableMetricResponse, error) {
+		return &metronomeClient.GetBillableMetricResponse{Data: metronomeClient.BillableMetric{
+			ID:   id,
+			Name: "old-name",
+		}}, nil
+	}
+
+	created := func(ctx context.Context, reqData metronomeClient.CreateBillableMetricRequest) (*metronomeClient.CreateBillableMetricResponse, error) {
+		return &metronomeClient.CreateBillableMetricResponse{Data: metronomeClient.BillableMetric{ID: "new-id"}}, nil
+	}
+	follow := map[string]string{productv1alpha1.AnnotationKeyFollowReplacements: "true"}
+	using := func(ctx context.Context, reqData metronomeClient.ListProductsRequest, nextPage string) (*metronomeClient.ListProductsResponse, error) {
+		return &metronomeClient.ListProductsResponse{Data: []metronomeClient.Product{
+			{ID: "p2", Current: metronomeClient.ProductDetails{BillableMetricID: "in-use"}},
+			{ID: "p1", Current: metronomeClient.ProductDetails{BillableMetricID: "in-use"}},
+			{ID: "p3", Current: metronomeClient.ProductDetails{BillableMetricID: "external-name"}},
+		}}, nil
+	}
+
+	type args struct {
+		metronome metronomeClient.BillableMetricClient
+		products  metronomeClient.ProductClient
+		kube      client.Client
+		mg        resource.Managed
+	}
+	type want struct {
+		out      managed.ExternalUpdate
+		err      error
+		replaced []string
+		blocking []string
+		blocked  corev1.ConditionStatus
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotBillableMetricResource": {
+			args: args{
+				mg: notBillableMetricResource{},
+			},
+			want: want{
+				err: errors.New(errNotBillableMetric),
+			},
+		},
+		"FailedToGetBillableMetric": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.GetBillableMetricResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: billableMetric(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetBillableMetric),
+			},
+		},
+		"ReplacementNotAllowed": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: changed,
+				},
+				mg: billableMetric(),
+			},
+			want: want{
+				err: errors.New(errUpdateNotSupported),
+			},
+		},
+		"FailedToCreateReplacement": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: changed,
+					CreateBillableMetricFn: func(ctx context.Context, reqData metronomeClient.CreateBillableMetricRequest) (*metronomeClient.CreateBillableMetricResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: billableMetric(replace),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateBillableMetric),
+			},
+		},
+		"FailedToListProducts": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: changed,
+					CreateBillableMetricFn: func(ctx context.Context, reqData metronomeClient.CreateBillableMetricRequest) (*metronomeClient.CreateBillableMetricResponse, error) {
+						return &metronomeClient.CreateBillableMetricResponse{Data: metronomeClient.BillableMetric{ID: "new-id"}}, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				},
+				mg: billableMetric(replace),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListProducts),
+			},
+		},
+		"FailedToSaveReplacedID": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn:    changed,
+					CreateBillableMetricFn: created,
+				},
+				kube: &test.MockClient{
+					MockList:         test.NewMockListFn(nil),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+					MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+						t.Errorf("Update(...): want the external name kept until the replaced ID is saved")
+						return nil
+					},
+				},
+				mg: billableMetric(replace),
+			},
+			want: want{
+				err:      errors.Wrap(errBoom, errSaveReplacedID),
+				replaced: []string{"external-name"},
+			},
+		},
+		"SchedulesFutureReplacement": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn:    changed,
+					CreateBillableMetricFn: created,
+					ArchiveBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.ArchiveBillableMetricResponse, error) {
+						return nil, errInUse
+					},
+				},
+				products: &MockProductClient{
+					ListProductFn: func(ctx context.Context, reqData metronomeClient.ListProductsRequest, nextPage string) (*metronomeClient.ListProductsResponse, error) {
+						return &metronomeClient.ListProductsResponse{}, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+						obj.(*productv1alpha1.ProductList).Items = []productv1alpha1.Product{
+							{ObjectMeta: metav1.ObjectMeta{Name: "selected", Annotations: follow}, Spec: productv1alpha1.ProductSpec{ForProvider: productv1alpha1.ProductParameters{BillableMetricID: "external-name"}}},
+							{ObjectMeta: metav1.ObjectMeta{Name: "not-following"}, Spec: productv1alpha1.ProductSpec{ForProvider: productv1alpha1.ProductParameters{BillableMetricID: "external-name"}}},
+							{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}, Spec: productv1alpha1.ProductSpec{ForProvider: productv1alpha1.ProductParameters{BillableMetricID: "other"}}},
+						}
+						return nil
+					},
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+						p, ok := obj.(*productv1alpha1.Product)
+						if !ok {
+							return nil
+						}
+						want := productv1alpha1.ProductParameters{
+							BillableMetricID: "external-name",
+							Schedule:         []productv1alpha1.ProductScheduleEntry{{StartingAt: "2025-04-01T00:00:00Z", BillableMetricID: "new-id"}},
+						}
+						if diff := cmp.Diff(want, p.Spec.ForProvider); p.GetName() != "selected" || diff != "" {
+							t.Errorf("Update(%s): -want product spec, +got product spec: %s", p.GetName(), diff)
+						}
+						return nil
+					},
+				},
+				mg: billableMetric(replace, func(mg *v1alpha1.BillableMetric) {
+					mg.Spec.Replacement.StartingAt = "2025-04-01T00:00:00Z"
+				}),
+			},
+			want: want{
+				replaced: []string{"external-name"},
+				blocked:  corev1.ConditionTrue,
+			},
+		},
+		"KeepsReplacedInUse": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.GetBillableMetricResponse, error) {
+						return &metronomeClient.GetBillableMetricResponse{Data: metronomeClient.BillableMetric{ID: id}}, nil
+					},
+					ArchiveBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.ArchiveBillableMetricResponse, error) {
+						if id == "in-use" {
+							return nil, errInUse
+						}
+						return &metronomeClient.ArchiveBillableMetricResponse{}, nil
+					},
+				},
+				products: &MockProductClient{
+					ListProductFn: using,
+				},
+				mg: billableMetric(func(mg *v1alpha1.BillableMetric) {
+					mg.Status.ReplacedIDs = []string{"unused", "in-use"}
+				}),
+			},
+			want: want{
+				replaced: []string{"in-use"},
+				blocking: []string{"p1", "p2"},
+				blocked:  corev1.ConditionTrue,
+			},
+		},
+		"FailedToListBlockingProducts": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.GetBillableMetricResponse, error) {
+						return &metronomeClient.GetBillableMetricResponse{Data: metronomeClient.BillableMetric{ID: id}}, nil
+					},
+					ArchiveBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.ArchiveBillableMetricResponse, error) {
+						return nil, errInUse
+					},
+				},
+				products: &MockProductClient{
+					ListProductFn: func(ctx context.Context, reqData metronomeClient.ListProductsRequest, nextPage string) (*metronomeClient.ListProductsResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: billableMetric(func(mg *v1alpha1.BillableMetric) {
+					mg.Status.ReplacedIDs = []string{"in-use"}
+				}),
+			},
+			want: want{
+				err:      errors.Wrap(errBoom, errListProducts),
+				replaced: []string{"in-use"},
+			},
+		},
+		"ArchivesReplacedNoLongerInUse": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.GetBillableMetricResponse, error) {
+						return &metronomeClient.GetBillableMetricResponse{Data: metronomeClient.BillableMetric{ID: id}}, nil
+					},
+					ArchiveBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.ArchiveBillableMetricResponse, error) {
+						if id == "external-name" {
+							t.Errorf("ArchiveBillableMetric(%s): want the current billable metric kept", id)
+						}
+						return &metronomeClient.ArchiveBillableMetricResponse{}, nil
+					},
+				},
+				mg: billableMetric(func(mg *v1alpha1.BillableMetric) {
+					mg.Status.ReplacedIDs = []string{"external-name", "in-use"}
+					mg.Status.BlockingProductIDs = []string{"p1"}
+					mg.SetConditions(metronomev1alpha1.ChangeBlocked(metronomev1alpha1.ReasonReplacedBillableMetricInUse, "in use"))
+				}),
+			},
+			want: want{
+				blocked: corev1.ConditionFalse,
+			},
+		},
+		"FailedToArchiveReplaced": {
+			args: args{
+				metronome: &MockBillableMetricClient{
+					GetBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.GetBillableMetricResponse, error) {
+						return &metronomeClient.GetBillableMetricResponse{Data: metronomeClient.BillableMetric{ID: id}}, nil
+					},
+					ArchiveBillableMetricFn: func(ctx context.Context, id string) (*metronomeClient.ArchiveBillableMetricResponse, error) {
+						if id == "in-use" {
+							return nil, errInUse
+						}
+						return nil, errBoom
+					},
+				},
+				mg: billableMetric(func(mg *v1alpha1.BillableMetric) {
+					mg.Status.ReplacedIDs = []string{"in-use", "broken", "unused"}
+				}),
+			},
+			want: want{
+				err:      errors.Wrap(errBoom, errArchiveBillableMetric),
+				replaced: []string{"in-use", "broken", "unused"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:    logging.NewNopLogger(),
+				metronome: tc.args.metronome,
+				products:  tc.args.products,
+				kube:      tc.args.kube,
+				clock:     clocktesting.NewFakePassiveClock(time.Date(2025, 3, 1, 12, 34, 56, 0, time.UTC)),
+			}
+			got, gotErr := e.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Update(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Fatalf("e.Update(...): -want out, +got out: %s", diff)
+			}
+
+			if cr, ok := tc.args.mg.(*v1alpha1.BillableMetric); ok {
+				if diff := cmp.Diff(tc.want.replaced, cr.Status.ReplacedIDs); diff != "" {
+					t.Errorf("e.Update(...): -want replaced IDs, +got replaced IDs: %s", diff)
+				}
+				if diff := cmp.Diff(tc.want.blocking, cr.Status.BlockingProductIDs); diff != "" {
+					t.Errorf("e.Update(...): -want blocking products, +got blocking products: %s", diff)
+				}
+				want := tc.want.blocked
+				if want == "" {
+					want = corev1.ConditionUnknown
+				}
+				if diff := cmp.Diff(want, cr.GetCondition(metronomev1alpha1.TypeChangeBlocked).Status); diff != "" {
+					t.Errorf("e.Update(...): -want blocked condition, +got blocked condition: %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_MetronomeExternal_Replacement(t *testing.T) {
+	ctx := context.Background()
+	clk := clocktesting.NewFakePassiveClock(time.Date(2025, 3, 1, 12, 34, 56, 0, time.UTC))
+
+	srv := metronometest.NewServer(metronometest.WithClock(clk))
+	defer srv.Close()
+
+	mc := srv.NewClient()
+
+	cr := billableMetric(func(mg *v1alpha1.BillableMetric) {
+		meta.SetExternalName(mg, "")
+		mg.SetUID("0b7d1c9e-5a4f-4e3d-9c2b-1a0f9e8d7c6b")
+		mg.Spec.ForProvider = v1alpha1.BillableMetricParameters{
+			Name:            "API calls",
+			AggregationType: v1alpha1.AggregationTypeCount,
+			EventTypeFilter: v1alpha1.EventTypeFilter{InValues: []string{"api_call"}},
+			GroupKeys:       [][]string{{"region"}},
+		}
+		mg.Spec.Replacement = &v1alpha1.Replacement{Policy: v1alpha1.ReplacementPolicyReplace}
+	})
+
+	products := []productv1alpha1.Product{{}, {}, {}}
+	updated := map[string]string{}
+	kube := &test.MockClient{
+		MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			obj.(*productv1alpha1.ProductList).Items = products
+			return nil
+		},
+		MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			if p, ok := obj.(*productv1alpha1.Product); ok {
+				updated[p.GetName()] = p.Spec.ForProvider.BillableMetricID
+			}
+			return nil
+		},
+		MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+	}
+
+	e := &metronomeExternal{
+		logger:    logging.NewNopLogger(),
+		metronome: mc.BillableMetric(),
+		keys:      mc.CustomFieldKey(),
+		products:  mc.Product(),
+		kube:      kube,
+		clock:     clk,
+		adopt:     true,
+	}
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	old := meta.GetExternalName(cr)
+
+	// one product references the billable metric and follows its
+	// replacements, one was given its ID directly, and one exists only in
+	// Metronome
+	ids := map[string]string{}
+	for _, name := range []string{"referencing", "direct", "unmanaged"} {
+		p, err := mc.Product().CreateProduct(ctx, metronomeClient.CreateProductRequest{Name: name, Type: "USAGE", BillableMetricID: old})
+		if err != nil {
+			t.Fatalf("CreateProduct(...): %v", err)
+		}
+		ids[name] = p.Data.ID
+	}
+	other, err := mc.Product().CreateProduct(ctx, metronomeClient.CreateProductRequest{Name: "unrelated", Type: "FIXED"})
+	if err != nil {
+		t.Fatalf("CreateProduct(...): %v", err)
+	}
+	for i, name := range []string{"referencing", "direct"} {
+		products[i].SetName(name)
+		meta.SetExternalName(&products[i], ids[name])
+		products[i].Spec.ForProvider.BillableMetricID = old
+	}
+	products[0].Spec.ForProvider.BillableMetricRef = &xpv1.Reference{Name: cr.GetName()}
+	products[0].SetAnnotations(map[string]string{productv1alpha1.AnnotationKeyFollowReplacements: "true"})
+	products[2].SetName("unrelated")
+	meta.SetExternalName(&products[2], other.Data.ID)
+
+	cr.Spec.ForProvider.GroupKeys = [][]string{{"region", "zone"}}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and not up to date, got %+v, %v", o, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+
+	id := meta.GetExternalName(cr)
+	if id == old {
+		t.Fatalf("Update(...): want a new external name, got the old one %q", old)
+	}
+
+	// only the following product is moved, and the product controller moves
+	// it in Metronome
+	if diff := cmp.Diff(map[string]string{"referencing": id}, updated); diff != "" {
+		t.Errorf("Update(...): -want product specs, +got product specs: %s", diff)
+	}
+	for name, pid := range ids {
+		res, err := mc.Product().GetProduct(ctx, metronomeClient.GetProductRequest{ID: pid})
+		if err != nil {
+			t.Fatalf("GetProduct(...): %v", err)
+		}
+		if got := res.Data.Current.BillableMetricID; got != old || len(res.Data.Updates) != 0 {
+			t.Errorf("GetProduct(%s): want the billable metric left to the product controller, got %q, updates %+v", name, got, res.Data.Updates)
+		}
+	}
+
+	// the old billable metric is still used by every product
+	if diff := cmp.Diff([]string{old}, cr.Status.ReplacedIDs); diff != "" {
+		t.Errorf("Update(...): -want replaced IDs, +got replaced IDs: %s", diff)
+	}
+	blocking := []string{ids["referencing"], ids["direct"], ids["unmanaged"]}
+	slices.Sort(blocking)
+	if diff := cmp.Diff(blocking, cr.Status.BlockingProductIDs); diff != "" {
+		t.Errorf("Update(...): -want blocking products, +got blocking products: %s", diff)
+	}
+	if c := cr.GetCondition(metronomev1alpha1.TypeChangeBlocked); c.Status != corev1.ConditionTrue || c.Reason != metronomev1alpha1.ReasonReplacedBillableMetricInUse || !strings.Contains(c.Message, id) {
+		t.Errorf("Update(...): want the change blocked by products in use, got %+v", c)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date while the products are unchanged, got %+v, %v", o, err)
+	}
+
+	move := func(name string) {
+		if _, err := mc.Product().UpdateProduct(ctx, metronomeClient.UpdateProductRequest{
+			ProductID:        ids[name],
+			StartingAt:       "2025-03-01T12:00:00Z",
+			BillableMetricID: id,
+		}); err != nil {
+			t.Fatalf("UpdateProduct(...): %v", err)
+		}
+	}
+	move("referencing")
+	move("direct")
+	if o, err := e.Observe(ctx, cr); err != nil || o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want not up to date once a product moved, got %+v, %v", o, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{ids["unmanaged"]}, cr.Status.BlockingProductIDs); diff != "" {
+		t.Errorf("Update(...): -want blocking products, +got blocking products: %s", diff)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date while the products are unchanged, got %+v, %v", o, err)
+	}
+
+	move("unmanaged")
+	if o, err := e.Observe(ctx, cr); err != nil || o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want not up to date once a product moved, got %+v, %v", o, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if len(cr.Status.ReplacedIDs) != 0 || len(cr.Status.BlockingProductIDs) != 0 {
+		t.Errorf("Update(...): want no replaced IDs once archived, got %v, blocked by %v", cr.Status.ReplacedIDs, cr.Status.BlockingProductIDs)
+	}
+	if c := cr.GetCondition(metronomev1alpha1.TypeChangeBlocked); c.Status != corev1.ConditionFalse {
+		t.Errorf("Update(...): want the change unblocked, got %+v", c)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and up to date, got %+v, %v", o, err)
+	}
+	res, err := mc.BillableMetric().GetBillableMetric(ctx, old)
+	if err != nil || res.Data.ArchivedAt == "" {
+		t.Errorf("GetBillableMetric(...): want the old billable metric archived, got %+v, %v", res, err)
+	}
+}
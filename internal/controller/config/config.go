@@ -17,7 +17,14 @@ limitations under the License.
 package config
 
 import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -26,11 +33,13 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/redbackthomson/provider-metronome/apis/v1alpha1"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/connector"
 )
 
 // Setup adds a controller that reconciles ProviderConfigs by accounting for
-// their current usage.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+// their current usage, and a controller that checks their credentials.
+func Setup(mgr ctrl.Manager, o controller.Options, co connector.Options) error {
 	name := providerconfig.ControllerName(v1alpha1.ProviderConfigGroupKind)
 
 	of := resource.ProviderConfigKinds{
@@ -42,10 +51,44 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		providerconfig.WithLogger(o.Logger.WithValues("controller", name)),
 		providerconfig.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
 
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha1.ProviderConfig{}).
 		Watches(&v1alpha1.ProviderConfigUsage{}, &resource.EnqueueRequestForProviderConfig{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter)); err != nil {
+		return err
+	}
+
+	return setupHealthCheck(mgr, o, co)
+}
+
+func setupHealthCheck(mgr ctrl.Manager, o controller.Options, co connector.Options) error {
+	name := providerconfig.ControllerName(v1alpha1.ProviderConfigGroupKind) + "/health"
+
+	h := &healthChecker{
+		kube: mgr.GetClient(),
+		newClient: func(ctx context.Context, pc *v1alpha1.ProviderConfig) (*metronomeClient.Client, error) {
+			return connector.NewClient(ctx, mgr.GetClient(), pc, co, o.Logger)
+		},
+		clients:  co.Clients,
+		record:   event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
+		log:      o.Logger.WithValues("controller", name),
+		interval: o.PollInterval,
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.ProviderConfig{}, secretRefIndex, secretRefKeys); err != nil {
+		return errors.Wrap(err, errIndexSecretRef)
+	}
+
+	// status updates, including those made by the health check, don't
+	// change the generation and so don't trigger another check. Secrets are
+	// only watched by their metadata, which is all that's needed to find the
+	// ProviderConfigs that reference them.
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.ProviderConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(h.providerConfigsForSecret)).
+		Complete(ratelimiter.NewReconciler(name, h, o.GlobalRateLimiter))
 }
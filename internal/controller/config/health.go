@@ -0,0 +1,169 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/redbackthomson/provider-metronome/apis/v1alpha1"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/connector"
+)
+
+const (
+	errGetProviderConfig = "cannot get provider config"
+	errListConfigs       = "cannot list provider configs"
+	errUpdateStatus      = "cannot update provider config status"
+	errIndexSecretRef    = "cannot index provider configs by credentials secret"
+)
+
+// secretRefIndex indexes ProviderConfigs by the namespace and name of the
+// Secret they read their credentials from.
+const secretRefIndex = "spec.credentials.secretRef"
+
+// healthChecker reconciles ProviderConfigs by checking that Metronome accepts
+// their credentials, and records the result in the CredentialsValid
+// condition. Each ProviderConfig is checked again after the interval, so
+// revoked credentials are noticed even if nothing changes in the cluster.
+type healthChecker struct {
+	kube      client.Client
+	newClient func(ctx context.Context, pc *v1alpha1.ProviderConfig) (*metronomeClient.Client, error)
+	clients   *connector.ClientCache
+	record    event.Recorder
+	log       logging.Logger
+	interval  time.Duration
+}
+
+func (h *healthChecker) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := h.log.WithValues("request", req)
+
+	pc := &v1alpha1.ProviderConfig{}
+	if err := h.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetProviderConfig)
+	}
+	if meta.WasDeleted(pc) {
+		if h.clients != nil {
+			h.clients.Remove(pc.GetUID())
+		}
+		return reconcile.Result{}, nil
+	}
+
+	cond := h.check(ctx, pc)
+	if prev := pc.GetCondition(v1alpha1.TypeCredentialsValid); !prev.Equal(cond) {
+		pc.SetConditions(cond)
+		if err := h.kube.Status().Update(ctx, pc); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+		}
+
+		// only changes are recorded, so a persistent problem isn't
+		// reported every interval
+		if cond.Status == corev1.ConditionTrue {
+			log.Debug("Credentials accepted by Metronome")
+			h.record.Event(pc, event.Normal(event.Reason(cond.Reason), "Credentials accepted by Metronome"))
+		} else {
+			log.Debug("Credentials not accepted by Metronome", "reason", cond.Reason, "error", cond.Message)
+			h.record.Event(pc, event.Warning(event.Reason(cond.Reason), errors.New(cond.Message)))
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: h.interval}, nil
+}
+
+// check makes a cheap authenticated request with the credentials of the
+// ProviderConfig, and returns the resulting CredentialsValid condition.
+func (h *healthChecker) check(ctx context.Context, pc *v1alpha1.ProviderConfig) xpv1.Condition {
+	c, err := h.newClient(ctx, pc)
+	if err != nil {
+		return v1alpha1.CredentialsInvalid(v1alpha1.ReasonCredentialsUnavailable, err.Error())
+	}
+	defer c.Close()
+	if _, err := c.CustomFieldKey().ListCustomFieldKeys(ctx, metronomeClient.ListCustomFieldKeysRequest{}, ""); err != nil {
+		return v1alpha1.CredentialsInvalid(failureReason(err), err.Error())
+	}
+	return v1alpha1.CredentialsValid()
+}
+
+// failureReason classifies the error of a failed health check request.
+func failureReason(err error) xpv1.ConditionReason {
+	var (
+		verifyErr    *tls.CertificateVerificationError
+		recordErr    tls.RecordHeaderError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+		netErr       net.Error
+	)
+	switch {
+	case errors.Is(err, metronomeClient.ErrUnauthorized):
+		return v1alpha1.ReasonUnauthorized
+	case errors.As(err, &verifyErr), errors.As(err, &recordErr), errors.As(err, &authorityErr),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return v1alpha1.ReasonTLSError
+	case errors.As(err, &netErr):
+		return v1alpha1.ReasonUnreachable
+	}
+	return v1alpha1.ReasonCheckFailed
+}
+
+// providerConfigsForSecret returns a request for every ProviderConfig that
+// reads its credentials from the Secret, so rotated credentials are checked
+// right away.
+func (h *healthChecker) providerConfigsForSecret(ctx context.Context, o client.Object) []reconcile.Request {
+	l := &v1alpha1.ProviderConfigList{}
+	if err := h.kube.List(ctx, l, client.MatchingFields{secretRefIndex: secretKey(o.GetNamespace(), o.GetName())}); err != nil {
+		h.log.Debug(errListConfigs, "error", err)
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for _, pc := range l.Items {
+		ref := pc.Spec.Credentials.SecretRef
+		if ref != nil && ref.Name == o.GetName() && ref.Namespace == o.GetNamespace() {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&pc)})
+		}
+	}
+	return reqs
+}
+
+// secretRefKeys returns the secretRefIndex keys of a ProviderConfig.
+func secretRefKeys(o client.Object) []string {
+	pc, ok := o.(*v1alpha1.ProviderConfig)
+	if !ok || pc.Spec.Credentials.SecretRef == nil {
+		return nil
+	}
+	ref := pc.Spec.Credentials.SecretRef
+	return []string{secretKey(ref.Namespace, ref.Name)}
+}
+
+func secretKey(namespace, name string) string {
+	return namespace + "/" + name
+}
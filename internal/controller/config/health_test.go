@@ -0,0 +1,219 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/redbackthomson/provider-metronome/apis/v1alpha1"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/clients/metronome/metronometest"
+)
+
+const (
+	providerConfigName = "metronome-test"
+	testNamespace      = "testns"
+)
+
+var (
+	errBoom = errors.New("boom")
+)
+
+func Test_HealthChecker_Reconcile(t *testing.T) {
+	srv := metronometest.NewServer()
+	defer srv.Close()
+
+	tlsSrv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsSrv.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	clientFor := func(url, token string) func(ctx context.Context, pc *v1alpha1.ProviderConfig) (*metronomeClient.Client, error) {
+		return func(ctx context.Context, pc *v1alpha1.ProviderConfig) (*metronomeClient.Client, error) {
+			return metronomeClient.New(logging.NewNopLogger(), url, token, metronomeClient.WithMaxAttempts(1))
+		}
+	}
+
+	type args struct {
+		kube      *test.MockClient
+		newClient func(ctx context.Context, pc *v1alpha1.ProviderConfig) (*metronomeClient.Client, error)
+		existing  []xpv1.Condition
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+		// cond is the condition written to the status, if any.
+		cond *xpv1.Condition
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"FailedToGetProviderConfig": {
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetProviderConfig),
+			},
+		},
+		"Accepted": {
+			args: args{
+				newClient: clientFor(srv.URL, metronometest.DefaultAuthToken),
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				cond:   ptr.To(v1alpha1.CredentialsValid()),
+			},
+		},
+		"AlreadyAccepted": {
+			args: args{
+				newClient: clientFor(srv.URL, metronometest.DefaultAuthToken),
+				existing:  []xpv1.Condition{v1alpha1.CredentialsValid()},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+			},
+		},
+		"Unauthorized": {
+			args: args{
+				newClient: clientFor(srv.URL, "revoked"),
+				existing:  []xpv1.Condition{v1alpha1.CredentialsValid()},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				cond:   ptr.To(v1alpha1.CredentialsInvalid(v1alpha1.ReasonUnauthorized, "")),
+			},
+		},
+		"TLSError": {
+			args: args{
+				newClient: clientFor(tlsSrv.URL, metronometest.DefaultAuthToken),
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				cond:   ptr.To(v1alpha1.CredentialsInvalid(v1alpha1.ReasonTLSError, "")),
+			},
+		},
+		"Unreachable": {
+			args: args{
+				newClient: clientFor(closed.URL, metronometest.DefaultAuthToken),
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				cond:   ptr.To(v1alpha1.CredentialsInvalid(v1alpha1.ReasonUnreachable, "")),
+			},
+		},
+		"CredentialsUnavailable": {
+			args: args{
+				newClient: func(ctx context.Context, pc *v1alpha1.ProviderConfig) (*metronomeClient.Client, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				cond:   ptr.To(v1alpha1.CredentialsInvalid(v1alpha1.ReasonCredentialsUnavailable, "")),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written *xpv1.Condition
+			kube := tc.args.kube
+			if kube == nil {
+				kube = &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						pc := obj.(*v1alpha1.ProviderConfig)
+						pc.SetName(providerConfigName)
+						pc.SetConditions(tc.args.existing...)
+						return nil
+					},
+					MockStatusUpdate: func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+						// the message is the error of the check, which
+						// depends on the test environment
+						c := obj.(*v1alpha1.ProviderConfig).GetCondition(v1alpha1.TypeCredentialsValid)
+						c.Message = ""
+						written = &c
+						return nil
+					},
+				}
+			}
+
+			h := &healthChecker{
+				kube:      kube,
+				newClient: tc.args.newClient,
+				record:    event.NewNopRecorder(),
+				log:       logging.NewNopLogger(),
+				interval:  time.Minute,
+			}
+			got, err := h.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKey{Name: providerConfigName}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("h.Reconcile(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("h.Reconcile(...): -want result, +got result: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cond, written); diff != "" {
+				t.Errorf("h.Reconcile(...): -want condition, +got condition: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_HealthChecker_ProviderConfigsForSecret(t *testing.T) {
+	secretRef := func(namespace, name string) v1alpha1.ProviderConfig {
+		pc := v1alpha1.ProviderConfig{}
+		pc.SetName(namespace + "-" + name)
+		pc.Spec.Credentials.SecretRef = &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: namespace, Name: name},
+			Key:             "key",
+		}
+		return pc
+	}
+
+	h := &healthChecker{
+		kube: &test.MockClient{
+			MockList: func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+				want := []client.ListOption{client.MatchingFields{secretRefIndex: testNamespace + "/creds"}}
+				if diff := cmp.Diff(want, opts); diff != "" {
+					t.Errorf("kube.List(...): -want options, +got options: %s", diff)
+				}
+				obj.(*v1alpha1.ProviderConfigList).Items = []v1alpha1.ProviderConfig{
+					secretRef(testNamespace, "creds"),
+					secretRef("other", "creds"),
+					{ObjectMeta: metav1.ObjectMeta{Name: "no-secret"}},
+				}
+				return nil
+			},
+		},
+		log: logging.NewNopLogger(),
+	}
+
+	secret := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "creds"}}
+	want := []reconcile.Request{{NamespacedName: client.ObjectKey{Name: testNamespace + "-creds"}}}
+	if diff := cmp.Diff(want, h.providerConfigsForSecret(context.Background(), secret)); diff != "" {
+		t.Errorf("h.providerConfigsForSecret(...): -want, +got: %s", diff)
+	}
+
+	pc := secretRef(testNamespace, "creds")
+	if diff := cmp.Diff([]string{testNamespace + "/creds"}, secretRefKeys(&pc)); diff != "" {
+		t.Errorf("secretRefKeys(...): -want, +got: %s", diff)
+	}
+	if keys := secretRefKeys(&v1alpha1.ProviderConfig{}); keys != nil {
+		t.Errorf("secretRefKeys(...): want no keys without a secret, got %v", keys)
+	}
+}
@@ -0,0 +1,381 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
+
+	"github.com/redbackthomson/provider-metronome/apis/contract/v1alpha1"
+	metronomev1alpha1 "github.com/redbackthomson/provider-metronome/apis/v1alpha1"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/connector"
+	"github.com/redbackthomson/provider-metronome/internal/converters"
+)
+
+const (
+	errNotContract        = "managed resource is not a Contract custom resource"
+	errGetContract        = "failed to get contract"
+	errCreateContract     = "failed to create contract"
+	errAmendContract      = "failed to amend contract"
+	errUpdateEndDate      = "failed to update contract end date"
+	errArchiveContract    = "failed to archive contract"
+	errAdoptContract      = "failed to find contract previously created for this resource"
+	errContractGone       = "contract no longer exists"
+	errCustomerIDRequired = "customer ID is required"
+)
+
+// Setup adds a controller that reconciles Contract managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options, co connector.Options) error {
+	name := managed.ControllerName(v1alpha1.ContractGroupKind)
+
+	reconcilerOptions := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(
+			&connector.Connector[*v1alpha1.Contract, *metronomeExternal]{
+				Logger:               o.Logger,
+				Client:               mgr.GetClient(),
+				Usage:                resource.NewProviderConfigUsageTracker(mgr.GetClient(), &metronomev1alpha1.ProviderConfigUsage{}),
+				BaseURL:              co.BaseURL,
+				RateLimiters:         co.RateLimiters,
+				Metrics:              co.Metrics,
+				TracerProvider:       co.TracerProvider,
+				HTTPClients:          co.HTTPClients,
+				Clients:              co.Clients,
+				NewMetronomeClientFn: metronomeClient.New,
+				NewExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *metronomeExternal {
+					return &metronomeExternal{
+						logger:    o.Logger,
+						metronome: client.Contract(),
+						clock:     clock.RealClock{},
+					}
+				},
+			}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithMetricRecorder(o.MetricOptions.MRMetrics),
+	}
+
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		reconcilerOptions = append(reconcilerOptions, managed.WithManagementPolicies())
+	}
+
+	if err := mgr.Add(statemetrics.NewMRStateRecorder(
+		mgr.GetClient(), o.Logger, o.MetricOptions.MRStateMetrics, &v1alpha1.ContractList{}, o.MetricOptions.PollStateMetricInterval)); err != nil {
+		return err
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.ContractGroupVersionKind),
+		reconcilerOptions...,
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.Contract{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(r)
+}
+
+type metronomeExternal struct {
+	logger    logging.Logger
+	metronome metronomeClient.ContractClient
+	clock     clock.PassiveClock
+}
+
+func (e *metronomeExternal) Disconnect(ctx context.Context) error {
+	return nil
+}
+
+func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Contract)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotContract)
+	}
+
+	e.logger.Debug("Observing")
+
+	customerID := cr.Spec.ForProvider.CustomerID
+	if customerID == "" {
+		return managed.ExternalObservation{}, errors.New(errCustomerIDRequired)
+	}
+
+	id := meta.GetExternalName(cr)
+	adopted := false
+	if id == "" {
+		// a previous reconcile may have created the contract without
+		// recording its ID
+		owned, err := e.findOwned(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errAdoptContract)
+		}
+		if owned == "" {
+			return managed.ExternalObservation{}, nil
+		}
+		e.logger.Debug("Adopting contract", "id", owned)
+		meta.SetExternalName(cr, owned)
+		id, adopted = owned, true
+	}
+
+	contract, err := e.get(ctx, customerID, id)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if contract == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	converter := &converters.ContractConverterImpl{}
+	cr.Status.AtProvider = *converter.FromContract(contract)
+	cr.SetConditions(xpv1.Available())
+
+	upToDate, diff := isUpToDate(cr, contract)
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: adopted,
+		Diff:                    diff,
+	}, nil
+}
+
+// get returns the contract with the given ID, or nil if it no longer exists.
+func (e *metronomeExternal) get(ctx context.Context, customerID, id string) (*metronomeClient.Contract, error) {
+	res, err := e.metronome.GetContract(ctx, metronomeClient.GetContractRequest{
+		CustomerID: customerID,
+		ContractID: id,
+	})
+	if err != nil {
+		// the external name isn't valid, or the contract no longer exists
+		if errors.Is(err, metronomeClient.ErrContractInvalidName) || errors.Is(err, metronomeClient.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, errGetContract)
+	}
+	if res == nil || res.Data.ArchivedAt != "" {
+		return nil, nil
+	}
+	return &res.Data, nil
+}
+
+// findOwned returns the ID of the contract created with the managed
+// resource's UID as its uniqueness key, or an empty string if there is none.
+func (e *metronomeExternal) findOwned(ctx context.Context, cr *v1alpha1.Contract) (string, error) {
+	uid := string(cr.GetUID())
+	if uid == "" {
+		return "", nil
+	}
+	req := metronomeClient.ListContractsRequest{CustomerID: cr.Spec.ForProvider.CustomerID}
+	for c, err := range metronomeClient.AllContracts(ctx, e.metronome, req) {
+		if err != nil {
+			return "", err
+		}
+		if c.UniquenessKey == uid && c.ArchivedAt == "" {
+			return c.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Contract)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotContract)
+	}
+
+	e.logger.Debug("Creating")
+
+	converter := &converters.ContractConverterImpl{}
+	req := converter.FromContractSpec(&cr.Spec.ForProvider)
+
+	// Metronome rejects a second contract with the same uniqueness key, so a
+	// retried create can't create a duplicate
+	req.UniquenessKey = string(cr.GetUID())
+
+	res, err := e.metronome.CreateContract(ctx, *req)
+	if err != nil {
+		if req.UniquenessKey != "" && errors.Is(err, metronomeClient.ErrConflict) {
+			// an earlier attempt created the contract
+			owned, ferr := e.findOwned(ctx, cr)
+			if ferr == nil && owned != "" {
+				meta.SetExternalName(cr, owned)
+				return managed.ExternalCreation{}, nil
+			}
+		}
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateContract)
+	}
+	if res.Data.ID == "" {
+		return managed.ExternalCreation{}, errors.New("contract ID is missing")
+	}
+
+	meta.SetExternalName(cr, res.Data.ID)
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *metronomeExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Contract)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotContract)
+	}
+
+	e.logger.Debug("Updating")
+
+	spec := cr.Spec.ForProvider
+	id := meta.GetExternalName(cr)
+	contract, err := e.get(ctx, spec.CustomerID, id)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if contract == nil {
+		return managed.ExternalUpdate{}, errors.New(errContractGone)
+	}
+
+	commits := missingCommits(spec.Commits, contract.Commits)
+	overrides := missingOverrides(spec.Overrides, contract.Overrides)
+	if len(commits) > 0 || len(overrides) > 0 {
+		converter := &converters.ContractConverterImpl{}
+		req := metronomeClient.AmendContractRequest{
+			CustomerID: spec.CustomerID,
+			ContractID: id,
+			StartingAt: e.amendmentStart(contract),
+		}
+		for _, c := range commits {
+			req.Commits = append(req.Commits, converter.FromCommitSpec(c))
+		}
+		for _, o := range overrides {
+			req.Overrides = append(req.Overrides, converter.FromOverrideSpec(o))
+		}
+		if _, err := e.metronome.AmendContract(ctx, req); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errAmendContract)
+		}
+	}
+
+	if !metronomeClient.SameTime(spec.EndingBefore, contract.EndingBefore) {
+		if err := e.metronome.UpdateContractEndDate(ctx, metronomeClient.UpdateContractEndDateRequest{
+			CustomerID:   spec.CustomerID,
+			ContractID:   id,
+			EndingBefore: spec.EndingBefore,
+		}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateEndDate)
+		}
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// amendmentStart returns the time amendments to the contract take effect: the
+// start of the current hour, or the start of the contract if it hasn't
+// started yet.
+func (e *metronomeExternal) amendmentStart(contract *metronomeClient.Contract) string {
+	at := e.clock.Now().UTC().Truncate(time.Hour)
+	if start, err := time.Parse(time.RFC3339, contract.StartingAt); err == nil && start.After(at) {
+		at = start.UTC()
+	}
+	return at.Format(time.RFC3339)
+}
+
+func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.Contract)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotContract)
+	}
+
+	e.logger.Debug("Deleting")
+
+	id := meta.GetExternalName(cr)
+	if id == "" {
+		return managed.ExternalDelete{}, nil
+	}
+
+	if err := e.metronome.ArchiveContract(ctx, metronomeClient.ArchiveContractRequest{
+		CustomerID: cr.Spec.ForProvider.CustomerID,
+		ContractID: id,
+	}); err != nil {
+		// the contract has already been archived or no longer exists
+		if errors.Is(err, metronomeClient.ErrConflict) || errors.Is(err, metronomeClient.ErrNotFound) {
+			return managed.ExternalDelete{}, nil
+		}
+		return managed.ExternalDelete{}, errors.Wrap(err, errArchiveContract)
+	}
+
+	return managed.ExternalDelete{}, nil
+}
+
+// missingCommits returns the desired commits that the contract doesn't have.
+// Commits are matched by type, product and name.
+func missingCommits(desired []v1alpha1.Commit, observed []metronomeClient.Commit) []v1alpha1.Commit {
+	var missing []v1alpha1.Commit
+	for _, d := range desired {
+		found := slices.ContainsFunc(observed, func(o metronomeClient.Commit) bool {
+			return strings.EqualFold(o.Type, d.Type) && o.Product.ID == d.ProductID && o.Name == d.Name
+		})
+		if !found {
+			missing = append(missing, d)
+		}
+	}
+	return missing
+}
+
+// missingOverrides returns the desired overrides that the contract doesn't
+// have. Overrides are matched by product, type and start time.
+func missingOverrides(desired []v1alpha1.Override, observed []metronomeClient.Override) []v1alpha1.Override {
+	var missing []v1alpha1.Override
+	for _, d := range desired {
+		found := slices.ContainsFunc(observed, func(o metronomeClient.Override) bool {
+			return strings.EqualFold(o.Type, d.Type) && o.Product.ID == d.ProductID && metronomeClient.SameTime(o.StartingAt, d.StartingAt)
+		})
+		if !found {
+			missing = append(missing, d)
+		}
+	}
+	return missing
+}
+
+func isUpToDate(cr *v1alpha1.Contract, contract *metronomeClient.Contract) (bool, string) {
+	spec := cr.Spec.ForProvider
+
+	var diff []string
+	if !metronomeClient.SameTime(spec.EndingBefore, contract.EndingBefore) {
+		diff = append(diff, fmt.Sprintf("endingBefore: want %q, got %q", spec.EndingBefore, contract.EndingBefore))
+	}
+	for _, c := range missingCommits(spec.Commits, contract.Commits) {
+		diff = append(diff, fmt.Sprintf("missing %s commit %q for product %s", c.Type, c.Name, c.ProductID))
+	}
+	for _, o := range missingOverrides(spec.Overrides, contract.Overrides) {
+		diff = append(diff, fmt.Sprintf("missing %s override for product %s starting at %s", o.Type, o.ProductID, o.StartingAt))
+	}
+
+	return len(diff) == 0, strings.Join(diff, "\n")
+}
@@ -0,0 +1,769 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/redbackthomson/provider-metronome/apis/contract/v1alpha1"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/clients/metronome/metronometest"
+)
+
+const (
+	providerConfigName = "metronome-test"
+	testResourceName   = "test-resource"
+	testNamespace      = "testns"
+
+	testCustomerID = "0b5a5f39-2c2b-4a6e-9f77-1f3c7d6a8e01"
+	testContractID = "3e2f1a4b-5c6d-4e7f-8a9b-0c1d2e3f4a5b"
+	testProductID  = "c76c1a94-9aaa-447b-bfcf-615fbf10ec52"
+)
+
+var (
+	errBoom = errors.New("boom")
+)
+
+type contractModifier func(mg *v1alpha1.Contract)
+
+func contract(cm ...contractModifier) *v1alpha1.Contract {
+	c := &v1alpha1.Contract{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testResourceName,
+			Namespace: testNamespace,
+		},
+		Spec: v1alpha1.ContractSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{
+					Name: providerConfigName,
+				},
+			},
+			ForProvider: v1alpha1.ContractParameters{
+				CustomerID: testCustomerID,
+				StartingAt: "2025-01-01T00:00:00Z",
+			},
+		},
+		Status: v1alpha1.ContractStatus{},
+	}
+
+	meta.SetExternalName(c, testContractID)
+
+	for _, m := range cm {
+		m(c)
+	}
+
+	return c
+}
+
+type notContractResource struct {
+	resource.Managed
+}
+
+type MockContractClient struct {
+	CreateContractFn        func(ctx context.Context, reqData metronomeClient.CreateContractRequest) (*metronomeClient.CreateContractResponse, error)
+	GetContractFn           func(ctx context.Context, reqData metronomeClient.GetContractRequest) (*metronomeClient.GetContractResponse, error)
+	ListContractsFn         func(ctx context.Context, reqData metronomeClient.ListContractsRequest, nextPage string) (*metronomeClient.ListContractsResponse, error)
+	AmendContractFn         func(ctx context.Context, reqData metronomeClient.AmendContractRequest) (*metronomeClient.AmendContractResponse, error)
+	UpdateContractEndDateFn func(ctx context.Context, reqData metronomeClient.UpdateContractEndDateRequest) error
+	ArchiveContractFn       func(ctx context.Context, reqData metronomeClient.ArchiveContractRequest) error
+}
+
+// CreateContract implements metronome.ContractClient.
+func (m *MockContractClient) CreateContract(ctx context.Context, reqData metronomeClient.CreateContractRequest) (*metronomeClient.CreateContractResponse, error) {
+	return m.CreateContractFn(ctx, reqData)
+}
+
+// GetContract implements metronome.ContractClient.
+func (m *MockContractClient) GetContract(ctx context.Context, reqData metronomeClient.GetContractRequest) (*metronomeClient.GetContractResponse, error) {
+	return m.GetContractFn(ctx, reqData)
+}
+
+// ListContracts implements metronome.ContractClient.
+func (m *MockContractClient) ListContracts(ctx context.Context, reqData metronomeClient.ListContractsRequest, nextPage string) (*metronomeClient.ListContractsResponse, error) {
+	return m.ListContractsFn(ctx, reqData, nextPage)
+}
+
+// AmendContract implements metronome.ContractClient.
+func (m *MockContractClient) AmendContract(ctx context.Context, reqData metronomeClient.AmendContractRequest) (*metronomeClient.AmendContractResponse, error) {
+	return m.AmendContractFn(ctx, reqData)
+}
+
+// UpdateContractEndDate implements metronome.ContractClient.
+func (m *MockContractClient) UpdateContractEndDate(ctx context.Context, reqData metronomeClient.UpdateContractEndDateRequest) error {
+	return m.UpdateContractEndDateFn(ctx, reqData)
+}
+
+// ArchiveContract implements metronome.ContractClient.
+func (m *MockContractClient) ArchiveContract(ctx context.Context, reqData metronomeClient.ArchiveContractRequest) error {
+	return m.ArchiveContractFn(ctx, reqData)
+}
+
+var _ (metronomeClient.ContractClient) = (*MockContractClient)(nil)
+
+func getContract(c metronomeClient.Contract) func(ctx context.Context, reqData metronomeClient.GetContractRequest) (*metronomeClient.GetContractResponse, error) {
+	return func(ctx context.Context, reqData metronomeClient.GetContractRequest) (*metronomeClient.GetContractResponse, error) {
+		c.ID = reqData.ContractID
+		c.CustomerID = reqData.CustomerID
+		return &metronomeClient.GetContractResponse{Data: c}, nil
+	}
+}
+
+func Test_External_Observe(t *testing.T) {
+	commit := v1alpha1.Commit{Type: "PREPAID", Name: "Annual", ProductID: testProductID}
+	override := v1alpha1.Override{ProductID: testProductID, StartingAt: "2025-01-01T00:00:00Z", Type: "MULTIPLIER"}
+
+	type args struct {
+		metronome metronomeClient.ContractClient
+		mg        resource.Managed
+	}
+	type want struct {
+		out          managed.ExternalObservation
+		externalName string
+		err          error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotContractResource": {
+			args: args{
+				mg: notContractResource{},
+			},
+			want: want{
+				err: errors.New(errNotContract),
+			},
+		},
+		"NoCustomerID": {
+			args: args{
+				mg: contract(func(mg *v1alpha1.Contract) {
+					mg.Spec.ForProvider.CustomerID = ""
+				}),
+			},
+			want: want{
+				externalName: testContractID,
+				err:          errors.New(errCustomerIDRequired),
+			},
+		},
+		"NoExternalName": {
+			args: args{
+				mg: contract(func(mg *v1alpha1.Contract) {
+					meta.SetExternalName(mg, "")
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToFindOwnedContract": {
+			args: args{
+				metronome: &MockContractClient{
+					ListContractsFn: func(ctx context.Context, reqData metronomeClient.ListContractsRequest, nextPage string) (*metronomeClient.ListContractsResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errAdoptContract),
+			},
+		},
+		"AdoptsOwnedContract": {
+			args: args{
+				metronome: &MockContractClient{
+					ListContractsFn: func(ctx context.Context, reqData metronomeClient.ListContractsRequest, nextPage string) (*metronomeClient.ListContractsResponse, error) {
+						return &metronomeClient.ListContractsResponse{Data: []metronomeClient.Contract{
+							{ID: "archived", UniquenessKey: "uid", ArchivedAt: "2025-01-01T00:00:00Z"},
+							{ID: "other", UniquenessKey: "other-uid"},
+							{ID: testContractID, UniquenessKey: "uid"},
+						}}, nil
+					},
+					GetContractFn: getContract(metronomeClient.Contract{StartingAt: "2025-01-01T00:00:00Z"}),
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+				}),
+			},
+			want: want{
+				out:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				externalName: testContractID,
+			},
+		},
+		"AdoptsOwnedContractOnLaterPage": {
+			args: args{
+				metronome: &MockContractClient{
+					ListContractsFn: func(ctx context.Context, reqData metronomeClient.ListContractsRequest, nextPage string) (*metronomeClient.ListContractsResponse, error) {
+						if nextPage == "" {
+							return &metronomeClient.ListContractsResponse{
+								Data:     []metronomeClient.Contract{{ID: "other", UniquenessKey: "other-uid"}},
+								NextPage: ptr.To("page-2"),
+							}, nil
+						}
+						return &metronomeClient.ListContractsResponse{Data: []metronomeClient.Contract{
+							{ID: testContractID, UniquenessKey: "uid"},
+						}}, nil
+					},
+					GetContractFn: getContract(metronomeClient.Contract{StartingAt: "2025-01-01T00:00:00Z"}),
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+				}),
+			},
+			want: want{
+				out:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				externalName: testContractID,
+			},
+		},
+		"FailedToGetContract": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: func(ctx context.Context, reqData metronomeClient.GetContractRequest) (*metronomeClient.GetContractResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: contract(),
+			},
+			want: want{
+				externalName: testContractID,
+				err:          errors.Wrap(errBoom, errGetContract),
+			},
+		},
+		"NotFoundIsDeleted": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: func(ctx context.Context, reqData metronomeClient.GetContractRequest) (*metronomeClient.GetContractResponse, error) {
+						return nil, &metronomeClient.APIError{StatusCode: 404}
+					},
+				},
+				mg: contract(),
+			},
+			want: want{
+				out:          managed.ExternalObservation{ResourceExists: false},
+				externalName: testContractID,
+			},
+		},
+		"ArchivedIsDeleted": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: getContract(metronomeClient.Contract{ArchivedAt: "2025-01-01T00:00:00Z"}),
+				},
+				mg: contract(),
+			},
+			want: want{
+				out:          managed.ExternalObservation{ResourceExists: false},
+				externalName: testContractID,
+			},
+		},
+		"MissingCommitIsNotUpToDate": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: getContract(metronomeClient.Contract{
+						Commits: []metronomeClient.Commit{{Type: "PREPAID", Name: "Other", Product: metronomeClient.ProductIdentifier{ID: testProductID}}},
+					}),
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					mg.Spec.ForProvider.Commits = []v1alpha1.Commit{commit}
+				}),
+			},
+			want: want{
+				out:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				externalName: testContractID,
+			},
+		},
+		"EndDateChangedIsNotUpToDate": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: getContract(metronomeClient.Contract{}),
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					mg.Spec.ForProvider.EndingBefore = "2026-01-01T00:00:00Z"
+				}),
+			},
+			want: want{
+				out:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				externalName: testContractID,
+			},
+		},
+		"UpToDate": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: getContract(metronomeClient.Contract{
+						EndingBefore: "2026-01-01T00:00:00.000Z",
+						Commits: []metronomeClient.Commit{
+							{Type: "PREPAID", Name: "Annual", Product: metronomeClient.ProductIdentifier{ID: testProductID}},
+							{Type: "POSTPAID", Name: "Unmanaged", Product: metronomeClient.ProductIdentifier{ID: testProductID}},
+						},
+						Overrides: []metronomeClient.Override{
+							{Type: "MULTIPLIER", StartingAt: "2025-01-01T00:00:00.000Z", Product: metronomeClient.ProductIdentifier{ID: testProductID}},
+						},
+					}),
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					mg.Spec.ForProvider.EndingBefore = "2026-01-01T00:00:00Z"
+					mg.Spec.ForProvider.Commits = []v1alpha1.Commit{commit}
+					mg.Spec.ForProvider.Overrides = []v1alpha1.Override{override}
+				}),
+			},
+			want: want{
+				out:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				externalName: testContractID,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:    logging.NewNopLogger(),
+				metronome: tc.args.metronome,
+			}
+
+			ignoreDiff := cmpopts.IgnoreFields(managed.ExternalObservation{}, "Diff")
+
+			got, gotErr := e.Observe(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Observe(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.out, got, ignoreDiff); diff != "" {
+				t.Fatalf("e.Observe(...): -want out, +got out: %s", diff)
+			}
+			if cr, ok := tc.args.mg.(*v1alpha1.Contract); ok {
+				if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(cr)); diff != "" {
+					t.Fatalf("e.Observe(...): -want external name, +got external name: %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_External_Create(t *testing.T) {
+	type args struct {
+		metronome metronomeClient.ContractClient
+		mg        resource.Managed
+	}
+	type want struct {
+		out          managed.ExternalCreation
+		externalName string
+		err          error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotContractResource": {
+			args: args{
+				mg: notContractResource{},
+			},
+			want: want{
+				err: errors.New(errNotContract),
+			},
+		},
+		"FailedToCreateContract": {
+			args: args{
+				metronome: &MockContractClient{
+					CreateContractFn: func(ctx context.Context, reqData metronomeClient.CreateContractRequest) (*metronomeClient.CreateContractResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					meta.SetExternalName(mg, "")
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateContract),
+			},
+		},
+		"AlreadyCreated": {
+			args: args{
+				metronome: &MockContractClient{
+					CreateContractFn: func(ctx context.Context, reqData metronomeClient.CreateContractRequest) (*metronomeClient.CreateContractResponse, error) {
+						return nil, errors.Wrap(metronomeClient.ErrConflict, "failed to create contract")
+					},
+					ListContractsFn: func(ctx context.Context, reqData metronomeClient.ListContractsRequest, nextPage string) (*metronomeClient.ListContractsResponse, error) {
+						return &metronomeClient.ListContractsResponse{Data: []metronomeClient.Contract{{ID: "id1", UniquenessKey: "uid"}}}, nil
+					},
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+				}),
+			},
+			want: want{
+				externalName: "id1",
+			},
+		},
+		"Success": {
+			args: args{
+				metronome: &MockContractClient{
+					CreateContractFn: func(ctx context.Context, reqData metronomeClient.CreateContractRequest) (*metronomeClient.CreateContractResponse, error) {
+						if reqData.UniquenessKey != "uid" || reqData.CustomerID != testCustomerID {
+							return nil, errBoom
+						}
+						return &metronomeClient.CreateContractResponse{Data: metronomeClient.IDOnly{ID: "id1"}}, nil
+					},
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+				}),
+			},
+			want: want{
+				externalName: "id1",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:    logging.NewNopLogger(),
+				metronome: tc.args.metronome,
+			}
+			got, gotErr := e.Create(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Create(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Fatalf("e.Create(...): -want out, +got out: %s", diff)
+			}
+			if cr, ok := tc.args.mg.(*v1alpha1.Contract); ok {
+				if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(cr)); diff != "" {
+					t.Fatalf("e.Create(...): -want external name, +got external name: %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_External_Update(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 34, 56, 0, time.UTC)
+	commit := v1alpha1.Commit{Type: "PREPAID", Name: "Annual", ProductID: testProductID}
+
+	type args struct {
+		metronome metronomeClient.ContractClient
+		mg        resource.Managed
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotContractResource": {
+			args: args{
+				mg: notContractResource{},
+			},
+			want: want{
+				err: errors.New(errNotContract),
+			},
+		},
+		"ContractGone": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: getContract(metronomeClient.Contract{ArchivedAt: "2025-01-01T00:00:00Z"}),
+				},
+				mg: contract(),
+			},
+			want: want{
+				err: errors.New(errContractGone),
+			},
+		},
+		"FailedToAmendContract": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: getContract(metronomeClient.Contract{StartingAt: "2025-01-01T00:00:00Z"}),
+					AmendContractFn: func(ctx context.Context, reqData metronomeClient.AmendContractRequest) (*metronomeClient.AmendContractResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					mg.Spec.ForProvider.Commits = []v1alpha1.Commit{commit}
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errAmendContract),
+			},
+		},
+		"AmendsFromCurrentHour": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: getContract(metronomeClient.Contract{StartingAt: "2025-01-01T00:00:00Z"}),
+					AmendContractFn: func(ctx context.Context, reqData metronomeClient.AmendContractRequest) (*metronomeClient.AmendContractResponse, error) {
+						want := metronomeClient.AmendContractRequest{
+							CustomerID: testCustomerID,
+							ContractID: testContractID,
+							StartingAt: "2025-03-01T12:00:00Z",
+							Commits:    []metronomeClient.CommitRequest{{Type: "PREPAID", Name: "Annual", ProductID: testProductID}},
+						}
+						if diff := cmp.Diff(want, reqData); diff != "" {
+							return nil, errors.Errorf("-want, +got: %s", diff)
+						}
+						return &metronomeClient.AmendContractResponse{}, nil
+					},
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					mg.Spec.ForProvider.Commits = []v1alpha1.Commit{commit}
+				}),
+			},
+		},
+		"AmendsFromStartOfFutureContract": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: getContract(metronomeClient.Contract{StartingAt: "2025-06-01T00:00:00Z"}),
+					AmendContractFn: func(ctx context.Context, reqData metronomeClient.AmendContractRequest) (*metronomeClient.AmendContractResponse, error) {
+						if reqData.StartingAt != "2025-06-01T00:00:00Z" {
+							return nil, errors.Errorf("unexpected starting at %s", reqData.StartingAt)
+						}
+						return &metronomeClient.AmendContractResponse{}, nil
+					},
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					mg.Spec.ForProvider.Commits = []v1alpha1.Commit{commit}
+				}),
+			},
+		},
+		"FailedToUpdateEndDate": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: getContract(metronomeClient.Contract{}),
+					UpdateContractEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateContractEndDateRequest) error {
+						return errBoom
+					},
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					mg.Spec.ForProvider.EndingBefore = "2026-01-01T00:00:00Z"
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateEndDate),
+			},
+		},
+		"UpdatesEndDate": {
+			args: args{
+				metronome: &MockContractClient{
+					GetContractFn: getContract(metronomeClient.Contract{EndingBefore: "2027-01-01T00:00:00Z"}),
+					UpdateContractEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateContractEndDateRequest) error {
+						if reqData.EndingBefore != "2026-01-01T00:00:00Z" {
+							return errors.Errorf("unexpected ending before %s", reqData.EndingBefore)
+						}
+						return nil
+					},
+				},
+				mg: contract(func(mg *v1alpha1.Contract) {
+					mg.Spec.ForProvider.EndingBefore = "2026-01-01T00:00:00Z"
+				}),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:    logging.NewNopLogger(),
+				metronome: tc.args.metronome,
+				clock:     clocktesting.NewFakePassiveClock(now),
+			}
+			_, gotErr := e.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Update(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_External_Delete(t *testing.T) {
+	type args struct {
+		metronome metronomeClient.ContractClient
+		mg        resource.Managed
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotContractResource": {
+			args: args{
+				mg: notContractResource{},
+			},
+			want: want{
+				err: errors.New(errNotContract),
+			},
+		},
+		"FailedToArchiveContract": {
+			args: args{
+				metronome: &MockContractClient{
+					ArchiveContractFn: func(ctx context.Context, reqData metronomeClient.ArchiveContractRequest) error {
+						return errBoom
+					},
+				},
+				mg: contract(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errArchiveContract),
+			},
+		},
+		"AlreadyArchived": {
+			args: args{
+				metronome: &MockContractClient{
+					ArchiveContractFn: func(ctx context.Context, reqData metronomeClient.ArchiveContractRequest) error {
+						return errors.Wrap(metronomeClient.ErrConflict, "failed to archive contract")
+					},
+				},
+				mg: contract(),
+			},
+		},
+		"Success": {
+			args: args{
+				metronome: &MockContractClient{
+					ArchiveContractFn: func(ctx context.Context, reqData metronomeClient.ArchiveContractRequest) error {
+						if reqData.ContractID != testContractID || reqData.CustomerID != testCustomerID {
+							return errBoom
+						}
+						return nil
+					},
+				},
+				mg: contract(),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:    logging.NewNopLogger(),
+				metronome: tc.args.metronome,
+			}
+			_, gotErr := e.Delete(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Delete(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_MetronomeExternal_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 34, 56, 0, time.UTC)
+	clk := clocktesting.NewFakePassiveClock(now)
+
+	srv := metronometest.NewServer(metronometest.WithClock(clk))
+	defer srv.Close()
+
+	client := srv.NewClient()
+	e := &metronomeExternal{
+		logger:    logging.NewNopLogger(),
+		metronome: client.Contract(),
+		clock:     clk,
+	}
+
+	customer, err := client.Customer().CreateCustomer(ctx, metronomeClient.CreateCustomerRequest{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("CreateCustomer(...): %v", err)
+	}
+	card, err := client.RateCard().CreateRateCard(ctx, metronomeClient.CreateRateCardRequest{Name: "Standard"})
+	if err != nil {
+		t.Fatalf("CreateRateCard(...): %v", err)
+	}
+	product, err := client.Product().CreateProduct(ctx, metronomeClient.CreateProductRequest{Name: "Seats", Type: "SUBSCRIPTION"})
+	if err != nil {
+		t.Fatalf("CreateProduct(...): %v", err)
+	}
+
+	cr := contract(func(c *v1alpha1.Contract) {
+		meta.SetExternalName(c, "")
+		c.SetUID("6f1c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f")
+		c.Spec.ForProvider = v1alpha1.ContractParameters{
+			CustomerID: customer.Data.ID,
+			RateCardID: card.Data.ID,
+			Name:       "Annual",
+			StartingAt: "2025-01-01T00:00:00Z",
+			Commits: []v1alpha1.Commit{{
+				Type:      "PREPAID",
+				Name:      "Annual commit",
+				ProductID: product.Data.ID,
+				AccessSchedule: &v1alpha1.AccessSchedule{ScheduleItems: []v1alpha1.ScheduleItem{{
+					Amount:       "100000",
+					StartingAt:   "2025-01-01T00:00:00Z",
+					EndingBefore: "2026-01-01T00:00:00Z",
+				}}},
+			}},
+		}
+	})
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and up to date, got %+v, %v", o, err)
+	}
+	if cr.Status.AtProvider.ID != meta.GetExternalName(cr) {
+		t.Errorf("Observe(...): want status ID %q, got %q", meta.GetExternalName(cr), cr.Status.AtProvider.ID)
+	}
+
+	// creating the contract again must not create a duplicate
+	id := meta.GetExternalName(cr)
+	meta.SetExternalName(cr, "")
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): want existing contract to be adopted, got %v", err)
+	}
+	if diff := cmp.Diff(id, meta.GetExternalName(cr)); diff != "" {
+		t.Fatalf("Create(...): -want external name, +got external name: %s", diff)
+	}
+
+	multiplier := 0.9
+	cr.Spec.ForProvider.EndingBefore = "2027-01-01T00:00:00Z"
+	cr.Spec.ForProvider.Commits = append(cr.Spec.ForProvider.Commits, v1alpha1.Commit{
+		Type:      "POSTPAID",
+		Name:      "True-up",
+		ProductID: product.Data.ID,
+	})
+	cr.Spec.ForProvider.Overrides = []v1alpha1.Override{{
+		ProductID:  product.Data.ID,
+		StartingAt: "2025-04-01T00:00:00Z",
+		Type:       "MULTIPLIER",
+		Multiplier: &multiplier,
+	}}
+	if o, _ := e.Observe(ctx, cr); o.ResourceUpToDate {
+		t.Fatal("Observe(...): want out of date after changing the spec")
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if o, _ := e.Observe(ctx, cr); !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date after update, got diff %s", o.Diff)
+	}
+	if diff := cmp.Diff(2, len(cr.Status.AtProvider.Commits)); diff != "" {
+		t.Errorf("Observe(...): -want commits, +got commits: %s", diff)
+	}
+	if diff := cmp.Diff(1, len(cr.Status.AtProvider.Overrides)); diff != "" {
+		t.Errorf("Observe(...): -want overrides, +got overrides: %s", diff)
+	}
+
+	if _, err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete(...): %v", err)
+	}
+	if o, _ := e.Observe(ctx, cr); o.ResourceExists {
+		t.Fatal("Observe(...): want not existing after delete")
+	}
+	if _, err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete(...): want archived contract to be treated as deleted, got %v", err)
+	}
+}
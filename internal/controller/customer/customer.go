@@ -0,0 +1,396 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customer
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
+
+	"github.com/redbackthomson/provider-metronome/apis/customer/v1alpha1"
+	metronomev1alpha1 "github.com/redbackthomson/provider-metronome/apis/v1alpha1"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/connector"
+	"github.com/redbackthomson/provider-metronome/internal/converters"
+)
+
+const (
+	errNotCustomer            = "managed resource is not a Customer custom resource"
+	errGetCustomer            = "failed to get customer"
+	errGetBillingProviders    = "failed to get customer billing provider configurations"
+	errCreateCustomer         = "failed to create customer"
+	errUpdateCustomerName     = "failed to update customer name"
+	errUpdateCustomerAliases  = "failed to update customer ingest aliases"
+	errUpdateCustomFields     = "failed to update customer custom fields"
+	errUpdateBillingProviders = "failed to update customer billing provider configurations"
+	errArchiveCustomer        = "failed to archive customer"
+	errAdoptCustomer          = "failed to find customer previously created for this resource"
+	errEnsureOwnerKey         = "failed to create owner custom field key"
+	errCustomerGone           = "customer no longer exists"
+)
+
+// Setup adds a controller that reconciles Customer managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options, co connector.Options) error {
+	name := managed.ControllerName(v1alpha1.CustomerGroupKind)
+
+	reconcilerOptions := []managed.ReconcilerOption{
+		managed.WithExternalConnecter(
+			&connector.Connector[*v1alpha1.Customer, *metronomeExternal]{
+				Logger:               o.Logger,
+				Client:               mgr.GetClient(),
+				Usage:                resource.NewProviderConfigUsageTracker(mgr.GetClient(), &metronomev1alpha1.ProviderConfigUsage{}),
+				BaseURL:              co.BaseURL,
+				RateLimiters:         co.RateLimiters,
+				Metrics:              co.Metrics,
+				TracerProvider:       co.TracerProvider,
+				HTTPClients:          co.HTTPClients,
+				Clients:              co.Clients,
+				NewMetronomeClientFn: metronomeClient.New,
+				NewExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *metronomeExternal {
+					return &metronomeExternal{
+						logger:       o.Logger,
+						metronome:    client.Customer(),
+						customFields: client.CustomField(),
+						keys:         client.CustomFieldKey(),
+						adopt:        co.AdoptCreatedObjects,
+					}
+				},
+			}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithMetricRecorder(o.MetricOptions.MRMetrics),
+	}
+
+	if o.Features.Enabled(feature.EnableBetaManagementPolicies) {
+		reconcilerOptions = append(reconcilerOptions, managed.WithManagementPolicies())
+	}
+
+	if err := mgr.Add(statemetrics.NewMRStateRecorder(
+		mgr.GetClient(), o.Logger, o.MetricOptions.MRStateMetrics, &v1alpha1.CustomerList{}, o.MetricOptions.PollStateMetricInterval)); err != nil {
+		return err
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.CustomerGroupVersionKind),
+		reconcilerOptions...,
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.Customer{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(r)
+}
+
+type metronomeExternal struct {
+	logger       logging.Logger
+	metronome    metronomeClient.CustomerClient
+	customFields metronomeClient.CustomFieldClient
+	keys         metronomeClient.CustomFieldKeyClient
+
+	// adopt marks the objects created in Metronome with the UID of their
+	// managed resource, and adopts them if their ID was never recorded.
+	adopt bool
+}
+
+func (e *metronomeExternal) Disconnect(ctx context.Context) error {
+	return nil
+}
+
+func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Customer)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotCustomer)
+	}
+
+	e.logger.Debug("Observing")
+
+	id := meta.GetExternalName(cr)
+	adopted := false
+	if id == "" {
+		// a previous reconcile may have created the customer without
+		// recording its ID
+		owned, err := e.findOwned(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errAdoptCustomer)
+		}
+		if owned == "" {
+			return managed.ExternalObservation{}, nil
+		}
+		e.logger.Debug("Adopting customer", "id", owned)
+		meta.SetExternalName(cr, owned)
+		id, adopted = owned, true
+	}
+
+	customer, billing, err := e.get(ctx, id)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if customer == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	converter := &converters.CustomerConverterImpl{}
+	cr.Status.AtProvider = *converter.FromCustomer(customer)
+	for _, bp := range billing {
+		cr.Status.AtProvider.BillingProviderConfigurations = append(cr.Status.AtProvider.BillingProviderConfigurations, converter.FromBillingProviderConfiguration(bp))
+	}
+	cr.SetConditions(xpv1.Available())
+
+	upToDate, diff := isUpToDate(cr, &cr.Status.AtProvider)
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: adopted,
+		Diff:                    diff,
+	}, nil
+}
+
+// get returns the customer with the given ID and its active billing provider
+// configurations, or a nil customer if it no longer exists.
+func (e *metronomeExternal) get(ctx context.Context, id string) (*metronomeClient.GetCustomerData, []metronomeClient.CustomerBillingProviderConfiguration, error) {
+	res, err := e.metronome.GetCustomer(ctx, id)
+	if err != nil {
+		// the customer no longer exists
+		if errors.Is(err, metronomeClient.ErrNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, errors.Wrap(err, errGetCustomer)
+	}
+	if res == nil || res.Data.ArchivedAt != "" {
+		return nil, nil, nil
+	}
+
+	bps, err := e.metronome.GetBillingProviderConfigurations(ctx, id)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errGetBillingProviders)
+	}
+	var billing []metronomeClient.CustomerBillingProviderConfiguration
+	if bps != nil {
+		for _, bp := range bps.Data {
+			if bp.ArchivedAt == "" {
+				billing = append(billing, bp)
+			}
+		}
+	}
+
+	return &res.Data, billing, nil
+}
+
+// findOwned returns the ID of the customer marked as created by the managed
+// resource, or an empty string if there is none.
+func (e *metronomeExternal) findOwned(ctx context.Context, cr *v1alpha1.Customer) (string, error) {
+	// only a create that was attempted can have lost the ID of the object
+	// it created
+	uid := string(cr.GetUID())
+	if !e.adopt || uid == "" || meta.GetExternalCreatePending(cr).IsZero() {
+		return "", nil
+	}
+	for c, err := range metronomeClient.AllCustomers(ctx, e.metronome, metronomeClient.ListCustomersRequest{}) {
+		if err != nil {
+			return "", err
+		}
+		if c.ArchivedAt != "" {
+			continue
+		}
+		if metronomeClient.IsOwnedBy(c.CustomFields, uid) && c.Name == cr.Spec.ForProvider.Name {
+			return c.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Customer)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotCustomer)
+	}
+
+	e.logger.Debug("Creating")
+
+	converter := &converters.CustomerConverterImpl{}
+	req := converter.FromCustomerSpec(&cr.Spec.ForProvider)
+
+	// mark the customer so it can be adopted if its ID is lost
+	if uid := string(cr.GetUID()); e.adopt && uid != "" {
+		if err := metronomeClient.EnsureOwnerCustomFieldKey(ctx, e.keys, metronomeClient.EntityCustomer); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errEnsureOwnerKey)
+		}
+		req.CustomFields = metronomeClient.WithOwner(req.CustomFields, uid)
+	}
+
+	res, err := e.metronome.CreateCustomer(ctx, *req)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateCustomer)
+	}
+	if res.Data.ID == "" {
+		return managed.ExternalCreation{}, errors.New("customer ID is missing")
+	}
+
+	meta.SetExternalName(cr, res.Data.ID)
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *metronomeExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Customer)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotCustomer)
+	}
+
+	e.logger.Debug("Updating")
+
+	id := meta.GetExternalName(cr)
+	customer, billing, err := e.get(ctx, id)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if customer == nil {
+		return managed.ExternalUpdate{}, errors.New(errCustomerGone)
+	}
+
+	spec := cr.Spec.ForProvider
+
+	if spec.Name != customer.Name {
+		if err := e.metronome.UpdateCustomerName(ctx, id, metronomeClient.UpdateNameRequest{Name: spec.Name}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateCustomerName)
+		}
+	}
+
+	if !sameStrings(spec.IngestAliases, customer.IngestAliases) {
+		if err := e.metronome.UpdateCustomerAliases(ctx, id, metronomeClient.UpdateAliasesRequest{IngestAliases: spec.IngestAliases}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateCustomerAliases)
+		}
+	}
+
+	if err := metronomeClient.SyncCustomFieldValues(ctx, e.customFields, metronomeClient.EntityCustomer, id, spec.CustomFields, customer.CustomFields); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateCustomFields)
+	}
+
+	if missing := missingBillingProviders(id, spec.BillingProviderConfigurations, billing); len(missing) > 0 {
+		if err := e.metronome.SetBillingProviderConfigurations(ctx, metronomeClient.SetBillingProviderConfigurationsRequest{Data: missing}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateBillingProviders)
+		}
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.Customer)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotCustomer)
+	}
+
+	e.logger.Debug("Deleting")
+
+	id := meta.GetExternalName(cr)
+	if id == "" {
+		return managed.ExternalDelete{}, nil
+	}
+
+	if err := e.metronome.ArchiveCustomer(ctx, id); err != nil {
+		// the customer has already been archived or no longer exists
+		if errors.Is(err, metronomeClient.ErrConflict) || errors.Is(err, metronomeClient.ErrNotFound) {
+			return managed.ExternalDelete{}, nil
+		}
+		return managed.ExternalDelete{}, errors.Wrap(err, errArchiveCustomer)
+	}
+
+	return managed.ExternalDelete{}, nil
+}
+
+// missingBillingProviders returns the desired billing provider configurations
+// that the customer doesn't have. Configurations the customer has that aren't
+// desired are left in place.
+func missingBillingProviders(id string, desired []v1alpha1.BillingProviderConfiguration, observed []metronomeClient.CustomerBillingProviderConfiguration) []metronomeClient.CustomerBillingProviderConfiguration {
+	var missing []metronomeClient.CustomerBillingProviderConfiguration
+	for _, d := range desired {
+		found := slices.ContainsFunc(observed, func(o metronomeClient.CustomerBillingProviderConfiguration) bool {
+			return o.BillingProvider == d.BillingProvider &&
+				o.DeliveryMethod == d.DeliveryMethod &&
+				o.Configuration.StripeCustomerID == d.Configuration.StripeCustomerID &&
+				o.Configuration.StripeCollectionMethod == d.Configuration.StripeCollectionMethod
+		})
+		if found {
+			continue
+		}
+		missing = append(missing, metronomeClient.CustomerBillingProviderConfiguration{
+			CustomerID:      id,
+			BillingProvider: d.BillingProvider,
+			DeliveryMethod:  d.DeliveryMethod,
+			Configuration: metronomeClient.BillingConfiguration{
+				StripeCustomerID:       d.Configuration.StripeCustomerID,
+				StripeCollectionMethod: d.Configuration.StripeCollectionMethod,
+			},
+		})
+	}
+	return missing
+}
+
+// sameStrings reports whether a and b contain the same strings, in any order.
+func sameStrings(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
+
+func isUpToDate(cr *v1alpha1.Customer, observed *v1alpha1.ObservedCustomer) (bool, string) {
+	spec := cr.Spec.ForProvider.DeepCopy()
+
+	params := &v1alpha1.CustomerParameters{
+		Name:          observed.Name,
+		IngestAliases: slices.Clone(observed.IngestAliases),
+		CustomFields:  metronomeClient.WithoutOwner(maps.Clone(observed.CustomFields)),
+	}
+
+	// only the desired billing provider configurations are managed, so ignore
+	// any others the customer has
+	for _, d := range spec.BillingProviderConfigurations {
+		if slices.Contains(observed.BillingProviderConfigurations, d) {
+			params.BillingProviderConfigurations = append(params.BillingProviderConfigurations, d)
+		}
+	}
+
+	slices.Sort(spec.IngestAliases)
+	slices.Sort(params.IngestAliases)
+
+	opts := []cmp.Option{
+		cmpopts.EquateEmpty(),
+		cmpopts.SortSlices(func(a, b v1alpha1.BillingProviderConfiguration) bool {
+			return strings.Compare(a.BillingProvider+"/"+a.DeliveryMethod, b.BillingProvider+"/"+b.DeliveryMethod) < 0
+		}),
+	}
+
+	return cmp.Equal(spec, params, opts...), cmp.Diff(spec, params, opts...)
+}
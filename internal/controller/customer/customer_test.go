@@ -0,0 +1,520 @@
+package customer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/redbackthomson/provider-metronome/apis/customer/v1alpha1"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/clients/metronome/metronometest"
+)
+
+const (
+	providerConfigName = "metronome-test"
+	testResourceName   = "test-resource"
+	testNamespace      = "testns"
+)
+
+var (
+	errBoom = errors.New("boom")
+)
+
+type customerModifier func(mg *v1alpha1.Customer)
+
+func customer(cm ...customerModifier) *v1alpha1.Customer {
+	c := &v1alpha1.Customer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testResourceName,
+			Namespace: testNamespace,
+		},
+		Spec: v1alpha1.CustomerSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{
+					Name: providerConfigName,
+				},
+			},
+			ForProvider: v1alpha1.CustomerParameters{},
+		},
+		Status: v1alpha1.CustomerStatus{},
+	}
+
+	meta.SetExternalName(c, "external-name")
+
+	for _, m := range cm {
+		m(c)
+	}
+
+	return c
+}
+
+type notCustomerResource struct {
+	resource.Managed
+}
+
+type MockCustomerClient struct {
+	CreateCustomerFn                   func(ctx context.Context, reqData metronomeClient.CreateCustomerRequest) (*metronomeClient.CreateCustomerResponse, error)
+	GetCustomerFn                      func(ctx context.Context, customerID string) (*metronomeClient.GetCustomerResponse, error)
+	UpdateCustomerAliasesFn            func(ctx context.Context, customerID string, reqData metronomeClient.UpdateAliasesRequest) error
+	UpdateCustomerNameFn               func(ctx context.Context, customerID string, reqData metronomeClient.UpdateNameRequest) error
+	ListCustomersFn                    func(ctx context.Context, reqData metronomeClient.ListCustomersRequest, nextPage string) (*metronomeClient.ListCustomersResponse, error)
+	ArchiveCustomerFn                  func(ctx context.Context, customerID string) error
+	GetBillingProviderConfigurationsFn func(ctx context.Context, customerID string) (*metronomeClient.GetBillingProviderConfigurationsResponse, error)
+	SetBillingProviderConfigurationsFn func(ctx context.Context, reqData metronomeClient.SetBillingProviderConfigurationsRequest) error
+}
+
+// CreateCustomer implements metronome.CustomerClient.
+func (m *MockCustomerClient) CreateCustomer(ctx context.Context, reqData metronomeClient.CreateCustomerRequest) (*metronomeClient.CreateCustomerResponse, error) {
+	return m.CreateCustomerFn(ctx, reqData)
+}
+
+// GetCustomer implements metronome.CustomerClient.
+func (m *MockCustomerClient) GetCustomer(ctx context.Context, customerID string) (*metronomeClient.GetCustomerResponse, error) {
+	return m.GetCustomerFn(ctx, customerID)
+}
+
+// UpdateCustomerAliases implements metronome.CustomerClient.
+func (m *MockCustomerClient) UpdateCustomerAliases(ctx context.Context, customerID string, reqData metronomeClient.UpdateAliasesRequest) error {
+	return m.UpdateCustomerAliasesFn(ctx, customerID, reqData)
+}
+
+// UpdateCustomerName implements metronome.CustomerClient.
+func (m *MockCustomerClient) UpdateCustomerName(ctx context.Context, customerID string, reqData metronomeClient.UpdateNameRequest) error {
+	return m.UpdateCustomerNameFn(ctx, customerID, reqData)
+}
+
+// ListCustomers implements metronome.CustomerClient.
+func (m *MockCustomerClient) ListCustomers(ctx context.Context, reqData metronomeClient.ListCustomersRequest, nextPage string) (*metronomeClient.ListCustomersResponse, error) {
+	return m.ListCustomersFn(ctx, reqData, nextPage)
+}
+
+// ArchiveCustomer implements metronome.CustomerClient.
+func (m *MockCustomerClient) ArchiveCustomer(ctx context.Context, customerID string) error {
+	return m.ArchiveCustomerFn(ctx, customerID)
+}
+
+// GetBillingProviderConfigurations implements metronome.CustomerClient.
+func (m *MockCustomerClient) GetBillingProviderConfigurations(ctx context.Context, customerID string) (*metronomeClient.GetBillingProviderConfigurationsResponse, error) {
+	return m.GetBillingProviderConfigurationsFn(ctx, customerID)
+}
+
+// SetBillingProviderConfigurations implements metronome.CustomerClient.
+func (m *MockCustomerClient) SetBillingProviderConfigurations(ctx context.Context, reqData metronomeClient.SetBillingProviderConfigurationsRequest) error {
+	return m.SetBillingProviderConfigurationsFn(ctx, reqData)
+}
+
+var _ (metronomeClient.CustomerClient) = (*MockCustomerClient)(nil)
+
+func noBillingProviders(ctx context.Context, customerID string) (*metronomeClient.GetBillingProviderConfigurationsResponse, error) {
+	return &metronomeClient.GetBillingProviderConfigurationsResponse{}, nil
+}
+
+func Test_External_Observe(t *testing.T) {
+	stripe := v1alpha1.BillingProviderConfiguration{
+		BillingProvider: "stripe",
+		DeliveryMethod:  "direct_to_billing_provider",
+		Configuration:   v1alpha1.BillingConfiguration{StripeCustomerID: "cus_1"},
+	}
+
+	type args struct {
+		metronome metronomeClient.CustomerClient
+		mg        resource.Managed
+	}
+	type want struct {
+		out managed.ExternalObservation
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotCustomerResource": {
+			args: args{
+				mg: notCustomerResource{},
+			},
+			want: want{
+				err: errors.New(errNotCustomer),
+			},
+		},
+		"NoExternalName": {
+			args: args{
+				mg: customer(func(mg *v1alpha1.Customer) {
+					meta.SetExternalName(mg, "")
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGetCustomer": {
+			args: args{
+				metronome: &MockCustomerClient{
+					GetCustomerFn: func(ctx context.Context, customerID string) (*metronomeClient.GetCustomerResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: customer(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCustomer),
+			},
+		},
+		"NotFoundIsDeleted": {
+			args: args{
+				metronome: &MockCustomerClient{
+					GetCustomerFn: func(ctx context.Context, customerID string) (*metronomeClient.GetCustomerResponse, error) {
+						return nil, &metronomeClient.APIError{StatusCode: 404}
+					},
+				},
+				mg: customer(),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"ArchivedIsDeleted": {
+			args: args{
+				metronome: &MockCustomerClient{
+					GetCustomerFn: func(ctx context.Context, customerID string) (*metronomeClient.GetCustomerResponse, error) {
+						return &metronomeClient.GetCustomerResponse{Data: metronomeClient.GetCustomerData{ID: customerID, ArchivedAt: "2025-01-01T00:00:00Z"}}, nil
+					},
+				},
+				mg: customer(),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToGetBillingProviders": {
+			args: args{
+				metronome: &MockCustomerClient{
+					GetCustomerFn: func(ctx context.Context, customerID string) (*metronomeClient.GetCustomerResponse, error) {
+						return &metronomeClient.GetCustomerResponse{Data: metronomeClient.GetCustomerData{ID: customerID}}, nil
+					},
+					GetBillingProviderConfigurationsFn: func(ctx context.Context, customerID string) (*metronomeClient.GetBillingProviderConfigurationsResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: customer(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetBillingProviders),
+			},
+		},
+		"NotUpToDate": {
+			args: args{
+				metronome: &MockCustomerClient{
+					GetCustomerFn: func(ctx context.Context, customerID string) (*metronomeClient.GetCustomerResponse, error) {
+						return &metronomeClient.GetCustomerResponse{Data: metronomeClient.GetCustomerData{
+							ID: customerID, Name: "name", IngestAliases: []string{"a"},
+						}}, nil
+					},
+					GetBillingProviderConfigurationsFn: noBillingProviders,
+				},
+				mg: customer(func(mg *v1alpha1.Customer) {
+					mg.Spec.ForProvider = v1alpha1.CustomerParameters{
+						Name:          "name",
+						IngestAliases: []string{"a", "b"},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"MissingBillingProviderIsNotUpToDate": {
+			args: args{
+				metronome: &MockCustomerClient{
+					GetCustomerFn: func(ctx context.Context, customerID string) (*metronomeClient.GetCustomerResponse, error) {
+						return &metronomeClient.GetCustomerResponse{Data: metronomeClient.GetCustomerData{ID: customerID, Name: "name"}}, nil
+					},
+					GetBillingProviderConfigurationsFn: noBillingProviders,
+				},
+				mg: customer(func(mg *v1alpha1.Customer) {
+					mg.Spec.ForProvider = v1alpha1.CustomerParameters{
+						Name:                          "name",
+						BillingProviderConfigurations: []v1alpha1.BillingProviderConfiguration{stripe},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"UpToDate": {
+			args: args{
+				metronome: &MockCustomerClient{
+					GetCustomerFn: func(ctx context.Context, customerID string) (*metronomeClient.GetCustomerResponse, error) {
+						return &metronomeClient.GetCustomerResponse{Data: metronomeClient.GetCustomerData{
+							ID:            customerID,
+							Name:          "name",
+							IngestAliases: []string{"b", "a"},
+							CustomFields:  map[string]string{"tier": "gold", metronomeClient.OwnerCustomFieldKey: "uid"},
+						}}, nil
+					},
+					GetBillingProviderConfigurationsFn: func(ctx context.Context, customerID string) (*metronomeClient.GetBillingProviderConfigurationsResponse, error) {
+						return &metronomeClient.GetBillingProviderConfigurationsResponse{Data: []metronomeClient.CustomerBillingProviderConfiguration{
+							{BillingProvider: "stripe", DeliveryMethod: "direct_to_billing_provider", Configuration: metronomeClient.BillingConfiguration{StripeCustomerID: "cus_1"}},
+							{BillingProvider: "netsuite", DeliveryMethod: "direct_to_billing_provider"},
+						}}, nil
+					},
+				},
+				mg: customer(func(mg *v1alpha1.Customer) {
+					mg.Spec.ForProvider = v1alpha1.CustomerParameters{
+						Name:                          "name",
+						IngestAliases:                 []string{"a", "b"},
+						CustomFields:                  map[string]string{"tier": "gold"},
+						BillingProviderConfigurations: []v1alpha1.BillingProviderConfiguration{stripe},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:    logging.NewNopLogger(),
+				metronome: tc.args.metronome,
+			}
+
+			ignoreDiff := cmpopts.IgnoreFields(managed.ExternalObservation{}, "Diff")
+
+			got, gotErr := e.Observe(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Observe(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.out, got, ignoreDiff); diff != "" {
+				t.Fatalf("e.Observe(...): -want out, +got out: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_External_Create(t *testing.T) {
+	type args struct {
+		metronome metronomeClient.CustomerClient
+		mg        resource.Managed
+	}
+	type want struct {
+		out          managed.ExternalCreation
+		externalName string
+		err          error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotCustomerResource": {
+			args: args{
+				mg: notCustomerResource{},
+			},
+			want: want{
+				err: errors.New(errNotCustomer),
+			},
+		},
+		"FailedToCreateCustomer": {
+			args: args{
+				metronome: &MockCustomerClient{
+					CreateCustomerFn: func(ctx context.Context, reqData metronomeClient.CreateCustomerRequest) (*metronomeClient.CreateCustomerResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: customer(),
+			},
+			want: want{
+				externalName: "external-name",
+				err:          errors.Wrap(errBoom, errCreateCustomer),
+			},
+		},
+		"Success": {
+			args: args{
+				metronome: &MockCustomerClient{
+					CreateCustomerFn: func(ctx context.Context, reqData metronomeClient.CreateCustomerRequest) (*metronomeClient.CreateCustomerResponse, error) {
+						return &metronomeClient.CreateCustomerResponse{Data: metronomeClient.CreateCustomerData{ID: "id1"}}, nil
+					},
+				},
+				mg: customer(),
+			},
+			want: want{
+				externalName: "id1",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:    logging.NewNopLogger(),
+				metronome: tc.args.metronome,
+			}
+			got, gotErr := e.Create(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Create(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Fatalf("e.Create(...): -want out, +got out: %s", diff)
+			}
+			if cr, ok := tc.args.mg.(*v1alpha1.Customer); ok {
+				if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(cr)); diff != "" {
+					t.Fatalf("e.Create(...): -want external name, +got external name: %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_External_Delete(t *testing.T) {
+	type args struct {
+		metronome metronomeClient.CustomerClient
+		mg        resource.Managed
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotCustomerResource": {
+			args: args{
+				mg: notCustomerResource{},
+			},
+			want: want{
+				err: errors.New(errNotCustomer),
+			},
+		},
+		"FailedToArchiveCustomer": {
+			args: args{
+				metronome: &MockCustomerClient{
+					ArchiveCustomerFn: func(ctx context.Context, customerID string) error {
+						return errBoom
+					},
+				},
+				mg: customer(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errArchiveCustomer),
+			},
+		},
+		"AlreadyArchived": {
+			args: args{
+				metronome: &MockCustomerClient{
+					ArchiveCustomerFn: func(ctx context.Context, customerID string) error {
+						return errors.Wrap(metronomeClient.ErrConflict, "failed to archive customer")
+					},
+				},
+				mg: customer(),
+			},
+		},
+		"Success": {
+			args: args{
+				metronome: &MockCustomerClient{
+					ArchiveCustomerFn: func(ctx context.Context, customerID string) error {
+						if customerID != "external-name" {
+							return errBoom
+						}
+						return nil
+					},
+				},
+				mg: customer(),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:    logging.NewNopLogger(),
+				metronome: tc.args.metronome,
+			}
+			_, gotErr := e.Delete(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Delete(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_MetronomeExternal_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	srv := metronometest.NewServer()
+	defer srv.Close()
+
+	client := srv.NewClient()
+	e := &metronomeExternal{
+		logger:       logging.NewNopLogger(),
+		metronome:    client.Customer(),
+		customFields: client.CustomField(),
+		keys:         client.CustomFieldKey(),
+		adopt:        true,
+	}
+	if err := client.CustomFieldKey().CreateCustomFieldKey(ctx, metronomeClient.CreateCustomFieldKeyRequest{Entity: metronomeClient.EntityCustomer, Key: "tier"}); err != nil {
+		t.Fatalf("CreateCustomFieldKey(...): %v", err)
+	}
+
+	cr := customer(func(c *v1alpha1.Customer) {
+		meta.SetExternalName(c, "")
+		c.SetUID("6f1c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f")
+		c.Spec.ForProvider = v1alpha1.CustomerParameters{
+			Name:          "Acme",
+			IngestAliases: []string{"acme@example.com"},
+			CustomFields:  map[string]string{"tier": "gold"},
+		}
+	})
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and up to date, got %+v, %v", o, err)
+	}
+	if cr.Status.AtProvider.ID != meta.GetExternalName(cr) {
+		t.Errorf("Observe(...): want status ID %q, got %q", meta.GetExternalName(cr), cr.Status.AtProvider.ID)
+	}
+
+	cr.Spec.ForProvider = v1alpha1.CustomerParameters{
+		Name:          "Acme Corp",
+		IngestAliases: []string{"acme@example.com", "billing@acme.example.com"},
+		BillingProviderConfigurations: []v1alpha1.BillingProviderConfiguration{{
+			BillingProvider: "stripe",
+			DeliveryMethod:  "direct_to_billing_provider",
+			Configuration:   v1alpha1.BillingConfiguration{StripeCustomerID: "cus_1"},
+		}},
+	}
+	if o, _ := e.Observe(ctx, cr); o.ResourceUpToDate {
+		t.Fatal("Observe(...): want out of date after changing the spec")
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if o, _ := e.Observe(ctx, cr); !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date after update, got diff %s", o.Diff)
+	}
+	if _, ok := cr.Status.AtProvider.CustomFields[metronomeClient.OwnerCustomFieldKey]; !ok {
+		t.Error("Update(...): want owner marker to be kept")
+	}
+
+	if _, err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete(...): %v", err)
+	}
+	if o, _ := e.Observe(ctx, cr); o.ResourceExists {
+		t.Fatal("Observe(...): want not existing after delete")
+	}
+	if _, err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete(...): want archived customer to be treated as deleted, got %v", err)
+	}
+}
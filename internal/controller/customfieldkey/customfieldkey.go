@@ -18,9 +18,14 @@ package customfieldkey
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
@@ -43,10 +48,19 @@ const (
 	errGetCustomFieldKey    = "failed to get custom field key"
 	errCreateCustomFieldKey = "failed to create custom field key"
 	errDeleteCustomFieldKey = "failed to delete custom field key"
+
+	errListCustomFieldValues   = "failed to list the existing values of the custom field key"
+	errDuplicateCustomField    = "cannot enforce uniqueness, value %q is set on %s %s"
+	errSaveCustomFieldValues   = "failed to save the existing values of the custom field key"
+	errRecreateCustomFieldKey  = "failed to recreate custom field key"
+	errRestoreCustomFieldValue = "failed to restore custom field value of %s %s"
+
+	errListCustomFieldKeys   = "failed to list CustomFieldKey resources"
+	errMissingCustomFieldKey = "no CustomFieldKey resource defines the %s custom field keys: %s"
 )
 
 // Setup adds a controller that reconciles CustomFieldKey managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
+func Setup(mgr ctrl.Manager, o controller.Options, co connector.Options) error {
 	name := managed.ControllerName(v1alpha1.CustomFieldKeyGroupKind)
 
 	reconcilerOptions := []managed.ReconcilerOption{
@@ -55,12 +69,19 @@ func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
 				Logger:               o.Logger,
 				Client:               mgr.GetClient(),
 				Usage:                resource.NewProviderConfigUsageTracker(mgr.GetClient(), &metronomev1alpha1.ProviderConfigUsage{}),
-				BaseURL:              baseUrl,
+				BaseURL:              co.BaseURL,
+				RateLimiters:         co.RateLimiters,
+				Metrics:              co.Metrics,
+				TracerProvider:       co.TracerProvider,
+				HTTPClients:          co.HTTPClients,
+				Clients:              co.Clients,
 				NewMetronomeClientFn: metronomeClient.New,
 				NewExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *metronomeExternal {
 					return &metronomeExternal{
-						logger:    o.Logger,
-						metronome: client.CustomFieldKey(),
+						logger:       o.Logger,
+						kube:         mgr.GetClient(),
+						metronome:    client.CustomFieldKey(),
+						customFields: client.CustomField(),
 					}
 				},
 			}),
@@ -92,8 +113,10 @@ func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
 }
 
 type metronomeExternal struct {
-	logger    logging.Logger
-	metronome metronomeClient.CustomFieldKeyClient
+	logger       logging.Logger
+	kube         client.Client
+	metronome    metronomeClient.CustomFieldKeyClient
+	customFields metronomeClient.CustomFieldClient
 }
 
 func (e *metronomeExternal) Disconnect(ctx context.Context) error {
@@ -120,32 +143,15 @@ func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (m
 	}
 
 	var foundCustomFieldKey *metronomeClient.CustomFieldKey
-	nextPage := ""
-	for {
-		res, err := e.metronome.ListCustomFieldKeys(ctx, metronomeClient.ListCustomFieldKeysRequest{
-			Entities: []string{cr.Spec.ForProvider.Entity},
-		}, nextPage)
+	keys := metronomeClient.AllCustomFieldKeys(ctx, e.metronome, metronomeClient.ListCustomFieldKeysRequest{
+		Entities: []string{cr.Spec.ForProvider.Entity},
+	})
+	for k, err := range keys {
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errGetCustomFieldKey)
 		}
-
-		if res == nil {
-			return managed.ExternalObservation{ResourceExists: false}, nil
-		}
-
-		found := false
-		for _, r := range res.Data {
-			if r.Key == cr.Spec.ForProvider.Key && r.Entity == cr.Spec.ForProvider.Entity {
-				foundCustomFieldKey = &r
-				found = true
-			}
-		}
-		if found {
-			break
-		}
-
-		nextPage = res.NextPage
-		if nextPage == "" {
+		if k.Key == cr.Spec.ForProvider.Key && k.Entity == cr.Spec.ForProvider.Entity {
+			foundCustomFieldKey = &k
 			break
 		}
 	}
@@ -158,7 +164,12 @@ func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (m
 	cr.Status.AtProvider = *converter.FromCustomFieldKey(foundCustomFieldKey)
 	cr.SetConditions(xpv1.Available())
 
-	upToDate := foundCustomFieldKey.EnforceUniqueness == cr.Spec.ForProvider.EnforceUniqueness
+	// values saved before the key was recreated still have to be restored
+	upToDate := foundCustomFieldKey.EnforceUniqueness == cr.Spec.ForProvider.EnforceUniqueness &&
+		len(cr.Status.RemovedValues) == 0
+	if upToDate && cr.GetCondition(metronomev1alpha1.TypeChangeBlocked).Status != corev1.ConditionUnknown {
+		cr.SetConditions(metronomev1alpha1.ChangeUnblocked())
+	}
 
 	return managed.ExternalObservation{
 		ResourceExists:   true,
@@ -182,18 +193,114 @@ func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (ma
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateCustomFieldKey)
 	}
 
-	return managed.ExternalCreation{}, nil
+	// restore the values saved by an update that removed the key but didn't
+	// finish
+	return managed.ExternalCreation{}, e.restore(ctx, cr)
 }
 
+// Update changes whether the custom field key enforces uniqueness. Metronome
+// can't update a key in place, so it is removed and added again with the new
+// setting. The existing values of the key are listed first, both to refuse
+// enforcing uniqueness over values that are already duplicated and to restore
+// them once the key has been recreated. They are saved to the status before
+// the key is removed, so that values not yet restored when an update fails are
+// restored by the next reconcile, whether the key was added back or not.
+//
+// Keys of entities whose values can't be listed are never recreated, and
+// neither are keys with values on archived objects, which can't be set again.
 func (e *metronomeExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	_, ok := mg.(*v1alpha1.CustomFieldKey)
+	cr, ok := mg.(*v1alpha1.CustomFieldKey)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotCustomFieldKey)
 	}
 
 	e.logger.Debug("Updating")
 
-	return managed.ExternalUpdate{}, errors.New("updating a custom field key is not supported")
+	p := cr.Spec.ForProvider
+	if cr.Status.AtProvider.EnforceUniqueness != p.EnforceUniqueness {
+		if err := e.recreate(ctx, cr); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
+	return managed.ExternalUpdate{}, e.restore(ctx, cr)
+}
+
+// recreate saves the values of the custom field key to the status, then
+// removes the key and adds it again with the spec.
+func (e *metronomeExternal) recreate(ctx context.Context, cr *v1alpha1.CustomFieldKey) error {
+	p := cr.Spec.ForProvider
+	values, err := e.customFields.ListCustomFieldValues(ctx, p.Entity, p.Key)
+	if errors.Is(err, metronomeClient.ErrCustomFieldValuesArchived) {
+		cr.SetConditions(metronomev1alpha1.ChangeBlocked(metronomev1alpha1.ReasonArchivedCustomFieldValues, err.Error()))
+	}
+	if err != nil {
+		return errors.Wrap(err, errListCustomFieldValues)
+	}
+	if p.EnforceUniqueness {
+		if err := checkUnique(p.Entity, values); err != nil {
+			return err
+		}
+	}
+	if cr.GetCondition(metronomev1alpha1.TypeChangeBlocked).Status != corev1.ConditionUnknown {
+		cr.SetConditions(metronomev1alpha1.ChangeUnblocked())
+	}
+
+	cr.Status.RemovedValues = values
+	if len(values) > 0 {
+		if err := e.kube.Status().Update(ctx, cr); err != nil {
+			return errors.Wrap(err, errSaveCustomFieldValues)
+		}
+	}
+
+	err = e.metronome.DeleteCustomFieldKey(ctx, metronomeClient.DeleteCustomFieldKeyRequest{
+		Entity: p.Entity,
+		Key:    p.Key,
+	})
+	if err != nil && !errors.Is(err, metronomeClient.ErrNotFound) {
+		return errors.Wrap(err, errRecreateCustomFieldKey)
+	}
+
+	converter := &converters.CustomFieldKeyConverterImpl{}
+	if err := e.metronome.CreateCustomFieldKey(ctx, *converter.FromCustomFieldKeySpec(&p)); err != nil {
+		return errors.Wrap(err, errRecreateCustomFieldKey)
+	}
+	return nil
+}
+
+// restore sets the values saved to the status before the custom field key was
+// recreated, and clears them once they have all been set.
+func (e *metronomeExternal) restore(ctx context.Context, cr *v1alpha1.CustomFieldKey) error {
+	p := cr.Spec.ForProvider
+	values := cr.Status.RemovedValues
+	for _, id := range slices.Sorted(maps.Keys(values)) {
+		err := e.customFields.SetCustomFieldValues(ctx, metronomeClient.SetCustomFieldValuesRequest{
+			Entity:       p.Entity,
+			EntityID:     id,
+			CustomFields: map[string]string{p.Key: values[id]},
+		})
+		if err != nil {
+			return errors.Wrapf(err, errRestoreCustomFieldValue, p.Entity, id)
+		}
+	}
+	cr.Status.RemovedValues = nil
+	return nil
+}
+
+// checkUnique returns an error naming the first value, in sorted order, that
+// is set on more than one object.
+func checkUnique(entity string, values map[string]string) error {
+	ids := map[string][]string{}
+	for id, v := range values {
+		ids[v] = append(ids[v], id)
+	}
+	for _, v := range slices.Sorted(maps.Keys(ids)) {
+		if len(ids[v]) > 1 {
+			slices.Sort(ids[v])
+			return errors.Errorf(errDuplicateCustomField, v, entity, strings.Join(ids[v], ", "))
+		}
+	}
+	return nil
 }
 
 func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
@@ -209,8 +316,40 @@ func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (ma
 		Key:    cr.Spec.ForProvider.Key,
 	})
 	if err != nil {
+		// the key has already been removed
+		if errors.Is(err, metronomeClient.ErrNotFound) {
+			return managed.ExternalDelete{}, nil
+		}
 		return managed.ExternalDelete{}, errors.Wrap(err, errDeleteCustomFieldKey)
 	}
 
 	return managed.ExternalDelete{}, nil
 }
+
+// ValidateKeys returns an error if any of the custom fields doesn't have a
+// CustomFieldKey resource for the entity. It lets resources that set custom
+// fields fail before reaching Metronome when a key hasn't been declared.
+func ValidateKeys(ctx context.Context, kube client.Reader, entity string, fields map[string]string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	l := &v1alpha1.CustomFieldKeyList{}
+	if err := kube.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListCustomFieldKeys)
+	}
+
+	var missing []string
+	for k := range fields {
+		if !slices.ContainsFunc(l.Items, func(cfk v1alpha1.CustomFieldKey) bool {
+			return cfk.Spec.ForProvider.Entity == entity && cfk.Spec.ForProvider.Key == k
+		}) {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return errors.Errorf(errMissingCustomFieldKey, entity, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -2,11 +2,16 @@ package customfieldkey
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -16,7 +21,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/redbackthomson/provider-metronome/apis/customfieldkey/v1alpha1"
+	metronomev1alpha1 "github.com/redbackthomson/provider-metronome/apis/v1alpha1"
 	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/clients/metronome/metronometest"
 )
 
 const (
@@ -81,6 +88,29 @@ func (m *MockCustomFieldKeyClient) ListCustomFieldKeys(ctx context.Context, reqD
 
 var _ (metronomeClient.CustomFieldKeyClient) = (*MockCustomFieldKeyClient)(nil)
 
+type MockCustomFieldClient struct {
+	SetCustomFieldValuesFn    func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error
+	DeleteCustomFieldValuesFn func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldValuesRequest) error
+	ListCustomFieldValuesFn   func(ctx context.Context, entity, key string) (map[string]string, error)
+}
+
+// SetCustomFieldValues implements metronome.CustomFieldClient.
+func (m *MockCustomFieldClient) SetCustomFieldValues(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+	return m.SetCustomFieldValuesFn(ctx, reqData)
+}
+
+// DeleteCustomFieldValues implements metronome.CustomFieldClient.
+func (m *MockCustomFieldClient) DeleteCustomFieldValues(ctx context.Context, reqData metronomeClient.DeleteCustomFieldValuesRequest) error {
+	return m.DeleteCustomFieldValuesFn(ctx, reqData)
+}
+
+// ListCustomFieldValues implements metronome.CustomFieldClient.
+func (m *MockCustomFieldClient) ListCustomFieldValues(ctx context.Context, entity, key string) (map[string]string, error) {
+	return m.ListCustomFieldValuesFn(ctx, entity, key)
+}
+
+var _ (metronomeClient.CustomFieldClient) = (*MockCustomFieldClient)(nil)
+
 func Test_External_Observe(t *testing.T) {
 	type args struct {
 		metronome metronomeClient.CustomFieldKeyClient
@@ -166,6 +196,30 @@ func Test_External_Observe(t *testing.T) {
 				err: nil,
 			},
 		},
+		"SavedValuesNotRestored": {
+			args: args{
+				metronome: &MockCustomFieldKeyClient{
+					ListCustomFieldKeysFn: func(ctx context.Context, reqData metronomeClient.ListCustomFieldKeysRequest, nextPage string) (*metronomeClient.ListCustomFieldKeysResponse, error) {
+						return &metronomeClient.ListCustomFieldKeysResponse{
+							Data: []metronomeClient.CustomFieldKey{
+								{Key: "key1", Entity: "entity1", EnforceUniqueness: true},
+							},
+						}, nil
+					},
+				},
+				mg: customFieldKey(func(mg *v1alpha1.CustomFieldKey) {
+					mg.Spec.ForProvider = v1alpha1.CustomFieldKeyParameters{
+						Key:               "key1",
+						Entity:            "entity1",
+						EnforceUniqueness: true,
+					}
+					mg.Status.RemovedValues = map[string]string{"a": "x"}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
 		"UpToDate": {
 			args: args{
 				metronome: &MockCustomFieldKeyClient{
@@ -223,8 +277,9 @@ func Test_External_Observe(t *testing.T) {
 
 func Test_External_Create(t *testing.T) {
 	type args struct {
-		metronome metronomeClient.CustomFieldKeyClient
-		mg        resource.Managed
+		metronome    metronomeClient.CustomFieldKeyClient
+		customFields metronomeClient.CustomFieldClient
+		mg           resource.Managed
 	}
 	type want struct {
 		out managed.ExternalCreation
@@ -255,6 +310,27 @@ func Test_External_Create(t *testing.T) {
 				err: errors.Wrap(errBoom, errCreateCustomFieldKey),
 			},
 		},
+		"RestoresSavedValues": {
+			args: args{
+				metronome: &MockCustomFieldKeyClient{
+					CreateCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.CreateCustomFieldKeyRequest) error {
+						return nil
+					},
+				},
+				customFields: &MockCustomFieldClient{
+					SetCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+						return errBoom
+					},
+				},
+				mg: customFieldKey(func(mg *v1alpha1.CustomFieldKey) {
+					mg.Spec.ForProvider = v1alpha1.CustomFieldKeyParameters{Key: "key", Entity: "customer"}
+					mg.Status.RemovedValues = map[string]string{"a": "x"}
+				}),
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errRestoreCustomFieldValue, "customer", "a"),
+			},
+		},
 		"Success": {
 			args: args{
 				metronome: &MockCustomFieldKeyClient{
@@ -288,8 +364,9 @@ func Test_External_Create(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			e := &metronomeExternal{
-				logger:    logging.NewNopLogger(),
-				metronome: tc.args.metronome,
+				logger:       logging.NewNopLogger(),
+				metronome:    tc.args.metronome,
+				customFields: tc.args.customFields,
 			}
 			got, gotErr := e.Create(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
@@ -303,6 +380,307 @@ func Test_External_Create(t *testing.T) {
 	}
 }
 
+func Test_External_Update(t *testing.T) {
+	unique := func(mg *v1alpha1.CustomFieldKey) {
+		mg.Spec.ForProvider = v1alpha1.CustomFieldKeyParameters{
+			Key:               "key",
+			Entity:            "customer",
+			EnforceUniqueness: true,
+		}
+	}
+	values := func(v map[string]string) func(ctx context.Context, entity, key string) (map[string]string, error) {
+		return func(ctx context.Context, entity, key string) (map[string]string, error) {
+			return v, nil
+		}
+	}
+	recreated := &MockCustomFieldKeyClient{
+		DeleteCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldKeyRequest) error {
+			return nil
+		},
+		CreateCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.CreateCustomFieldKeyRequest) error {
+			return nil
+		},
+	}
+
+	saved := func(v map[string]string) *test.MockClient {
+		return &test.MockClient{
+			MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+				if diff := cmp.Diff(v, obj.(*v1alpha1.CustomFieldKey).Status.RemovedValues); diff != "" {
+					t.Errorf("Status().Update(...): -want removed values, +got removed values: %s", diff)
+				}
+				return nil
+			},
+		}
+	}
+
+	type args struct {
+		kube         client.Client
+		metronome    metronomeClient.CustomFieldKeyClient
+		customFields metronomeClient.CustomFieldClient
+		mg           resource.Managed
+	}
+	type want struct {
+		out     managed.ExternalUpdate
+		err     error
+		removed map[string]string
+		blocked corev1.ConditionStatus
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotCustomFieldKeyResource": {
+			args: args{
+				mg: notCustomFieldKeyResource{},
+			},
+			want: want{
+				err: errors.New(errNotCustomFieldKey),
+			},
+		},
+		"FailedToListValues": {
+			args: args{
+				customFields: &MockCustomFieldClient{
+					ListCustomFieldValuesFn: func(ctx context.Context, entity, key string) (map[string]string, error) {
+						return nil, errBoom
+					},
+				},
+				mg: customFieldKey(unique),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListCustomFieldValues),
+			},
+		},
+		"EntityNotListable": {
+			args: args{
+				customFields: &MockCustomFieldClient{
+					ListCustomFieldValuesFn: func(ctx context.Context, entity, key string) (map[string]string, error) {
+						return nil, metronomeClient.ErrCustomFieldValuesNotListable
+					},
+				},
+				mg: customFieldKey(unique),
+			},
+			want: want{
+				err: errors.Wrap(metronomeClient.ErrCustomFieldValuesNotListable, errListCustomFieldValues),
+			},
+		},
+		"ValuesOnArchivedObjects": {
+			args: args{
+				customFields: &MockCustomFieldClient{
+					ListCustomFieldValuesFn: func(ctx context.Context, entity, key string) (map[string]string, error) {
+						return nil, metronomeClient.ErrCustomFieldValuesArchived
+					},
+				},
+				mg: customFieldKey(unique),
+			},
+			want: want{
+				err:     errors.Wrap(metronomeClient.ErrCustomFieldValuesArchived, errListCustomFieldValues),
+				blocked: corev1.ConditionTrue,
+			},
+		},
+		"DuplicateValues": {
+			args: args{
+				customFields: &MockCustomFieldClient{
+					ListCustomFieldValuesFn: values(map[string]string{"c": "a", "b": "x", "a": "x"}),
+				},
+				mg: customFieldKey(unique),
+			},
+			want: want{
+				err: errors.Errorf(errDuplicateCustomField, "x", "customer", "a, b"),
+			},
+		},
+		"FailedToSaveValues": {
+			args: args{
+				kube: &test.MockClient{MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom)},
+				metronome: &MockCustomFieldKeyClient{
+					DeleteCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldKeyRequest) error {
+						t.Errorf("DeleteCustomFieldKey: want key kept when its values can't be saved")
+						return nil
+					},
+				},
+				customFields: &MockCustomFieldClient{
+					ListCustomFieldValuesFn: values(map[string]string{"a": "x"}),
+				},
+				mg: customFieldKey(unique),
+			},
+			want: want{
+				err:     errors.Wrap(errBoom, errSaveCustomFieldValues),
+				removed: map[string]string{"a": "x"},
+			},
+		},
+		"FailedToDeleteKey": {
+			args: args{
+				metronome: &MockCustomFieldKeyClient{
+					DeleteCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldKeyRequest) error {
+						return errBoom
+					},
+				},
+				customFields: &MockCustomFieldClient{
+					ListCustomFieldValuesFn: values(nil),
+				},
+				mg: customFieldKey(unique),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errRecreateCustomFieldKey),
+			},
+		},
+		"FailedToCreateKey": {
+			args: args{
+				metronome: &MockCustomFieldKeyClient{
+					DeleteCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldKeyRequest) error {
+						return nil
+					},
+					CreateCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.CreateCustomFieldKeyRequest) error {
+						return errBoom
+					},
+				},
+				customFields: &MockCustomFieldClient{
+					ListCustomFieldValuesFn: values(nil),
+				},
+				mg: customFieldKey(unique),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errRecreateCustomFieldKey),
+			},
+		},
+		"FailedToRestoreValue": {
+			args: args{
+				kube:      saved(map[string]string{"a": "x"}),
+				metronome: recreated,
+				customFields: &MockCustomFieldClient{
+					ListCustomFieldValuesFn: values(map[string]string{"a": "x"}),
+					SetCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+						return errBoom
+					},
+				},
+				mg: customFieldKey(unique),
+			},
+			want: want{
+				err:     errors.Wrapf(errBoom, errRestoreCustomFieldValue, "customer", "a"),
+				removed: map[string]string{"a": "x"},
+			},
+		},
+		"RestoresSavedValues": {
+			args: args{
+				metronome: &MockCustomFieldKeyClient{
+					DeleteCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldKeyRequest) error {
+						t.Errorf("DeleteCustomFieldKey: want recreated key kept")
+						return nil
+					},
+				},
+				customFields: &MockCustomFieldClient{
+					SetCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+						return nil
+					},
+				},
+				mg: customFieldKey(unique, func(mg *v1alpha1.CustomFieldKey) {
+					mg.Status.AtProvider.EnforceUniqueness = true
+					mg.Status.RemovedValues = map[string]string{"a": "x"}
+				}),
+			},
+		},
+		"DisablingAllowsDuplicates": {
+			args: args{
+				metronome: &MockCustomFieldKeyClient{
+					DeleteCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldKeyRequest) error {
+						return metronomeClient.ErrNotFound
+					},
+					CreateCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.CreateCustomFieldKeyRequest) error {
+						if reqData.EnforceUniqueness {
+							t.Errorf("CreateCustomFieldKey: want uniqueness disabled")
+						}
+						return nil
+					},
+				},
+				kube: saved(map[string]string{"a": "x", "b": "x"}),
+				customFields: &MockCustomFieldClient{
+					ListCustomFieldValuesFn: values(map[string]string{"a": "x", "b": "x"}),
+					SetCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+						return nil
+					},
+				},
+				mg: customFieldKey(unique, func(mg *v1alpha1.CustomFieldKey) {
+					mg.Spec.ForProvider.EnforceUniqueness = false
+					mg.Status.AtProvider.EnforceUniqueness = true
+				}),
+			},
+		},
+		"Success": {
+			args: args{
+				kube: saved(map[string]string{"a": "x"}),
+				metronome: &MockCustomFieldKeyClient{
+					DeleteCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldKeyRequest) error {
+						expected := metronomeClient.DeleteCustomFieldKeyRequest{
+							Key:    "key",
+							Entity: "customer",
+						}
+						if diff := cmp.Diff(expected, reqData); diff != "" {
+							t.Errorf("DeleteCustomFieldKey mismatched: -want req, +got req: %s", diff)
+						}
+						return nil
+					},
+					CreateCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.CreateCustomFieldKeyRequest) error {
+						expected := metronomeClient.CreateCustomFieldKeyRequest{
+							Key:               "key",
+							Entity:            "customer",
+							EnforceUniqueness: true,
+						}
+						if diff := cmp.Diff(expected, reqData); diff != "" {
+							t.Errorf("CreateCustomFieldKey mismatched: -want req, +got req: %s", diff)
+						}
+						return nil
+					},
+				},
+				customFields: &MockCustomFieldClient{
+					ListCustomFieldValuesFn: values(map[string]string{"a": "x"}),
+					SetCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+						expected := metronomeClient.SetCustomFieldValuesRequest{
+							Entity:       "customer",
+							EntityID:     "a",
+							CustomFields: map[string]string{"key": "x"},
+						}
+						if diff := cmp.Diff(expected, reqData); diff != "" {
+							t.Errorf("SetCustomFieldValues mismatched: -want req, +got req: %s", diff)
+						}
+						return nil
+					},
+				},
+				mg: customFieldKey(unique),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:       logging.NewNopLogger(),
+				kube:         tc.args.kube,
+				metronome:    tc.args.metronome,
+				customFields: tc.args.customFields,
+			}
+			got, gotErr := e.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Update(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Fatalf("e.Update(...): -want out, +got out: %s", diff)
+			}
+
+			if cr, ok := tc.args.mg.(*v1alpha1.CustomFieldKey); ok {
+				if diff := cmp.Diff(tc.want.removed, cr.Status.RemovedValues, cmpopts.EquateEmpty()); diff != "" {
+					t.Fatalf("e.Update(...): -want removed values, +got removed values: %s", diff)
+				}
+				want := tc.want.blocked
+				if want == "" {
+					want = corev1.ConditionUnknown
+				}
+				if diff := cmp.Diff(want, cr.GetCondition(metronomev1alpha1.TypeChangeBlocked).Status); diff != "" {
+					t.Fatalf("e.Update(...): -want blocked condition, +got blocked condition: %s", diff)
+				}
+			}
+		})
+	}
+}
+
 func Test_External_Delete(t *testing.T) {
 	type args struct {
 		metronome metronomeClient.CustomFieldKeyClient
@@ -383,3 +761,184 @@ func Test_External_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_MetronomeExternal_ChangeUniqueness(t *testing.T) {
+	ctx := context.Background()
+
+	srv := metronometest.NewServer()
+	defer srv.Close()
+
+	client := srv.NewClient()
+	e := &metronomeExternal{
+		logger:       logging.NewNopLogger(),
+		kube:         test.NewMockClient(),
+		metronome:    client.CustomFieldKey(),
+		customFields: client.CustomField(),
+	}
+
+	cr := customFieldKey(func(mg *v1alpha1.CustomFieldKey) {
+		mg.Spec.ForProvider = v1alpha1.CustomFieldKeyParameters{
+			Key:    "region",
+			Entity: metronomeClient.EntityCustomer,
+		}
+	})
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+
+	var ids []string
+	for _, region := range []string{"us", "eu"} {
+		res, err := client.Customer().CreateCustomer(ctx, metronomeClient.CreateCustomerRequest{
+			Name:         "customer-" + region,
+			CustomFields: map[string]string{"region": region},
+		})
+		if err != nil {
+			t.Fatalf("CreateCustomer(...): %v", err)
+		}
+		ids = append(ids, res.Data.ID)
+	}
+
+	cr.Spec.ForProvider.EnforceUniqueness = true
+	if o, err := e.Observe(ctx, cr); err != nil || o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want key to be out of date, got %+v, %v", o, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want key to be up to date, got %+v, %v", o, err)
+	}
+	for i, region := range []string{"us", "eu"} {
+		res, err := client.Customer().GetCustomer(ctx, ids[i])
+		if err != nil {
+			t.Fatalf("GetCustomer(...): %v", err)
+		}
+		if got := res.Data.CustomFields["region"]; got != region {
+			t.Errorf("GetCustomer(...): want region %q, got %q", region, got)
+		}
+	}
+
+	// an update that fails once the key is removed is finished by creating
+	// the key again and restoring the saved values
+	interrupted := *e
+	interrupted.metronome = &MockCustomFieldKeyClient{
+		ListCustomFieldKeysFn:  client.CustomFieldKey().ListCustomFieldKeys,
+		DeleteCustomFieldKeyFn: client.CustomFieldKey().DeleteCustomFieldKey,
+		CreateCustomFieldKeyFn: func(ctx context.Context, reqData metronomeClient.CreateCustomFieldKeyRequest) error {
+			return errBoom
+		},
+	}
+	cr.Spec.ForProvider.EnforceUniqueness = false
+	if _, err := interrupted.Update(ctx, cr); !errors.Is(err, errBoom) {
+		t.Fatalf("Update(...): want %v, got %v", errBoom, err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || o.ResourceExists {
+		t.Fatalf("Observe(...): want key to be removed, got %+v, %v", o, err)
+	}
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want key to be up to date, got %+v, %v", o, err)
+	}
+	for i, region := range []string{"us", "eu"} {
+		res, err := client.Customer().GetCustomer(ctx, ids[i])
+		if err != nil {
+			t.Fatalf("GetCustomer(...): %v", err)
+		}
+		if got := res.Data.CustomFields["region"]; got != region {
+			t.Errorf("GetCustomer(...): want restored region %q, got %q", region, got)
+		}
+	}
+
+	// a duplicated value keeps uniqueness from being enforced again
+	if err := client.CustomField().SetCustomFieldValues(ctx, metronomeClient.SetCustomFieldValuesRequest{
+		Entity:       metronomeClient.EntityCustomer,
+		EntityID:     ids[1],
+		CustomFields: map[string]string{"region": "us"},
+	}); err != nil {
+		t.Fatalf("SetCustomFieldValues(...): %v", err)
+	}
+	cr.Spec.ForProvider.EnforceUniqueness = true
+	if _, err := e.Observe(ctx, cr); err != nil {
+		t.Fatalf("Observe(...): %v", err)
+	}
+	want := errors.Errorf(errDuplicateCustomField, "us", metronomeClient.EntityCustomer, strings.Join(slices.Sorted(slices.Values(ids)), ", "))
+	if _, err := e.Update(ctx, cr); err == nil || err.Error() != want.Error() {
+		t.Fatalf("Update(...): want %v, got %v", want, err)
+	}
+}
+
+func Test_ValidateKeys(t *testing.T) {
+	keys := func(keys ...v1alpha1.CustomFieldKeyParameters) func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+		return func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			l := obj.(*v1alpha1.CustomFieldKeyList)
+			for _, k := range keys {
+				l.Items = append(l.Items, v1alpha1.CustomFieldKey{
+					Spec: v1alpha1.CustomFieldKeySpec{ForProvider: k},
+				})
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		kube   client.Reader
+		entity string
+		fields map[string]string
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NoFields": {
+			args: args{
+				entity: metronomeClient.EntityProduct,
+			},
+		},
+		"FailedToListKeys": {
+			args: args{
+				kube:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				entity: metronomeClient.EntityProduct,
+				fields: map[string]string{"team": "finance"},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListCustomFieldKeys),
+			},
+		},
+		"KeysOfOtherEntity": {
+			args: args{
+				kube: &test.MockClient{MockList: keys(
+					v1alpha1.CustomFieldKeyParameters{Entity: metronomeClient.EntityProduct, Key: "team"},
+					v1alpha1.CustomFieldKeyParameters{Entity: metronomeClient.EntityCustomer, Key: "region"},
+				)},
+				entity: metronomeClient.EntityProduct,
+				fields: map[string]string{"team": "finance", "region": "us", "tier": "gold"},
+			},
+			want: want{
+				err: errors.Errorf(errMissingCustomFieldKey, metronomeClient.EntityProduct, "region, tier"),
+			},
+		},
+		"AllKeysDefined": {
+			args: args{
+				kube: &test.MockClient{MockList: keys(
+					v1alpha1.CustomFieldKeyParameters{Entity: metronomeClient.EntityProduct, Key: "team"},
+					v1alpha1.CustomFieldKeyParameters{Entity: metronomeClient.EntityProduct, Key: "region"},
+				)},
+				entity: metronomeClient.EntityProduct,
+				fields: map[string]string{"team": "finance", "region": "us"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateKeys(context.Background(), tc.args.kube, tc.args.entity, tc.args.fields)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("ValidateKeys(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
@@ -21,8 +21,11 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
+	"github.com/redbackthomson/provider-metronome/internal/connector"
 	"github.com/redbackthomson/provider-metronome/internal/controller/billablemetric"
 	"github.com/redbackthomson/provider-metronome/internal/controller/config"
+	"github.com/redbackthomson/provider-metronome/internal/controller/contract"
+	"github.com/redbackthomson/provider-metronome/internal/controller/customer"
 	"github.com/redbackthomson/provider-metronome/internal/controller/customfieldkey"
 	"github.com/redbackthomson/provider-metronome/internal/controller/product"
 	"github.com/redbackthomson/provider-metronome/internal/controller/rate"
@@ -31,23 +34,29 @@ import (
 
 // Setup creates all Template controllers with the supplied logger and adds them to
 // the supplied manager.
-func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
-	if err := config.Setup(mgr, o); err != nil {
+func Setup(mgr ctrl.Manager, o controller.Options, co connector.Options) error {
+	if err := config.Setup(mgr, o, co); err != nil {
 		return err
 	}
-	if err := billablemetric.Setup(mgr, o, baseUrl); err != nil {
+	if err := billablemetric.Setup(mgr, o, co); err != nil {
 		return err
 	}
-	if err := customfieldkey.Setup(mgr, o, baseUrl); err != nil {
+	if err := contract.Setup(mgr, o, co); err != nil {
 		return err
 	}
-	if err := product.Setup(mgr, o, baseUrl); err != nil {
+	if err := customer.Setup(mgr, o, co); err != nil {
 		return err
 	}
-	if err := rate.Setup(mgr, o, baseUrl); err != nil {
+	if err := customfieldkey.Setup(mgr, o, co); err != nil {
 		return err
 	}
-	if err := ratecard.Setup(mgr, o, baseUrl); err != nil {
+	if err := product.Setup(mgr, o, co); err != nil {
+		return err
+	}
+	if err := rate.Setup(mgr, o, co); err != nil {
+		return err
+	}
+	if err := ratecard.Setup(mgr, o, co); err != nil {
 		return err
 	}
 	return nil
@@ -18,13 +18,18 @@ package product
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
@@ -40,6 +45,7 @@ import (
 	metronomev1alpha1 "github.com/redbackthomson/provider-metronome/apis/v1alpha1"
 	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
 	"github.com/redbackthomson/provider-metronome/internal/connector"
+	"github.com/redbackthomson/provider-metronome/internal/controller/customfieldkey"
 	"github.com/redbackthomson/provider-metronome/internal/converters"
 )
 
@@ -49,26 +55,46 @@ const (
 	errCreateProduct  = "failed to create product"
 	errUpdateProduct  = "failed to update product"
 	errArchiveProduct = "failed to archive product"
+	errAdoptProduct   = "failed to find product previously created for this resource"
+	errEnsureOwnerKey = "failed to create owner custom field key"
 	errNoID           = "product does not have ID"
-	errNoStartingAt   = "forProvider.startingAt is required for updates"
+	errNoStartingAt   = "forProvider.startingAt is required for updates when forProvider.updatePolicy is Explicit"
+	errStartingAtHour = "forProvider.startingAt must be an RFC 3339 timestamp on an hour boundary"
+	errScheduleUpdate = "failed to schedule product update starting at %s"
+	errUpdateFields   = "failed to update product custom fields"
 )
 
 // Setup adds a controller that reconciles Product managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
+func Setup(mgr ctrl.Manager, o controller.Options, co connector.Options) error {
 	name := managed.ControllerName(v1alpha1.ProductGroupKind)
 
+	var fieldKeys client.Reader
+	if co.ValidateCustomFieldKeys {
+		fieldKeys = mgr.GetClient()
+	}
+
 	reconcilerOptions := []managed.ReconcilerOption{
 		managed.WithExternalConnecter(
 			&connector.Connector[*v1alpha1.Product, *metronomeExternal]{
 				Logger:               o.Logger,
 				Client:               mgr.GetClient(),
 				Usage:                resource.NewProviderConfigUsageTracker(mgr.GetClient(), &metronomev1alpha1.ProviderConfigUsage{}),
-				BaseURL:              baseUrl,
+				BaseURL:              co.BaseURL,
+				RateLimiters:         co.RateLimiters,
+				Metrics:              co.Metrics,
+				TracerProvider:       co.TracerProvider,
+				HTTPClients:          co.HTTPClients,
+				Clients:              co.Clients,
 				NewMetronomeClientFn: metronomeClient.New,
 				NewExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *metronomeExternal {
 					return &metronomeExternal{
-						logger:    o.Logger,
-						metronome: client.Product(),
+						logger:       o.Logger,
+						metronome:    client.Product(),
+						keys:         client.CustomFieldKey(),
+						customFields: client.CustomField(),
+						fieldKeys:    fieldKeys,
+						clock:        clock.RealClock{},
+						adopt:        co.AdoptCreatedObjects,
 					}
 				},
 			}),
@@ -100,8 +126,19 @@ func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
 }
 
 type metronomeExternal struct {
-	logger    logging.Logger
-	metronome metronomeClient.ProductClient
+	logger       logging.Logger
+	metronome    metronomeClient.ProductClient
+	keys         metronomeClient.CustomFieldKeyClient
+	customFields metronomeClient.CustomFieldClient
+	clock        clock.PassiveClock
+
+	// fieldKeys lists the CustomFieldKey resources the custom fields of the
+	// spec are validated against. They aren't validated if it is nil.
+	fieldKeys client.Reader
+
+	// adopt marks the objects created in Metronome with the UID of their
+	// managed resource, and adopts them if their ID was never recorded.
+	adopt bool
 }
 
 func (e *metronomeExternal) Disconnect(ctx context.Context) error {
@@ -117,16 +154,28 @@ func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (m
 	e.logger.Debug("Observing")
 
 	id := meta.GetExternalName(cr)
+	adopted := false
 	if id == "" {
-		return managed.ExternalObservation{}, nil
+		// a previous reconcile may have created the product without
+		// recording its ID
+		owned, err := e.findOwned(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errAdoptProduct)
+		}
+		if owned == "" {
+			return managed.ExternalObservation{}, nil
+		}
+		e.logger.Debug("Adopting product", "id", owned)
+		meta.SetExternalName(cr, owned)
+		id, adopted = owned, true
 	}
 
 	res, err := e.metronome.GetProduct(ctx, metronomeClient.GetProductRequest{
 		ID: id,
 	})
 	if err != nil {
-		// the external name isn't valid
-		if errors.Is(err, metronomeClient.ErrProductInvalidName) {
+		// the external name isn't valid, or the product no longer exists
+		if errors.Is(err, metronomeClient.ErrProductInvalidName) || errors.Is(err, metronomeClient.ErrNotFound) {
 			return managed.ExternalObservation{ResourceExists: false}, nil
 		}
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetProduct)
@@ -145,18 +194,43 @@ func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (m
 	card := &res.Data
 
 	converter := &converters.ProductConverterImpl{}
+	updateStartingAt := cr.Status.AtProvider.UpdateStartingAt
 	cr.Status.AtProvider = *converter.FromProduct(card)
+	cr.Status.AtProvider.UpdateStartingAt = updateStartingAt
 	cr.SetConditions(xpv1.Available())
 
-	upToDate, diff := isUpToDate(cr, card)
+	now := e.clock.Now()
+	upToDate, diff := isUpToDate(cr, card, now, comparedAt(cr, now))
+	upToDate = upToDate && len(missingScheduleEntries(cr, card, now)) == 0 && sameCustomFields(cr, card)
 
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: upToDate,
-		Diff:             diff,
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: adopted,
+		Diff:                    diff,
 	}, nil
 }
 
+// findOwned returns the ID of the product marked as created by the managed
+// resource, or an empty string if there is none.
+func (e *metronomeExternal) findOwned(ctx context.Context, cr *v1alpha1.Product) (string, error) {
+	// only a create that was attempted can have lost the ID of the object
+	// it created
+	uid := string(cr.GetUID())
+	if !e.adopt || uid == "" || meta.GetExternalCreatePending(cr).IsZero() {
+		return "", nil
+	}
+	for p, err := range metronomeClient.AllProducts(ctx, e.metronome, metronomeClient.ListProductsRequest{ArchiveFilter: "NOT_ARCHIVED"}) {
+		if err != nil {
+			return "", err
+		}
+		if metronomeClient.IsOwnedBy(p.CustomFields, uid) && p.Current.Name == cr.Spec.ForProvider.Name {
+			return p.ID, nil
+		}
+	}
+	return "", nil
+}
+
 func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.Product)
 	if !ok {
@@ -165,9 +239,21 @@ func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (ma
 
 	e.logger.Debug("Creating")
 
+	if err := e.validateCustomFields(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
 	converter := &converters.ProductConverterImpl{}
 	req := converter.FromProductSpec(&cr.Spec.ForProvider)
 
+	// mark the product so it can be adopted if its ID is lost
+	if uid := string(cr.GetUID()); e.adopt && uid != "" {
+		if err := metronomeClient.EnsureOwnerCustomFieldKey(ctx, e.keys, metronomeClient.EntityProduct); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errEnsureOwnerKey)
+		}
+		req.CustomFields = metronomeClient.WithOwner(req.CustomFields, uid)
+	}
+
 	res, err := e.metronome.CreateProduct(ctx, *req)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateProduct)
@@ -195,25 +281,65 @@ func (e *metronomeExternal) Update(ctx context.Context, mg resource.Managed) (ma
 		return managed.ExternalUpdate{}, errors.New(errNoID)
 	}
 
-	if cr.Spec.ForProvider.StartingAt == "" {
-		return managed.ExternalUpdate{}, errors.New(errNoStartingAt)
+	if err := e.validateCustomFields(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	res, err := e.metronome.GetProduct(ctx, metronomeClient.GetProductRequest{
+		ID: id,
+	})
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errGetProduct)
 	}
 
 	converter := &converters.ProductConverterImpl{}
-	req := converter.ToProductUpdate(&cr.Spec.ForProvider)
-	req.ProductID = id
+	now := e.clock.Now()
 
-	res, err := e.metronome.UpdateProduct(ctx, *req)
-	if err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateProduct)
+	if upToDate, _ := isUpToDate(cr, &res.Data, now, comparedAt(cr, now)); !upToDate {
+		startingAt, err := updateStartingAt(&cr.Spec.ForProvider, now)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+
+		req := converter.ToProductUpdate(effectiveParameters(&cr.Spec.ForProvider, startingAt))
+		req.ProductID = id
+		req.StartingAt = startingAt.UTC().Format(time.RFC3339)
+
+		res, err := e.metronome.UpdateProduct(ctx, *req)
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateProduct)
+		}
+		if res.Data.ID == "" {
+			return managed.ExternalUpdate{}, errors.New("product ID is missing")
+		}
+		cr.Status.AtProvider.UpdateStartingAt = req.StartingAt
 	}
-	if res.Data.ID == "" {
-		return managed.ExternalUpdate{}, errors.New("product ID is missing")
+
+	for _, entry := range missingScheduleEntries(cr, &res.Data, now) {
+		req := converter.FromProductScheduleEntry(&entry)
+		req.ProductID = id
+
+		if _, err := e.metronome.UpdateProduct(ctx, *req); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrapf(err, errScheduleUpdate, entry.StartingAt)
+		}
+	}
+
+	if err := metronomeClient.SyncCustomFieldValues(ctx, e.customFields, metronomeClient.EntityProduct, id, cr.Spec.ForProvider.CustomFields, res.Data.CustomFields); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFields)
 	}
 
 	return managed.ExternalUpdate{}, nil
 }
 
+// validateCustomFields checks that the custom fields of the spec have
+// CustomFieldKey resources, if validation is enabled.
+func (e *metronomeExternal) validateCustomFields(ctx context.Context, cr *v1alpha1.Product) error {
+	if e.fieldKeys == nil {
+		return nil
+	}
+	return customfieldkey.ValidateKeys(ctx, e.fieldKeys, metronomeClient.EntityProduct, cr.Spec.ForProvider.CustomFields)
+}
+
 func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
 	cr, ok := mg.(*v1alpha1.Product)
 	if !ok {
@@ -231,7 +357,8 @@ func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (ma
 		ProductID: id,
 	})
 	if err != nil {
-		if errors.Is(err, metronomeClient.ErrProductAlreadyArchived) {
+		// the product has already been archived or no longer exists
+		if errors.Is(err, metronomeClient.ErrConflict) || errors.Is(err, metronomeClient.ErrNotFound) {
 			return managed.ExternalDelete{}, nil
 		}
 		return managed.ExternalDelete{}, errors.Wrap(err, errArchiveProduct)
@@ -240,11 +367,83 @@ func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (ma
 	return managed.ExternalDelete{}, nil
 }
 
-func isUpToDate(cr *v1alpha1.Product, metric *metronomeClient.Product) (bool, string) {
-	spec := cr.Spec.ForProvider.DeepCopy()
+// updateStartingAt returns when an update made now to match the spec starts,
+// according to its update policy.
+func updateStartingAt(spec *v1alpha1.ProductParameters, now time.Time) (time.Time, error) {
+	policy := spec.UpdatePolicy
+	if policy == "" {
+		policy = v1alpha1.UpdatePolicyImmediate
+		if spec.StartingAt != "" {
+			policy = v1alpha1.UpdatePolicyExplicit
+		}
+	}
+
+	switch policy {
+	case v1alpha1.UpdatePolicyNextBillingPeriod:
+		now = now.UTC()
+		return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC), nil
+	case v1alpha1.UpdatePolicyExplicit:
+		if spec.StartingAt == "" {
+			return time.Time{}, errors.New(errNoStartingAt)
+		}
+		t, err := time.Parse(time.RFC3339, spec.StartingAt)
+		if err != nil || !t.Truncate(time.Hour).Equal(t) {
+			return time.Time{}, errors.New(errStartingAtHour)
+		}
+		return t, nil
+	default:
+		return now.Truncate(time.Hour).Add(time.Hour), nil
+	}
+}
+
+// comparedAt returns when the product is compared to its spec. Updates start
+// after they are made, so until the last update starts the product is
+// compared as it will be then rather than as it is now.
+func comparedAt(cr *v1alpha1.Product, now time.Time) time.Time {
+	t, err := time.Parse(time.RFC3339, cr.Status.AtProvider.UpdateStartingAt)
+	if err != nil || !t.After(now) {
+		return now
+	}
+	return t
+}
+
+// sameCustomFields reports whether the custom fields of the product, other
+// than the owner marker, match the spec.
+func sameCustomFields(cr *v1alpha1.Product, product *metronomeClient.Product) bool {
+	return maps.Equal(cr.Spec.ForProvider.CustomFields, metronomeClient.WithoutOwner(product.CustomFields))
+}
+
+// isUpToDate reports whether the product matches the spec at the given time,
+// with the schedule entries and the product updates that have started by
+// then applied.
+func isUpToDate(cr *v1alpha1.Product, product *metronomeClient.Product, now, at time.Time) (bool, string) {
+	spec := effectiveParameters(&cr.Spec.ForProvider, at)
 
 	converter := &converters.ProductConverterImpl{}
-	params := converter.FromProductToParameters(metric)
+	params := converter.FromProductToParameters(product)
+
+	// the current details include every update that has started by now
+	updates := slices.Clone(product.Updates)
+	slices.SortStableFunc(updates, func(a, b metronomeClient.ProductDetails) int {
+		return updateTime(a).Compare(updateTime(b))
+	})
+	for _, u := range updates {
+		if t := updateTime(u); t.After(now) && !t.After(at) {
+			applyScheduleEntry(params, *converter.ToProductScheduleEntry(&u))
+		}
+	}
+
+	return equalParameters(spec, params)
+}
+
+func updateTime(u metronomeClient.ProductDetails) time.Time {
+	t, _ := time.Parse(time.RFC3339, u.StartingAt)
+	return t
+}
+
+func equalParameters(spec, params *v1alpha1.ProductParameters) (bool, string) {
+	spec = spec.DeepCopy()
+	params = params.DeepCopy()
 
 	caseInsensitiveComparer := cmp.Comparer(strings.EqualFold)
 
@@ -270,8 +469,109 @@ func isUpToDate(cr *v1alpha1.Product, metric *metronomeClient.Product) (bool, st
 		}, caseInsensitiveComparer),
 		cmpopts.IgnoreFields(v1alpha1.ProductParameters{},
 			"StartingAt",
+			"UpdatePolicy",
+			"Schedule",
+			// custom fields aren't part of the product details, and are
+			// compared separately
+			"CustomFields",
 		),
 	}
 
 	return cmp.Equal(spec, params, opts...), cmp.Diff(spec, params, opts...)
 }
+
+// effectiveParameters returns the parameters of the product at the given
+// time, with the schedule entries that have started by then applied in order.
+func effectiveParameters(in *v1alpha1.ProductParameters, at time.Time) *v1alpha1.ProductParameters {
+	out := in.DeepCopy()
+
+	entries := slices.Clone(out.Schedule)
+	slices.SortStableFunc(entries, func(a, b v1alpha1.ProductScheduleEntry) int {
+		return scheduleTime(a).Compare(scheduleTime(b))
+	})
+	for _, entry := range entries {
+		if started(entry, at) {
+			applyScheduleEntry(out, entry)
+		}
+	}
+	return out
+}
+
+// missingScheduleEntries returns the schedule entries that haven't started by
+// now and haven't been submitted to Metronome as product updates.
+func missingScheduleEntries(cr *v1alpha1.Product, product *metronomeClient.Product, now time.Time) []v1alpha1.ProductScheduleEntry {
+	converter := &converters.ProductConverterImpl{}
+
+	var missing []v1alpha1.ProductScheduleEntry
+	for _, entry := range cr.Spec.ForProvider.Schedule {
+		if started(entry, now) {
+			continue
+		}
+		submitted := slices.ContainsFunc(product.Updates, func(u metronomeClient.ProductDetails) bool {
+			observed := converter.ToProductScheduleEntry(&u)
+			if !scheduleTime(entry).Equal(scheduleTime(*observed)) {
+				return false
+			}
+
+			// the update matches if applying the entry to it changes nothing
+			before := &v1alpha1.ProductParameters{}
+			applyScheduleEntry(before, *observed)
+			after := before.DeepCopy()
+			applyScheduleEntry(after, entry)
+			equal, _ := equalParameters(after, before)
+			return equal
+		})
+		if !submitted {
+			missing = append(missing, entry)
+		}
+	}
+	return missing
+}
+
+// started reports whether the schedule entry has started by the given time.
+// An entry with an invalid starting time never starts, so Metronome rejects
+// it when it is submitted.
+func started(entry v1alpha1.ProductScheduleEntry, at time.Time) bool {
+	t := scheduleTime(entry)
+	return !t.IsZero() && !t.After(at)
+}
+
+func scheduleTime(entry v1alpha1.ProductScheduleEntry) time.Time {
+	t, _ := time.Parse(time.RFC3339, entry.StartingAt)
+	return t
+}
+
+// applyScheduleEntry applies the fields set in the schedule entry to the
+// parameters, the same way Metronome applies a product update.
+func applyScheduleEntry(p *v1alpha1.ProductParameters, entry v1alpha1.ProductScheduleEntry) {
+	if entry.BillableMetricID != "" {
+		p.BillableMetricID = entry.BillableMetricID
+	}
+	if entry.Name != "" {
+		p.Name = entry.Name
+	}
+	if entry.CompositeProductIDs != nil {
+		p.CompositeProductIDs = entry.CompositeProductIDs
+	}
+	if entry.CompositeTags != nil {
+		p.CompositeTags = entry.CompositeTags
+	}
+	if entry.ExcludeFreeUsage {
+		p.ExcludeFreeUsage = true
+	}
+	if entry.PresentationGroupKey != nil {
+		p.PresentationGroupKey = entry.PresentationGroupKey
+	}
+	if entry.PricingGroupKey != nil {
+		p.PricingGroupKey = entry.PricingGroupKey
+	}
+	if entry.QuantityConversion != nil {
+		p.QuantityConversion = entry.QuantityConversion
+	}
+	if entry.QuantityRounding != nil {
+		p.QuantityRounding = entry.QuantityRounding
+	}
+	if entry.Tags != nil {
+		p.Tags = entry.Tags
+	}
+}
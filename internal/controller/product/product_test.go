@@ -2,12 +2,16 @@ package product
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -16,8 +20,10 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	cfkv1alpha1 "github.com/redbackthomson/provider-metronome/apis/customfieldkey/v1alpha1"
 	"github.com/redbackthomson/provider-metronome/apis/product/v1alpha1"
 	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/clients/metronome/metronometest"
 )
 
 const (
@@ -92,8 +98,34 @@ func (m *MockProductClient) UpdateProduct(ctx context.Context, reqData metronome
 
 var _ (metronomeClient.ProductClient) = (*MockProductClient)(nil)
 
+type MockCustomFieldClient struct {
+	SetCustomFieldValuesFn    func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error
+	DeleteCustomFieldValuesFn func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldValuesRequest) error
+	ListCustomFieldValuesFn   func(ctx context.Context, entity, key string) (map[string]string, error)
+}
+
+// SetCustomFieldValues implements metronome.CustomFieldClient.
+func (m *MockCustomFieldClient) SetCustomFieldValues(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+	return m.SetCustomFieldValuesFn(ctx, reqData)
+}
+
+// DeleteCustomFieldValues implements metronome.CustomFieldClient.
+func (m *MockCustomFieldClient) DeleteCustomFieldValues(ctx context.Context, reqData metronomeClient.DeleteCustomFieldValuesRequest) error {
+	return m.DeleteCustomFieldValuesFn(ctx, reqData)
+}
+
+// ListCustomFieldValues implements metronome.CustomFieldClient.
+func (m *MockCustomFieldClient) ListCustomFieldValues(ctx context.Context, entity, key string) (map[string]string, error) {
+	return m.ListCustomFieldValuesFn(ctx, entity, key)
+}
+
+var _ (metronomeClient.CustomFieldClient) = (*MockCustomFieldClient)(nil)
+
 func Test_External_Observe(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
 	type args struct {
+		adopt     bool
 		metronome metronomeClient.ProductClient
 		mg        resource.Managed
 	}
@@ -126,6 +158,19 @@ func Test_External_Observe(t *testing.T) {
 				err: errors.Wrap(errBoom, errGetProduct),
 			},
 		},
+		"NotFoundIsDeleted": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						return nil, errors.Wrap(&metronomeClient.APIError{StatusCode: http.StatusNotFound}, "failed to get product")
+					},
+				},
+				mg: product(),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
 		"ArchivedIsDeleted": {
 			args: args{
 				metronome: &MockProductClient{
@@ -143,6 +188,99 @@ func Test_External_Observe(t *testing.T) {
 				out: managed.ExternalObservation{ResourceExists: false},
 			},
 		},
+		"AdoptionDisabled": {
+			args: args{
+				metronome: &MockProductClient{
+					ListProductFn: func(ctx context.Context, reqData metronomeClient.ListProductsRequest, nextPage string) (*metronomeClient.ListProductsResponse, error) {
+						t.Errorf("ListProduct(...): want no lookup")
+						return nil, errBoom
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"CreateNotAttempted": {
+			args: args{
+				adopt: true,
+				metronome: &MockProductClient{
+					ListProductFn: func(ctx context.Context, reqData metronomeClient.ListProductsRequest, nextPage string) (*metronomeClient.ListProductsResponse, error) {
+						t.Errorf("ListProduct(...): want no lookup")
+						return nil, errBoom
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToFindOwnedProduct": {
+			args: args{
+				adopt: true,
+				metronome: &MockProductClient{
+					ListProductFn: func(ctx context.Context, reqData metronomeClient.ListProductsRequest, nextPage string) (*metronomeClient.ListProductsResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errAdoptProduct),
+			},
+		},
+		"AdoptsOwnedProduct": {
+			args: args{
+				adopt: true,
+				metronome: &MockProductClient{
+					ListProductFn: func(ctx context.Context, reqData metronomeClient.ListProductsRequest, nextPage string) (*metronomeClient.ListProductsResponse, error) {
+						return &metronomeClient.ListProductsResponse{
+							Data: []metronomeClient.Product{
+								{ID: "id1", Current: metronomeClient.ProductDetails{Name: "name"}},
+								{ID: "id2", Current: metronomeClient.ProductDetails{Name: "name"}, CustomFields: map[string]string{metronomeClient.OwnerCustomFieldKey: "uid"}},
+							},
+						}, nil
+					},
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						if reqData.ID != "id2" {
+							return nil, errBoom
+						}
+						return &metronomeClient.GetProductResponse{
+							Data: metronomeClient.Product{
+								ID:      "id2",
+								Type:    "USAGE",
+								Current: metronomeClient.ProductDetails{Name: "name"},
+							},
+						}, nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name: "name",
+					}
+					mg.Spec.ForProvider.Type = "usage"
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
 		"NotUpToDate": {
 			args: args{
 				metronome: &MockProductClient{
@@ -191,12 +329,167 @@ func Test_External_Observe(t *testing.T) {
 				err: nil,
 			},
 		},
+		"StartedScheduleEntryIsDesired": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						return &metronomeClient.GetProductResponse{
+							Data: metronomeClient.Product{
+								ID:      "id1",
+								Type:    "USAGE",
+								Current: metronomeClient.ProductDetails{Name: "new-name"},
+								Updates: []metronomeClient.ProductDetails{{Name: "new-name", StartingAt: "2025-02-01T00:00:00Z"}},
+							},
+						}, nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name: "name",
+						Type: "usage",
+						Schedule: []v1alpha1.ProductScheduleEntry{{
+							StartingAt: "2025-02-01T00:00:00Z",
+							Name:       "new-name",
+						}},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				err: nil,
+			},
+		},
+		"FutureScheduleEntryMissing": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						return &metronomeClient.GetProductResponse{
+							Data: metronomeClient.Product{
+								ID:      "id1",
+								Type:    "USAGE",
+								Current: metronomeClient.ProductDetails{Name: "name"},
+								Updates: []metronomeClient.ProductDetails{{Name: "other-name", StartingAt: "2025-04-01T00:00:00Z"}},
+							},
+						}, nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name: "name",
+						Type: "usage",
+						Schedule: []v1alpha1.ProductScheduleEntry{{
+							StartingAt: "2025-04-01T00:00:00Z",
+							Name:       "new-name",
+						}},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
+			},
+		},
+		"FutureScheduleEntrySubmitted": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						return &metronomeClient.GetProductResponse{
+							Data: metronomeClient.Product{
+								ID:      "id1",
+								Type:    "USAGE",
+								Current: metronomeClient.ProductDetails{Name: "name"},
+								Updates: []metronomeClient.ProductDetails{{
+									StartingAt:      "2025-04-01T00:00:00.000Z",
+									PricingGroupKey: []string{"zone", "region"},
+								}},
+							},
+						}, nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name: "name",
+						Type: "usage",
+						Schedule: []v1alpha1.ProductScheduleEntry{{
+							StartingAt:      "2025-04-01T00:00:00Z",
+							PricingGroupKey: []string{"region", "zone"},
+						}},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				err: nil,
+			},
+		},
+		"CustomFieldsNotUpToDate": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						return &metronomeClient.GetProductResponse{
+							Data: metronomeClient.Product{
+								ID:      "id1",
+								Type:    "USAGE",
+								Current: metronomeClient.ProductDetails{Name: "name"},
+								CustomFields: map[string]string{
+									metronomeClient.OwnerCustomFieldKey: "uid",
+									"team":                              "billing",
+								},
+							},
+						}, nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name:         "name",
+						Type:         "usage",
+						CustomFields: map[string]string{"team": "finance"},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
+			},
+		},
+		"CustomFieldsUpToDateIgnoringOwner": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						return &metronomeClient.GetProductResponse{
+							Data: metronomeClient.Product{
+								ID:      "id1",
+								Type:    "USAGE",
+								Current: metronomeClient.ProductDetails{Name: "name"},
+								CustomFields: map[string]string{
+									metronomeClient.OwnerCustomFieldKey: "uid",
+									"team":                              "finance",
+								},
+							},
+						}, nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name:         "name",
+						Type:         "usage",
+						CustomFields: map[string]string{"team": "finance"},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				err: nil,
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			e := &metronomeExternal{
+				adopt:     tc.args.adopt,
 				logger:    logging.NewNopLogger(),
 				metronome: tc.args.metronome,
+				clock:     clocktesting.NewFakePassiveClock(now),
 			}
 
 			ignoreDiff := cmpopts.IgnoreFields(managed.ExternalObservation{}, "Diff")
@@ -274,6 +567,29 @@ func Test_External_Create(t *testing.T) {
 				err: nil,
 			},
 		},
+		"NotMarkedUnlessAdopting": {
+			args: args{
+				metronome: &MockProductClient{
+					CreateProductFn: func(ctx context.Context, reqData metronomeClient.CreateProductRequest) (*metronomeClient.CreateProductResponse, error) {
+						if _, ok := reqData.CustomFields[metronomeClient.OwnerCustomFieldKey]; ok {
+							t.Errorf("CreateProduct(...): want no owner marker, got custom fields %v", reqData.CustomFields)
+						}
+						return &metronomeClient.CreateProductResponse{
+							Data: metronomeClient.IDOnly{ID: "id"},
+						}, nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.SetUID("uid")
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name: "name",
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalCreation{},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -293,6 +609,299 @@ func Test_External_Create(t *testing.T) {
 	}
 }
 
+func Test_External_Update(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	current := func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+		return &metronomeClient.GetProductResponse{
+			Data: metronomeClient.Product{
+				ID:      "id1",
+				Type:    "USAGE",
+				Current: metronomeClient.ProductDetails{Name: "name"},
+				Updates: []metronomeClient.ProductDetails{{Name: "submitted", StartingAt: "2025-04-01T00:00:00Z"}},
+			},
+		}, nil
+	}
+
+	type args struct {
+		metronome    metronomeClient.ProductClient
+		customFields metronomeClient.CustomFieldClient
+		fieldKeys    client.Reader
+		mg           resource.Managed
+	}
+	type want struct {
+		out              managed.ExternalUpdate
+		err              error
+		updateStartingAt string
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotProductResource": {
+			args: args{
+				mg: notProductResource{},
+			},
+			want: want{
+				err: errors.New(errNotProduct),
+			},
+		},
+		"FailedToGetProduct": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: func(ctx context.Context, reqData metronomeClient.GetProductRequest) (*metronomeClient.GetProductResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: product(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetProduct),
+			},
+		},
+		"ExplicitWithoutStartingAt": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: current,
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{Name: "other-name", Type: "usage", UpdatePolicy: v1alpha1.UpdatePolicyExplicit}
+				}),
+			},
+			want: want{
+				err: errors.New(errNoStartingAt),
+			},
+		},
+		"StartingAtNotOnHour": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: current,
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{Name: "other-name", Type: "usage", StartingAt: "2025-03-01T12:30:00Z"}
+				}),
+			},
+			want: want{
+				err: errors.New(errStartingAtHour),
+			},
+		},
+		"ImmediateStartsAtNextHour": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: current,
+					UpdateProductFn: func(ctx context.Context, reqData metronomeClient.UpdateProductRequest) (*metronomeClient.UpdateProductResponse, error) {
+						if reqData.StartingAt != "2025-03-01T13:00:00Z" || reqData.Name != "other-name" {
+							t.Errorf("UpdateProductRequest mismatched: got %+v", reqData)
+						}
+						return &metronomeClient.UpdateProductResponse{Data: metronomeClient.IDOnly{ID: "id1"}}, nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{Name: "other-name", Type: "usage"}
+				}),
+			},
+			want: want{
+				out:              managed.ExternalUpdate{},
+				updateStartingAt: "2025-03-01T13:00:00Z",
+			},
+		},
+		"NextBillingPeriod": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: current,
+					UpdateProductFn: func(ctx context.Context, reqData metronomeClient.UpdateProductRequest) (*metronomeClient.UpdateProductResponse, error) {
+						if reqData.StartingAt != "2025-04-01T00:00:00Z" {
+							t.Errorf("UpdateProductRequest mismatched: want starting at %q, got %q", "2025-04-01T00:00:00Z", reqData.StartingAt)
+						}
+						return &metronomeClient.UpdateProductResponse{Data: metronomeClient.IDOnly{ID: "id1"}}, nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{Name: "other-name", Type: "usage", UpdatePolicy: v1alpha1.UpdatePolicyNextBillingPeriod}
+				}),
+			},
+			want: want{
+				out:              managed.ExternalUpdate{},
+				updateStartingAt: "2025-04-01T00:00:00Z",
+			},
+		},
+		"FailedToScheduleUpdate": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: current,
+					UpdateProductFn: func(ctx context.Context, reqData metronomeClient.UpdateProductRequest) (*metronomeClient.UpdateProductResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name: "name",
+						Type: "usage",
+						Schedule: []v1alpha1.ProductScheduleEntry{{
+							StartingAt: "2025-05-01T00:00:00Z",
+							Name:       "later",
+						}},
+					}
+				}),
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errScheduleUpdate, "2025-05-01T00:00:00Z"),
+			},
+		},
+		"SubmitsOnlyMissingFutureEntries": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: current,
+					UpdateProductFn: func(ctx context.Context, reqData metronomeClient.UpdateProductRequest) (*metronomeClient.UpdateProductResponse, error) {
+						want := metronomeClient.UpdateProductRequest{
+							ProductID:  "id1",
+							StartingAt: "2025-05-01T00:00:00Z",
+							Name:       "later",
+						}
+						if diff := cmp.Diff(want, reqData); diff != "" {
+							t.Errorf("UpdateProductRequest mismatched: -want req, +got req: %s", diff)
+						}
+						return &metronomeClient.UpdateProductResponse{}, nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					meta.SetExternalName(mg, "id1")
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name: "original-name",
+						Type: "usage",
+						Schedule: []v1alpha1.ProductScheduleEntry{{
+							StartingAt: "2025-01-01T00:00:00Z",
+							Name:       "name",
+						}, {
+							StartingAt: "2025-04-01T00:00:00Z",
+							Name:       "submitted",
+						}, {
+							StartingAt: "2025-05-01T00:00:00Z",
+							Name:       "later",
+						}},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalUpdate{},
+			},
+		},
+		"MissingCustomFieldKey": {
+			args: args{
+				fieldKeys: &test.MockClient{
+					MockList: func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						l := obj.(*cfkv1alpha1.CustomFieldKeyList)
+						l.Items = []cfkv1alpha1.CustomFieldKey{{
+							Spec: cfkv1alpha1.CustomFieldKeySpec{
+								ForProvider: cfkv1alpha1.CustomFieldKeyParameters{Entity: metronomeClient.EntityCustomer, Key: "team"},
+							},
+						}}
+						return nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name:         "name",
+						Type:         "usage",
+						CustomFields: map[string]string{"team": "finance"},
+					}
+				}),
+			},
+			want: want{
+				err: errors.Errorf("no CustomFieldKey resource defines the %s custom field keys: %s", metronomeClient.EntityProduct, "team"),
+			},
+		},
+		"FailedToUpdateCustomFields": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: current,
+				},
+				customFields: &MockCustomFieldClient{
+					SetCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+						return errBoom
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name:         "name",
+						Type:         "usage",
+						CustomFields: map[string]string{"team": "finance"},
+					}
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateFields),
+			},
+		},
+		"SyncsOnlyCustomFields": {
+			args: args{
+				metronome: &MockProductClient{
+					GetProductFn: current,
+				},
+				customFields: &MockCustomFieldClient{
+					SetCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+						want := metronomeClient.SetCustomFieldValuesRequest{
+							Entity:       metronomeClient.EntityProduct,
+							EntityID:     "external-name",
+							CustomFields: map[string]string{"team": "finance"},
+						}
+						if diff := cmp.Diff(want, reqData); diff != "" {
+							t.Errorf("SetCustomFieldValuesRequest mismatched: -want req, +got req: %s", diff)
+						}
+						return nil
+					},
+				},
+				fieldKeys: &test.MockClient{
+					MockList: func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						l := obj.(*cfkv1alpha1.CustomFieldKeyList)
+						l.Items = []cfkv1alpha1.CustomFieldKey{{
+							Spec: cfkv1alpha1.CustomFieldKeySpec{
+								ForProvider: cfkv1alpha1.CustomFieldKeyParameters{Entity: metronomeClient.EntityProduct, Key: "team"},
+							},
+						}}
+						return nil
+					},
+				},
+				mg: product(func(mg *v1alpha1.Product) {
+					mg.Spec.ForProvider = v1alpha1.ProductParameters{
+						Name:         "name",
+						Type:         "usage",
+						CustomFields: map[string]string{"team": "finance"},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalUpdate{},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:       logging.NewNopLogger(),
+				metronome:    tc.args.metronome,
+				customFields: tc.args.customFields,
+				fieldKeys:    tc.args.fieldKeys,
+				clock:        clocktesting.NewFakePassiveClock(now),
+			}
+			got, gotErr := e.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Update(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Fatalf("e.Update(...): -want out, +got out: %s", diff)
+			}
+
+			if cr, ok := tc.args.mg.(*v1alpha1.Product); ok {
+				if got := cr.Status.AtProvider.UpdateStartingAt; got != tc.want.updateStartingAt {
+					t.Errorf("e.Update(...): want update starting at %q, got %q", tc.want.updateStartingAt, got)
+				}
+			}
+		})
+	}
+}
+
 func Test_External_Delete(t *testing.T) {
 	type args struct {
 		metronome metronomeClient.ProductClient
@@ -314,6 +923,19 @@ func Test_External_Delete(t *testing.T) {
 				err: errors.New(errNotProduct),
 			},
 		},
+		"AlreadyArchived": {
+			args: args{
+				metronome: &MockProductClient{
+					ArchiveProductFn: func(ctx context.Context, reqData metronomeClient.ArchiveProductRequest) (*metronomeClient.ArchiveProductResponse, error) {
+						return nil, errors.Wrap(&metronomeClient.APIError{StatusCode: http.StatusConflict}, "failed to archive product")
+					},
+				},
+				mg: product(),
+			},
+			want: want{
+				out: managed.ExternalDelete{},
+			},
+		},
 		"FailedToDeleteProduct": {
 			args: args{
 				metronome: &MockProductClient{
@@ -368,3 +990,287 @@ func Test_External_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_MetronomeExternal_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	clk := clocktesting.NewFakePassiveClock(now)
+
+	srv := metronometest.NewServer(metronometest.WithClock(clk))
+	defer srv.Close()
+
+	e := &metronomeExternal{
+		logger:    logging.NewNopLogger(),
+		metronome: srv.NewClient().Product(),
+		clock:     clk,
+	}
+
+	cr := product(func(p *v1alpha1.Product) {
+		meta.SetExternalName(p, "")
+		p.Spec.ForProvider.Name = "seats"
+		p.Spec.ForProvider.Type = "subscription"
+	})
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if meta.GetExternalName(cr) == "" {
+		t.Fatal("Create(...): external name not set")
+	}
+
+	o, err := e.Observe(ctx, cr)
+	if err != nil {
+		t.Fatalf("Observe(...): %v", err)
+	}
+	if !o.ResourceExists || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and up to date, got %+v", o)
+	}
+
+	cr.Spec.ForProvider.Tags = []string{"team-a"}
+	cr.Spec.ForProvider.StartingAt = now.Format(time.RFC3339)
+	if o, _ := e.Observe(ctx, cr); o.ResourceUpToDate {
+		t.Fatal("Observe(...): want out of date after changing tags")
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if o, _ := e.Observe(ctx, cr); !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date after update, got diff %s", o.Diff)
+	}
+
+	if _, err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete(...): %v", err)
+	}
+	if o, _ := e.Observe(ctx, cr); o.ResourceExists {
+		t.Fatal("Observe(...): want not existing after delete")
+	}
+	if _, err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete(...): want archived product to be treated as deleted, got %v", err)
+	}
+}
+
+func Test_MetronomeExternal_Schedule(t *testing.T) {
+	ctx := context.Background()
+	clk := clocktesting.NewFakePassiveClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	srv := metronometest.NewServer(metronometest.WithClock(clk))
+	defer srv.Close()
+
+	e := &metronomeExternal{
+		logger:    logging.NewNopLogger(),
+		metronome: srv.NewClient().Product(),
+		clock:     clk,
+	}
+
+	cr := product(func(p *v1alpha1.Product) {
+		meta.SetExternalName(p, "")
+		p.Spec.ForProvider.Name = "seats"
+		p.Spec.ForProvider.Type = "subscription"
+		p.Spec.ForProvider.Schedule = []v1alpha1.ProductScheduleEntry{{
+			StartingAt: "2025-04-01T00:00:00Z",
+			Name:       "seats (v2)",
+		}, {
+			StartingAt:      "2025-05-01T00:00:00Z",
+			PricingGroupKey: []string{"region"},
+		}}
+	})
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want out of date until the schedule is submitted, got %+v, %v", o, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date once the schedule is submitted, got %+v, %v", o, err)
+	}
+	if got := len(cr.Status.AtProvider.Updates); got != 2 {
+		t.Fatalf("Observe(...): want 2 updates, got %d", got)
+	}
+
+	// submitting the schedule again doesn't duplicate it
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if _, err := e.Observe(ctx, cr); err != nil {
+		t.Fatalf("Observe(...): %v", err)
+	}
+	if got := len(cr.Status.AtProvider.Updates); got != 2 {
+		t.Fatalf("Observe(...): want 2 updates after updating again, got %d", got)
+	}
+
+	// once an entry starts, it is part of the desired state of the product
+	clk.SetTime(time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC))
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date after the first entry starts, got %+v, %v", o, err)
+	}
+	if got := cr.Status.AtProvider.Current.Name; got != "seats (v2)" {
+		t.Errorf("Observe(...): want current name %q, got %q", "seats (v2)", got)
+	}
+}
+
+func Test_MetronomeExternal_PendingUpdate(t *testing.T) {
+	ctx := context.Background()
+	clk := clocktesting.NewFakePassiveClock(time.Date(2025, 3, 1, 12, 20, 0, 0, time.UTC))
+
+	srv := metronometest.NewServer(metronometest.WithClock(clk))
+	defer srv.Close()
+
+	e := &metronomeExternal{
+		logger:    logging.NewNopLogger(),
+		metronome: srv.NewClient().Product(),
+		clock:     clk,
+	}
+
+	cr := product(func(p *v1alpha1.Product) {
+		meta.SetExternalName(p, "")
+		p.Spec.ForProvider.Name = "seats"
+		p.Spec.ForProvider.Type = "subscription"
+	})
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+
+	cr.Spec.ForProvider.Tags = []string{"team-a"}
+	if o, err := e.Observe(ctx, cr); err != nil || o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want out of date after changing tags, got %+v, %v", o, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if got, want := cr.Status.AtProvider.UpdateStartingAt, "2025-03-01T13:00:00Z"; got != want {
+		t.Errorf("Update(...): want update starting at %q, got %q", want, got)
+	}
+
+	// the update hasn't started, but is not made again
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date while the update is pending, got %+v, %v", o, err)
+	}
+	if got := cr.Status.AtProvider.Current.Tags; len(got) != 0 {
+		t.Errorf("Observe(...): want no current tags before the update starts, got %v", got)
+	}
+
+	clk.SetTime(time.Date(2025, 3, 1, 13, 5, 0, 0, time.UTC))
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date after the update starts, got %+v, %v", o, err)
+	}
+	if diff := cmp.Diff([]string{"team-a"}, cr.Status.AtProvider.Current.Tags); diff != "" {
+		t.Errorf("Observe(...): -want current tags, +got current tags: %s", diff)
+	}
+	if got := len(cr.Status.AtProvider.Updates); got != 1 {
+		t.Errorf("Observe(...): want 1 update, got %d", got)
+	}
+}
+
+func Test_MetronomeExternal_AdoptsLostProduct(t *testing.T) {
+	ctx := context.Background()
+
+	srv := metronometest.NewServer()
+	defer srv.Close()
+
+	client := srv.NewClient()
+	e := &metronomeExternal{
+		logger:    logging.NewNopLogger(),
+		metronome: client.Product(),
+		keys:      client.CustomFieldKey(),
+		clock:     clocktesting.NewFakePassiveClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)),
+		adopt:     true,
+	}
+
+	cr := product(func(p *v1alpha1.Product) {
+		meta.SetExternalName(p, "")
+		meta.SetExternalCreatePending(p, time.Now())
+		p.SetUID("2d7b3a4e-0c1f-4c57-9a39-2f1e7b0f3c11")
+		p.Spec.ForProvider.Name = "seats"
+		p.Spec.ForProvider.Type = "subscription"
+	})
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	id := meta.GetExternalName(cr)
+
+	// simulate the provider crashing before the external name was persisted
+	meta.SetExternalName(cr, "")
+
+	o, err := e.Observe(ctx, cr)
+	if err != nil {
+		t.Fatalf("Observe(...): %v", err)
+	}
+	if !o.ResourceExists || !o.ResourceLateInitialized {
+		t.Fatalf("Observe(...): want existing product to be adopted, got %+v", o)
+	}
+	if got := meta.GetExternalName(cr); got != id {
+		t.Fatalf("Observe(...): want external name %q, got %q", id, got)
+	}
+	if !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date, got diff %s", o.Diff)
+	}
+
+	other := product(func(p *v1alpha1.Product) {
+		meta.SetExternalName(p, "")
+		meta.SetExternalCreatePending(p, time.Now())
+		p.SetUID("0b4e5f6a-7c8d-4e9f-8a1b-2c3d4e5f6a7b")
+		p.Spec.ForProvider.Name = "seats"
+		p.Spec.ForProvider.Type = "subscription"
+	})
+	if o, err := e.Observe(ctx, other); err != nil || o.ResourceExists {
+		t.Fatalf("Observe(...): want product owned by another resource to be ignored, got %+v, %v", o, err)
+	}
+}
+
+func Test_MetronomeExternal_CustomFields(t *testing.T) {
+	ctx := context.Background()
+
+	srv := metronometest.NewServer()
+	defer srv.Close()
+
+	client := srv.NewClient()
+	e := &metronomeExternal{
+		logger:       logging.NewNopLogger(),
+		metronome:    client.Product(),
+		customFields: client.CustomField(),
+		clock:        clocktesting.NewFakePassiveClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)),
+	}
+
+	for _, key := range []string{"team", "cost-center"} {
+		if err := client.CustomFieldKey().CreateCustomFieldKey(ctx, metronomeClient.CreateCustomFieldKeyRequest{
+			Entity: metronomeClient.EntityProduct,
+			Key:    key,
+		}); err != nil {
+			t.Fatalf("CreateCustomFieldKey(...): %v", err)
+		}
+	}
+
+	cr := product(func(p *v1alpha1.Product) {
+		meta.SetExternalName(p, "")
+		p.Spec.ForProvider.Name = "seats"
+		p.Spec.ForProvider.Type = "subscription"
+		p.Spec.ForProvider.CustomFields = map[string]string{"team": "billing", "cost-center": "42"}
+	})
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date after create, got %+v, %v", o, err)
+	}
+
+	cr.Spec.ForProvider.CustomFields = map[string]string{"team": "finance"}
+	if o, _ := e.Observe(ctx, cr); o.ResourceUpToDate {
+		t.Fatal("Observe(...): want out of date after changing custom fields")
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want up to date after update, got %+v, %v", o, err)
+	}
+	if diff := cmp.Diff(map[string]string{"team": "finance"}, cr.Status.AtProvider.CustomFields); diff != "" {
+		t.Errorf("Observe(...): -want custom fields, +got custom fields: %s", diff)
+	}
+}
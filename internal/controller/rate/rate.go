@@ -18,12 +18,14 @@ package rate
 
 import (
 	"context"
-	"math"
+	"maps"
 	"slices"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -31,6 +33,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
@@ -48,10 +51,17 @@ const (
 	errGetRate            = "failed to get rate"
 	errCreateRate         = "failed to create rate"
 	errArchiveRate        = "failed to archive rate"
+	errEndRate            = "failed to end the current version of the rate"
+	errRateGone           = "rate no longer exists"
+	errParseAnnotation    = "cannot parse the %s annotation as an RFC 3339 timestamp on an hour boundary"
+
+	// maxVersions bounds the number of versions of a rate followed when
+	// looking for its current version.
+	maxVersions = 100
 )
 
 // Setup adds a controller that reconciles Rate managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
+func Setup(mgr ctrl.Manager, o controller.Options, co connector.Options) error {
 	name := managed.ControllerName(v1alpha1.RateGroupKind)
 
 	reconcilerOptions := []managed.ReconcilerOption{
@@ -60,12 +70,18 @@ func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
 				Logger:               o.Logger,
 				Client:               mgr.GetClient(),
 				Usage:                resource.NewProviderConfigUsageTracker(mgr.GetClient(), &metronomev1alpha1.ProviderConfigUsage{}),
-				BaseURL:              baseUrl,
+				BaseURL:              co.BaseURL,
+				RateLimiters:         co.RateLimiters,
+				Metrics:              co.Metrics,
+				TracerProvider:       co.TracerProvider,
+				HTTPClients:          co.HTTPClients,
+				Clients:              co.Clients,
 				NewMetronomeClientFn: metronomeClient.New,
 				NewExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *metronomeExternal {
 					return &metronomeExternal{
 						logger:    o.Logger,
 						metronome: client.Rate(),
+						clock:     clock.RealClock{},
 					}
 				},
 			}),
@@ -99,6 +115,7 @@ func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
 type metronomeExternal struct {
 	logger    logging.Logger
 	metronome metronomeClient.RateClient
+	clock     clock.PassiveClock
 }
 
 func (e *metronomeExternal) Disconnect(ctx context.Context) error {
@@ -106,11 +123,12 @@ func (e *metronomeExternal) Disconnect(ctx context.Context) error {
 }
 
 // Observe checks to see if the resource already exists. Metronome doesn't give
-// rates a unique ID, so the only way to know if we've already created one is to
-// search through all of the existing rates (with a bit of server-side
-// filtering) and compare the spec against each of them. If our spec matches an
-// existing rate, then we will assume we created it, otherwise we assume it
-// doesn't exist.
+// rates a unique ID, so the rate is found by its product and pricing group
+// values at the time the spec starts. Each change to the spec ends the
+// current version of the rate and adds a new one starting where it ends, so
+// the versions are followed until the current one is found. The resource is
+// up to date if the current version matches the spec, so a rate that ended
+// earlier than the spec says is resumed by Update.
 func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
 	cr, ok := mg.(*v1alpha1.Rate)
 	if !ok {
@@ -119,65 +137,128 @@ func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (m
 
 	e.logger.Debug("Observing")
 
-	// no such thing as "deleting" a rate, so don't block on observe
-	if cr.DeletionTimestamp != nil {
+	versions, err := e.versions(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetRate)
+	}
+	if len(versions) == 0 {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
+	current := &versions[len(versions)-1]
 
-	var foundRate *metronomeClient.Rate
-	nextPage := ""
-	for {
-		res, err := e.metronome.GetRates(ctx, metronomeClient.GetRatesRequest{
-			RateCardID: cr.Spec.ForProvider.RateCardID,
-			At:         cr.Spec.ForProvider.StartingAt,
-			Selectors: []metronomeClient.RateSelector{{
-				PricingGroupValues: cr.Spec.ForProvider.PricingGroupValues,
-				ProductID:          cr.Spec.ForProvider.ProductID,
-			}},
-		}, nextPage)
-		if err != nil {
-			// the external name isn't valid
-			return managed.ExternalObservation{}, errors.Wrap(err, errGetRate)
-		}
+	// a rate can't be removed, only ended, so it is gone once the end Delete
+	// gave it has passed
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: !endsBy(*current, e.clock.Now())}, nil
+	}
 
-		if res == nil {
-			return managed.ExternalObservation{ResourceExists: false}, nil
-		}
+	upToDate := e.isUpToDate(cr, current)
 
-		found := false
-		for _, r := range res.Data {
-			if e.isUpToDate(cr, &r) {
-				foundRate = &r
-				found = true
-			}
+	isLateInitialized := false
+	if upToDate {
+		before := cr.Spec.ForProvider.DeepCopy()
+		lateInitialize(&cr.Spec.ForProvider, current)
+		isLateInitialized = !cmp.Equal(before, &cr.Spec.ForProvider)
+	}
+
+	converter := &converters.RateConverterImpl{}
+	cr.Status.AtProvider = *converter.FromRate(current)
+	for _, v := range versions[:len(versions)-1] {
+		cr.Status.AtProvider.Superseded = append(cr.Status.AtProvider.Superseded, converter.FromRateToSuperseded(v))
+	}
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: isLateInitialized,
+	}, nil
+}
+
+// versions returns the versions of the rate, oldest first, starting with the
+// one in effect when the spec starts.
+func (e *metronomeExternal) versions(ctx context.Context, cr *v1alpha1.Rate) ([]metronomeClient.Rate, error) {
+	var versions []metronomeClient.Rate
+	at := cr.Spec.ForProvider.StartingAt
+	for range maxVersions {
+		r, err := e.rateAt(ctx, cr, at)
+		if err != nil {
+			return nil, err
+		}
+		if r == nil {
+			break
 		}
-		if found {
+		if n := len(versions); n > 0 && metronomeClient.SameTime(versions[n-1].StartingAt, r.StartingAt) {
 			break
 		}
+		versions = append(versions, *r)
 
-		nextPage = res.NextPage
-		if nextPage == "" {
+		// a rate that ends when the spec says it should has no successor
+		if r.EndingBefore == "" || metronomeClient.SameTime(r.EndingBefore, cr.Spec.ForProvider.EndingBefore) {
+			break
+		}
+		next, err := e.successorAt(ctx, cr, *r)
+		if err != nil {
+			return nil, err
+		}
+		if next == "" {
 			break
 		}
+		at = next
 	}
+	return versions, nil
+}
 
-	if foundRate == nil {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+// successorAt returns when to look for the version following the rate, or an
+// empty string if there is none. It usually starts where the rate ends, but
+// a rate that ended earlier than the spec says is resumed by a version
+// starting when Update scheduled it.
+func (e *metronomeExternal) successorAt(ctx context.Context, cr *v1alpha1.Rate, r metronomeClient.Rate) (string, error) {
+	next, err := e.rateAt(ctx, cr, r.EndingBefore)
+	if err != nil || next != nil {
+		return r.EndingBefore, err
 	}
+	if !endsBy(r, e.clock.Now()) {
+		return "", nil
+	}
+	// an invalid change-at annotation is reported by Update
+	if resumeAt, err := e.scheduledAt(cr, v1alpha1.AnnotationKeyChangeAt, r); err == nil {
+		return resumeAt.UTC().Format(time.RFC3339), nil
+	}
+	return "", nil
+}
 
-	current := cr.Spec.ForProvider.DeepCopy()
-	lateInitialize(&cr.Spec.ForProvider, foundRate)
-	isLateInitialized := !cmp.Equal(current, &cr.Spec.ForProvider)
-
-	converter := &converters.RateConverterImpl{}
-	cr.Status.AtProvider = *converter.FromRate(foundRate)
-	cr.SetConditions(xpv1.Available())
-
-	return managed.ExternalObservation{
-		ResourceExists:          true,
-		ResourceUpToDate:        true,
-		ResourceLateInitialized: isLateInitialized,
-	}, nil
+// rateAt returns the rate for the product and pricing group values of the
+// spec in effect at the given time, preferring one that matches the spec, or
+// nil if there is none or the rate card no longer exists.
+func (e *metronomeExternal) rateAt(ctx context.Context, cr *v1alpha1.Rate, at string) (*metronomeClient.Rate, error) {
+	var found *metronomeClient.Rate
+	rates := metronomeClient.AllRates(ctx, e.metronome, metronomeClient.GetRatesRequest{
+		RateCardID: cr.Spec.ForProvider.RateCardID,
+		At:         at,
+		Selectors: []metronomeClient.RateSelector{{
+			PricingGroupValues: cr.Spec.ForProvider.PricingGroupValues,
+			ProductID:          cr.Spec.ForProvider.ProductID,
+		}},
+	})
+	for r, err := range rates {
+		if errors.Is(err, metronomeClient.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.ProductID != cr.Spec.ForProvider.ProductID || !maps.Equal(r.PricingGroupValues, cr.Spec.ForProvider.PricingGroupValues) {
+			continue
+		}
+		if e.isUpToDate(cr, &r) {
+			return &r, nil
+		}
+		if found == nil {
+			found = &r
+		}
+	}
+	return found, nil
 }
 
 func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -199,18 +280,161 @@ func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (ma
 	return managed.ExternalCreation{}, nil
 }
 
+// Update adds a new version of the rate matching the spec. The current version
+// is ended when the change takes effect, and the new version starts then. A
+// change to only when the rate ends is made to the current version instead,
+// and a rate that has already ended is resumed by a new version.
 func (e *metronomeExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	_, ok := mg.(*v1alpha1.Rate)
+	cr, ok := mg.(*v1alpha1.Rate)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotRate)
 	}
 
 	e.logger.Debug("Updating")
 
-	return managed.ExternalUpdate{}, errors.New("updating a rate is not supported")
+	versions, err := e.versions(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errGetRate)
+	}
+	if len(versions) == 0 {
+		return managed.ExternalUpdate{}, errors.New(errRateGone)
+	}
+	current := versions[len(versions)-1]
+	ended := endsBy(current, e.clock.Now())
+
+	if !ended && e.onlyEndChanged(cr, current) {
+		if err := e.metronome.UpdateRateEndDate(ctx, metronomeClient.UpdateRateEndDateRequest{
+			RateCardID:         cr.Spec.ForProvider.RateCardID,
+			ProductID:          current.ProductID,
+			PricingGroupValues: current.PricingGroupValues,
+			StartingAt:         current.StartingAt,
+			EndingBefore:       cr.Spec.ForProvider.EndingBefore,
+		}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errEndRate)
+		}
+		return managed.ExternalUpdate{}, nil
+	}
+
+	changeAt, err := e.changeAt(cr, current)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	// a previous update may have ended the current version without adding
+	// the new one
+	if !ended && !metronomeClient.SameTime(current.EndingBefore, changeAt) {
+		if err := e.metronome.UpdateRateEndDate(ctx, metronomeClient.UpdateRateEndDateRequest{
+			RateCardID:         cr.Spec.ForProvider.RateCardID,
+			ProductID:          current.ProductID,
+			PricingGroupValues: current.PricingGroupValues,
+			StartingAt:         current.StartingAt,
+			EndingBefore:       changeAt,
+		}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errEndRate)
+		}
+	}
+
+	converter := &converters.RateConverterImpl{}
+	req := converter.FromRateSpec(&cr.Spec.ForProvider)
+	req.StartingAt = changeAt
+
+	if _, err := e.metronome.AddRate(ctx, *req); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errCreateRate)
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// onlyEndChanged reports whether the current version of the rate differs from
+// the spec only in when it ends.
+func (e *metronomeExternal) onlyEndChanged(cr *v1alpha1.Rate, current metronomeClient.Rate) bool {
+	if metronomeClient.SameTime(current.EndingBefore, cr.Spec.ForProvider.EndingBefore) {
+		return false
+	}
+	current.EndingBefore = cr.Spec.ForProvider.EndingBefore
+	return e.isUpToDate(cr, &current)
+}
+
+// changeAt returns when the new version of the rate starts. It is the end of
+// the current version if it has already been ended in the future, or
+// otherwise when the change-at annotation schedules it.
+func (e *metronomeExternal) changeAt(cr *v1alpha1.Rate, current metronomeClient.Rate) (string, error) {
+	if _, ok := cr.GetAnnotations()[v1alpha1.AnnotationKeyChangeAt]; !ok && current.EndingBefore != "" {
+		if !endsBy(current, e.clock.Now()) {
+			t, err := time.Parse(time.RFC3339, current.EndingBefore)
+			if err == nil {
+				return t.UTC().Format(time.RFC3339), nil
+			}
+		}
+	}
+	at, err := e.scheduledAt(cr, v1alpha1.AnnotationKeyChangeAt, current)
+	if err != nil {
+		return "", err
+	}
+	return at.UTC().Format(time.RFC3339), nil
+}
+
+// scheduledAt returns the time set by the given annotation, or the next hour
+// boundary if it isn't set. Metronome requires the current version of a rate
+// to last at least an hour, so the time is never before then.
+func (e *metronomeExternal) scheduledAt(cr *v1alpha1.Rate, key string, current metronomeClient.Rate) (time.Time, error) {
+	at := e.clock.Now().Truncate(time.Hour).Add(time.Hour)
+	if v, ok := cr.GetAnnotations()[key]; ok {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil || !t.Truncate(time.Hour).Equal(t) {
+			return time.Time{}, errors.Errorf(errParseAnnotation, key)
+		}
+		at = t
+	}
+
+	if start, err := time.Parse(time.RFC3339, current.StartingAt); err == nil && !at.After(start) {
+		at = start.Truncate(time.Hour).Add(time.Hour)
+	}
+	return at, nil
 }
 
-func (e *metronomeExternal) Delete(_ context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+// Delete ends the current version of the rate when the end-at annotation says,
+// or at the next hour boundary. A rate that already ends by then is left as
+// is.
+func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	cr, ok := mg.(*v1alpha1.Rate)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotRate)
+	}
+
+	e.logger.Debug("Deleting")
+
+	versions, err := e.versions(ctx, cr)
+	if err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, errGetRate)
+	}
+	if len(versions) == 0 {
+		return managed.ExternalDelete{}, nil
+	}
+	current := versions[len(versions)-1]
+
+	endAt, err := e.scheduledAt(cr, v1alpha1.AnnotationKeyEndAt, current)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+	if endsBy(current, endAt) {
+		return managed.ExternalDelete{}, nil
+	}
+
+	err = e.metronome.UpdateRateEndDate(ctx, metronomeClient.UpdateRateEndDateRequest{
+		RateCardID:         cr.Spec.ForProvider.RateCardID,
+		ProductID:          current.ProductID,
+		PricingGroupValues: current.PricingGroupValues,
+		StartingAt:         current.StartingAt,
+		EndingBefore:       endAt.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		// the rate or its rate card has already been removed
+		if errors.Is(err, metronomeClient.ErrNotFound) {
+			return managed.ExternalDelete{}, nil
+		}
+		return managed.ExternalDelete{}, errors.Wrap(err, errEndRate)
+	}
 	return managed.ExternalDelete{}, nil
 }
 
@@ -226,10 +450,16 @@ func (e *metronomeExternal) isUpToDate(cr *v1alpha1.Rate, r *metronomeClient.Rat
 	params := converter.FromRateToParameters(r)
 
 	sortTiers := func(a, b v1alpha1.Tier) int {
-		if math.Abs(a.Price-b.Price) <= 1e-9 { // float equivalency
-			return int(a.Size - b.Size)
+		if c := a.Price.Cmp(b.Price); c != 0 {
+			return c
 		}
-		return int(a.Price - b.Price)
+		switch {
+		case a.Size < b.Size:
+			return -1
+		case a.Size > b.Size:
+			return 1
+		}
+		return 0
 	}
 
 	slices.SortFunc(spec.Tiers, sortTiers)
@@ -254,6 +484,12 @@ func (e *metronomeExternal) isUpToDate(cr *v1alpha1.Rate, r *metronomeClient.Rat
 	return cmp.Equal(spec, params, opts...)
 }
 
+// endsBy reports whether the rate ends no later than the given time.
+func endsBy(r metronomeClient.Rate, at time.Time) bool {
+	end, err := time.Parse(time.RFC3339, r.EndingBefore)
+	return err == nil && !end.After(at)
+}
+
 func lateInitialize(in *v1alpha1.RateParameters, r *metronomeClient.Rate) {
 	in.CreditTypeID = r.Details.CreditType.ID
 }
@@ -2,11 +2,15 @@ package rate
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -16,6 +20,7 @@ import (
 
 	"github.com/redbackthomson/provider-metronome/apis/rate/v1alpha1"
 	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+	"github.com/redbackthomson/provider-metronome/internal/clients/metronome/metronometest"
 )
 
 const (
@@ -61,19 +66,19 @@ func fullyPopulate(release *v1alpha1.Rate) {
 		StartingAt: "starting-at",
 		Entitled:   true,
 		RateType:   "rate-type",
-		Price:      1.01,
+		Price:      "1.01",
 		PricingGroupValues: map[string]string{
 			"key1": "val1",
 			"key2": "val2",
 		},
 		CommitRate: &v1alpha1.CommitRate{
 			RateType: "commit-rate-type",
-			Price:    1.02,
+			Price:    "1.02",
 			Tiers: []v1alpha1.Tier{{
-				Price: 1.03,
+				Price: "1.03",
 				Size:  10,
 			}, {
-				Price: 1.04,
+				Price: "1.04",
 				Size:  20,
 			}},
 		},
@@ -82,10 +87,10 @@ func fullyPopulate(release *v1alpha1.Rate) {
 		IsProrated:   true,
 		Quantity:     1.05,
 		Tiers: []v1alpha1.Tier{{
-			Price: 1.06,
+			Price: "1.06",
 			Size:  30,
 		}, {
-			Price: 1.07,
+			Price: "1.07",
 			Size:  40,
 		}},
 		UseListPrices: true,
@@ -111,29 +116,29 @@ var fullyPopulated = metronomeClient.Rate{
 	},
 	CommitRate: &metronomeClient.CommitRate{
 		RateType: "commit-rate-type",
-		Price:    1.02,
+		Price:    "1.02",
 		Tiers: []metronomeClient.Tier{{
-			Price: 1.03,
+			Price: "1.03",
 			Size:  10,
 		}, {
-			Price: 1.04,
+			Price: "1.04",
 			Size:  20,
 		}},
 	},
 	Details: metronomeClient.RateDetails{
 		RateType:   "rate-type",
 		IsProrated: true,
-		Price:      1.01,
+		Price:      "1.01",
 		PricingGroupValues: map[string]string{
 			"key1": "val1",
 			"key2": "val2",
 		},
 		Quantity: 1.05,
 		Tiers: []metronomeClient.Tier{{
-			Price: 1.06,
+			Price: "1.06",
 			Size:  30,
 		}, {
-			Price: 1.07,
+			Price: "1.07",
 			Size:  40,
 		}},
 		UseListPrices: true,
@@ -149,8 +154,9 @@ type notRateResource struct {
 }
 
 type MockRateClient struct {
-	GetRatesFn func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error)
-	AddRateFn  func(ctx context.Context, reqData metronomeClient.AddRateRequest) (*metronomeClient.AddRateResponse, error)
+	GetRatesFn          func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error)
+	AddRateFn           func(ctx context.Context, reqData metronomeClient.AddRateRequest) (*metronomeClient.AddRateResponse, error)
+	UpdateRateEndDateFn func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error
 }
 
 func (m *MockRateClient) AddRate(ctx context.Context, reqData metronomeClient.AddRateRequest) (*metronomeClient.AddRateResponse, error) {
@@ -161,16 +167,60 @@ func (m *MockRateClient) GetRates(ctx context.Context, reqData metronomeClient.G
 	return m.GetRatesFn(ctx, reqData, nextPage)
 }
 
+// UpdateRateEndDate implements metronome.RateClient.
+func (m *MockRateClient) UpdateRateEndDate(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+	return m.UpdateRateEndDateFn(ctx, reqData)
+}
+
 var _ (metronomeClient.RateClient) = (*MockRateClient)(nil)
 
+func deleted(r *v1alpha1.Rate) {
+	now := metav1.Now()
+	r.SetDeletionTimestamp(&now)
+}
+
+// versioned returns a GetRates function serving each rate to requests made at
+// its starting time.
+func versioned(rates ...metronomeClient.Rate) func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+	return func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+		res := &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{}}
+		for _, r := range rates {
+			if r.StartingAt == reqData.At {
+				res.Data = append(res.Data, r)
+			}
+		}
+		return res, nil
+	}
+}
+
+// superseded returns a copy of fullyPopulated at a different price that ended
+// when the current version started.
+func superseded() metronomeClient.Rate {
+	r := fullyPopulated
+	r.EndingBefore = "2025-03-01T13:00:00Z"
+	r.Details.Price = "0.99"
+	return r
+}
+
+// current returns a copy of fullyPopulated that started when the superseded
+// version ended.
+func current() metronomeClient.Rate {
+	r := fullyPopulated
+	r.StartingAt = "2025-03-01T13:00:00Z"
+	return r
+}
+
 func Test_External_Observe(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 34, 56, 0, time.UTC)
+
 	type args struct {
 		metronome metronomeClient.RateClient
 		mg        resource.Managed
 	}
 	type want struct {
-		out managed.ExternalObservation
-		err error
+		out        managed.ExternalObservation
+		err        error
+		superseded int
 	}
 	cases := map[string]struct {
 		args
@@ -211,24 +261,92 @@ func Test_External_Observe(t *testing.T) {
 				err: errors.Wrap(errBoom, errGetRate),
 			},
 		},
-		"RateIsInstantlyDeleted": {
+		"RateCardGone": {
 			args: args{
 				metronome: &MockRateClient{
 					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
-						return nil, errBoom
+						return nil, &metronomeClient.APIError{StatusCode: http.StatusNotFound, Message: "Rate card not found"}
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"EndedEarlierThanSpec": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.EndingBefore = "2025-03-01T12:00:00Z"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
 					},
 				},
-				mg: rate(
-					func(release *v1alpha1.Rate) {
-						now := metav1.Now()
-						release.SetDeletionTimestamp(&now)
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"DeletedRateNotYetEnded": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(fullyPopulated),
+				},
+				mg: rate(fullyPopulate, deleted),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true},
+			},
+		},
+		"DeletedRateEndsLater": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.EndingBefore = "2025-03-01T13:00:00Z"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+				},
+				mg: rate(fullyPopulate, deleted),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true},
+			},
+		},
+		"DeletedRateEnded": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.EndingBefore = "2025-03-01T12:00:00Z"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
 					},
-				),
+				},
+				mg: rate(fullyPopulate, deleted),
 			},
 			want: want{
 				out: managed.ExternalObservation{ResourceExists: false},
 			},
 		},
+		"DeletedRateEndsAfterAnnotation": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.EndingBefore = "2025-03-01T13:00:00Z"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+				},
+				mg: rate(fullyPopulate, deleted, func(r *v1alpha1.Rate) {
+					r.SetAnnotations(map[string]string{v1alpha1.AnnotationKeyEndAt: "2025-03-01T12:00:00Z"})
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true},
+			},
+		},
 		"IgnoresDifferentStartingAt": {
 			args: args{
 				metronome: &MockRateClient{
@@ -277,12 +395,88 @@ func Test_External_Observe(t *testing.T) {
 				err: nil,
 			},
 		},
+		"NotUpToDate": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(superseded()),
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				err: nil,
+			},
+		},
+		"UpToDateWithDifferentlyWrittenPrice": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.Details.Price = "1e-4"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+				},
+				mg: rate(fullyPopulate, func(r *v1alpha1.Rate) {
+					r.Spec.ForProvider.Price = "0.00010"
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"NotUpToDateWithSubCentPriceChange": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.Details.Price = "0.0000000001"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+				},
+				mg: rate(fullyPopulate, func(r *v1alpha1.Rate) {
+					r.Spec.ForProvider.Price = "0.0000000002"
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"IgnoresOtherProducts": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						other := fullyPopulated
+						other.ProductID = "other-product-id"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{other}}, nil
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+				err: nil,
+			},
+		},
+		"FollowsNewerVersions": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(superseded(), current()),
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out:        managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				err:        nil,
+				superseded: 1,
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			e := &metronomeExternal{
 				logger:    logging.NewNopLogger(),
 				metronome: tc.args.metronome,
+				clock:     clocktesting.NewFakePassiveClock(now),
 			}
 			got, gotErr := e.Observe(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
@@ -292,6 +486,12 @@ func Test_External_Observe(t *testing.T) {
 			if diff := cmp.Diff(tc.want.out, got); diff != "" {
 				t.Fatalf("e.Observe(...): -want out, +got out: %s", diff)
 			}
+
+			if cr, ok := tc.args.mg.(*v1alpha1.Rate); ok {
+				if got := len(cr.Status.AtProvider.Superseded); got != tc.want.superseded {
+					t.Errorf("e.Observe(...): want %d superseded versions, got %d", tc.want.superseded, got)
+				}
+			}
 		})
 	}
 }
@@ -343,12 +543,12 @@ func Test_External_Create(t *testing.T) {
 							CreditTypeID:  "credit-type-id",
 							EndingBefore:  "ending-before",
 							IsProrated:    true,
-							Price:         1.01,
+							Price:         "1.01",
 							Quantity:      1.05,
 							UseListPrices: true,
 							Tiers: []metronomeClient.Tier{
-								{Price: 1.06, Size: 30},
-								{Price: 1.07, Size: 40},
+								{Price: "1.06", Size: 30},
+								{Price: "1.07", Size: 40},
 							},
 							PricingGroupValues: map[string]string{
 								"key1": "val1",
@@ -356,10 +556,10 @@ func Test_External_Create(t *testing.T) {
 							},
 							CommitRate: &metronomeClient.CommitRate{
 								RateType: "commit-rate-type",
-								Price:    1.02,
+								Price:    "1.02",
 								Tiers: []metronomeClient.Tier{
-									{Price: 1.03, Size: 10},
-									{Price: 1.04, Size: 20},
+									{Price: "1.03", Size: 10},
+									{Price: "1.04", Size: 20},
 								},
 							},
 						}
@@ -395,3 +595,590 @@ func Test_External_Create(t *testing.T) {
 		})
 	}
 }
+
+func Test_External_Update(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 34, 56, 0, time.UTC)
+
+	type args struct {
+		metronome metronomeClient.RateClient
+		mg        resource.Managed
+	}
+	type want struct {
+		out managed.ExternalUpdate
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotRateResource": {
+			args: args{
+				mg: notRateResource{},
+			},
+			want: want{
+				err: errors.New(errNotRate),
+			},
+		},
+		"FailedToGetRate": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetRate),
+			},
+		},
+		"RateGone": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(),
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				err: errors.New(errRateGone),
+			},
+		},
+		"InvalidChangeAt": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(superseded()),
+				},
+				mg: rate(fullyPopulate, func(r *v1alpha1.Rate) {
+					r.SetAnnotations(map[string]string{v1alpha1.AnnotationKeyChangeAt: "2025-04-01T00:30:00Z"})
+				}),
+			},
+			want: want{
+				err: errors.Errorf(errParseAnnotation, v1alpha1.AnnotationKeyChangeAt),
+			},
+		},
+		"FailedToEndRate": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.Details.Price = "0.99"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+					UpdateRateEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+						return errBoom
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errEndRate),
+			},
+		},
+		"FailedToAddRate": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.Details.Price = "0.99"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+					UpdateRateEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+						return nil
+					},
+					AddRateFn: func(ctx context.Context, reqData metronomeClient.AddRateRequest) (*metronomeClient.AddRateResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateRate),
+			},
+		},
+		"ChangesAtNextHour": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.Details.Price = "0.99"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+					UpdateRateEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+						expected := metronomeClient.UpdateRateEndDateRequest{
+							RateCardID:         "rate-card-id",
+							ProductID:          "product-id",
+							PricingGroupValues: map[string]string{"key1": "val1", "key2": "val2"},
+							StartingAt:         "starting-at",
+							EndingBefore:       "2025-03-01T13:00:00Z",
+						}
+						if diff := cmp.Diff(expected, reqData); diff != "" {
+							t.Errorf("UpdateRateEndDateRequest mismatched: -want req, +got req: %s", diff)
+						}
+						return nil
+					},
+					AddRateFn: func(ctx context.Context, reqData metronomeClient.AddRateRequest) (*metronomeClient.AddRateResponse, error) {
+						if reqData.StartingAt != "2025-03-01T13:00:00Z" || reqData.Price != "1.01" || reqData.EndingBefore != "ending-before" {
+							t.Errorf("AddRateRequest mismatched: got %+v", reqData)
+						}
+						return &metronomeClient.AddRateResponse{}, nil
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalUpdate{},
+			},
+		},
+		"ChangesAtAnnotation": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.Details.Price = "0.99"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+					UpdateRateEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+						if reqData.EndingBefore != "2025-04-01T00:00:00Z" {
+							t.Errorf("UpdateRateEndDateRequest mismatched: want ending before %q, got %q", "2025-04-01T00:00:00Z", reqData.EndingBefore)
+						}
+						return nil
+					},
+					AddRateFn: func(ctx context.Context, reqData metronomeClient.AddRateRequest) (*metronomeClient.AddRateResponse, error) {
+						if reqData.StartingAt != "2025-04-01T00:00:00Z" {
+							t.Errorf("AddRateRequest mismatched: want starting at %q, got %q", "2025-04-01T00:00:00Z", reqData.StartingAt)
+						}
+						return &metronomeClient.AddRateResponse{}, nil
+					},
+				},
+				mg: rate(fullyPopulate, func(r *v1alpha1.Rate) {
+					r.SetAnnotations(map[string]string{v1alpha1.AnnotationKeyChangeAt: "2025-04-01T00:00:00Z"})
+				}),
+			},
+			want: want{
+				out: managed.ExternalUpdate{},
+			},
+		},
+		"ChangesOnlyEndDate": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						if reqData.At != "starting-at" {
+							return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{}}, nil
+						}
+						r := fullyPopulated
+						r.EndingBefore = "2025-03-02T00:00:00Z"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+					UpdateRateEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+						expected := metronomeClient.UpdateRateEndDateRequest{
+							RateCardID:         "rate-card-id",
+							ProductID:          "product-id",
+							PricingGroupValues: map[string]string{"key1": "val1", "key2": "val2"},
+							StartingAt:         "starting-at",
+							EndingBefore:       "ending-before",
+						}
+						if diff := cmp.Diff(expected, reqData); diff != "" {
+							t.Errorf("UpdateRateEndDateRequest mismatched: -want req, +got req: %s", diff)
+						}
+						return nil
+					},
+					AddRateFn: func(ctx context.Context, reqData metronomeClient.AddRateRequest) (*metronomeClient.AddRateResponse, error) {
+						t.Errorf("AddRate(...): want the current version changed in place, got %+v", reqData)
+						return &metronomeClient.AddRateResponse{}, nil
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalUpdate{},
+			},
+		},
+		"ResumesEndedRate": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						if reqData.At != "starting-at" {
+							return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{}}, nil
+						}
+						r := fullyPopulated
+						r.EndingBefore = "2025-03-01T12:00:00Z"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+					UpdateRateEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+						t.Errorf("UpdateRateEndDate(...): want the ended version left as is, got %+v", reqData)
+						return nil
+					},
+					AddRateFn: func(ctx context.Context, reqData metronomeClient.AddRateRequest) (*metronomeClient.AddRateResponse, error) {
+						if reqData.StartingAt != "2025-03-01T13:00:00Z" || reqData.EndingBefore != "ending-before" {
+							t.Errorf("AddRateRequest mismatched: got %+v", reqData)
+						}
+						return &metronomeClient.AddRateResponse{}, nil
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalUpdate{},
+			},
+		},
+		"ReusesExistingEndDate": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						if reqData.At != "starting-at" {
+							return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{}}, nil
+						}
+						r := fullyPopulated
+						r.Details.Price = "0.99"
+						r.EndingBefore = "2025-03-02T00:00:00Z"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+					AddRateFn: func(ctx context.Context, reqData metronomeClient.AddRateRequest) (*metronomeClient.AddRateResponse, error) {
+						if reqData.StartingAt != "2025-03-02T00:00:00Z" {
+							t.Errorf("AddRateRequest mismatched: want starting at %q, got %q", "2025-03-02T00:00:00Z", reqData.StartingAt)
+						}
+						return &metronomeClient.AddRateResponse{}, nil
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalUpdate{},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:    logging.NewNopLogger(),
+				metronome: tc.args.metronome,
+				clock:     clocktesting.NewFakePassiveClock(now),
+			}
+			got, gotErr := e.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Update(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Fatalf("e.Update(...): -want out, +got out: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_External_Delete(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 34, 56, 0, time.UTC)
+
+	type args struct {
+		metronome metronomeClient.RateClient
+		mg        resource.Managed
+	}
+	type want struct {
+		out managed.ExternalDelete
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotRateResource": {
+			args: args{
+				mg: notRateResource{},
+			},
+			want: want{
+				err: errors.New(errNotRate),
+			},
+		},
+		"FailedToGetRate": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetRate),
+			},
+		},
+		"RateCardGone": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						return nil, &metronomeClient.APIError{StatusCode: http.StatusNotFound, Message: "Rate card not found"}
+					},
+				},
+				mg: rate(fullyPopulate, deleted),
+			},
+			want: want{
+				out: managed.ExternalDelete{},
+			},
+		},
+		"RateGone": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(),
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalDelete{},
+			},
+		},
+		"InvalidEndAt": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(fullyPopulated),
+				},
+				mg: rate(fullyPopulate, func(r *v1alpha1.Rate) {
+					r.SetAnnotations(map[string]string{v1alpha1.AnnotationKeyEndAt: "tomorrow"})
+				}),
+			},
+			want: want{
+				err: errors.Errorf(errParseAnnotation, v1alpha1.AnnotationKeyEndAt),
+			},
+		},
+		"FailedToEndRate": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(fullyPopulated),
+					UpdateRateEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+						return errBoom
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errEndRate),
+			},
+		},
+		"RateAlreadyRemoved": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(fullyPopulated),
+					UpdateRateEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+						return metronomeClient.ErrNotFound
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalDelete{},
+			},
+		},
+		"AlreadyEnds": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: func(ctx context.Context, reqData metronomeClient.GetRatesRequest, nextPage string) (*metronomeClient.GetRatesResponse, error) {
+						r := fullyPopulated
+						r.EndingBefore = "2025-03-01T13:00:00Z"
+						return &metronomeClient.GetRatesResponse{Data: []metronomeClient.Rate{r}}, nil
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalDelete{},
+			},
+		},
+		"EndsAtNextHour": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(fullyPopulated),
+					UpdateRateEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+						expected := metronomeClient.UpdateRateEndDateRequest{
+							RateCardID:         "rate-card-id",
+							ProductID:          "product-id",
+							PricingGroupValues: map[string]string{"key1": "val1", "key2": "val2"},
+							StartingAt:         "starting-at",
+							EndingBefore:       "2025-03-01T13:00:00Z",
+						}
+						if diff := cmp.Diff(expected, reqData); diff != "" {
+							t.Errorf("UpdateRateEndDateRequest mismatched: -want req, +got req: %s", diff)
+						}
+						return nil
+					},
+				},
+				mg: rate(fullyPopulate),
+			},
+			want: want{
+				out: managed.ExternalDelete{},
+			},
+		},
+		"EndsAtAnnotation": {
+			args: args{
+				metronome: &MockRateClient{
+					GetRatesFn: versioned(fullyPopulated),
+					UpdateRateEndDateFn: func(ctx context.Context, reqData metronomeClient.UpdateRateEndDateRequest) error {
+						if reqData.EndingBefore != "2025-04-01T00:00:00Z" {
+							t.Errorf("UpdateRateEndDateRequest mismatched: want ending before %q, got %q", "2025-04-01T00:00:00Z", reqData.EndingBefore)
+						}
+						return nil
+					},
+				},
+				mg: rate(fullyPopulate, func(r *v1alpha1.Rate) {
+					r.SetAnnotations(map[string]string{v1alpha1.AnnotationKeyEndAt: "2025-04-01T00:00:00Z"})
+				}),
+			},
+			want: want{
+				out: managed.ExternalDelete{},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:    logging.NewNopLogger(),
+				metronome: tc.args.metronome,
+				clock:     clocktesting.NewFakePassiveClock(now),
+			}
+			got, gotErr := e.Delete(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Delete(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Fatalf("e.Delete(...): -want out, +got out: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_MetronomeExternal_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 34, 56, 0, time.UTC)
+	clk := clocktesting.NewFakePassiveClock(now)
+
+	srv := metronometest.NewServer(metronometest.WithClock(clk))
+	defer srv.Close()
+
+	client := srv.NewClient()
+	e := &metronomeExternal{
+		logger:    logging.NewNopLogger(),
+		metronome: client.Rate(),
+		clock:     clk,
+	}
+
+	card, err := client.RateCard().CreateRateCard(ctx, metronomeClient.CreateRateCardRequest{Name: "Standard"})
+	if err != nil {
+		t.Fatalf("CreateRateCard(...): %v", err)
+	}
+	product, err := client.Product().CreateProduct(ctx, metronomeClient.CreateProductRequest{Name: "Seats", Type: "SUBSCRIPTION"})
+	if err != nil {
+		t.Fatalf("CreateProduct(...): %v", err)
+	}
+
+	cr := rate(func(r *v1alpha1.Rate) {
+		r.Spec.ForProvider = v1alpha1.RateParameters{
+			RateCardID: card.Data.ID,
+			ProductID:  product.Data.ID,
+			StartingAt: "2025-01-01T00:00:00Z",
+			Entitled:   true,
+			RateType:   "FLAT",
+			Price:      "100",
+		}
+	})
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and up to date, got %+v, %v", o, err)
+	}
+
+	// changing the price ends the current rate and adds a new one
+	cr.Spec.ForProvider.Price = "120"
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and not up to date, got %+v, %v", o, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and up to date, got %+v, %v", o, err)
+	}
+
+	want := []v1alpha1.SupersededRate{{
+		StartingAt:   "2025-01-01T00:00:00Z",
+		EndingBefore: "2025-03-01T13:00:00Z",
+	}}
+	if diff := cmp.Diff(want, cr.Status.AtProvider.Superseded, cmpopts.IgnoreFields(v1alpha1.SupersededRate{}, "Details", "CommitRate")); diff != "" {
+		t.Errorf("Observe(...): -want superseded, +got superseded: %s", diff)
+	}
+	if got := cr.Status.AtProvider.Superseded[0].Details.Price; got != "100" {
+		t.Errorf("Observe(...): want superseded price 100, got %v", got)
+	}
+	if got := cr.Status.AtProvider.StartingAt; got != "2025-03-01T13:00:00Z" {
+		t.Errorf("Observe(...): want current version starting at %q, got %q", "2025-03-01T13:00:00Z", got)
+	}
+
+	// rates in effect before the change are kept
+	rates, err := client.Rate().GetRates(ctx, metronomeClient.GetRatesRequest{RateCardID: card.Data.ID, At: "2025-02-01T00:00:00Z"}, "")
+	if err != nil || len(rates.Data) != 1 || rates.Data[0].Details.Price != "100" {
+		t.Errorf("GetRates(...): want the original rate before the change, got %+v, %v", rates, err)
+	}
+
+	// changing only when the rate ends changes the current version
+	cr.Spec.ForProvider.EndingBefore = "2025-04-01T00:00:00Z"
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and not up to date, got %+v, %v", o, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and up to date, got %+v, %v", o, err)
+	}
+	if got := cr.Status.AtProvider.StartingAt; got != "2025-03-01T13:00:00Z" || len(cr.Status.AtProvider.Superseded) != 1 {
+		t.Errorf("Observe(...): want the current version kept, got starting at %q with %d superseded", got, len(cr.Status.AtProvider.Superseded))
+	}
+
+	// deleting the rate ends it, and it is gone once it has ended
+	clk.SetTime(time.Date(2025, 3, 2, 8, 15, 0, 0, time.UTC))
+	deleted(cr)
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists {
+		t.Fatalf("Observe(...): want existing while deleting, got %+v, %v", o, err)
+	}
+	if _, err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete(...): %v", err)
+	}
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists {
+		t.Fatalf("Observe(...): want existing until the end passes, got %+v, %v", o, err)
+	}
+	clk.SetTime(time.Date(2025, 3, 2, 8, 59, 0, 0, time.UTC))
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists {
+		t.Fatalf("Observe(...): want existing until the end passes, got %+v, %v", o, err)
+	}
+	clk.SetTime(time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC))
+	if o, err := e.Observe(ctx, cr); err != nil || o.ResourceExists {
+		t.Fatalf("Observe(...): want gone once ended, got %+v, %v", o, err)
+	}
+
+	rates, err = client.Rate().GetRates(ctx, metronomeClient.GetRatesRequest{RateCardID: card.Data.ID, At: "2025-03-02T08:00:00Z"}, "")
+	if err != nil || len(rates.Data) != 1 || rates.Data[0].EndingBefore != "2025-03-02T09:00:00Z" {
+		t.Errorf("GetRates(...): want the rate ending at the next hour, got %+v, %v", rates, err)
+	}
+
+	// a rate that ended earlier than the spec says is resumed by a new
+	// version rather than added again from the start
+	cr.DeletionTimestamp = nil
+	clk.SetTime(time.Date(2025, 3, 2, 9, 30, 0, 0, time.UTC))
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and not up to date after ending early, got %+v, %v", o, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+	clk.SetTime(time.Date(2025, 3, 2, 11, 0, 0, 0, time.UTC))
+	if o, err := e.Observe(ctx, cr); err != nil || !o.ResourceExists || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want existing and up to date, got %+v, %v", o, err)
+	}
+	if got := cr.Status.AtProvider.StartingAt; got != "2025-03-02T10:00:00Z" || len(cr.Status.AtProvider.Superseded) != 2 {
+		t.Errorf("Observe(...): want a new version starting at %q, got %q with %d superseded", "2025-03-02T10:00:00Z", got, len(cr.Status.AtProvider.Superseded))
+	}
+	rates, err = client.Rate().GetRates(ctx, metronomeClient.GetRatesRequest{RateCardID: card.Data.ID, At: "2025-02-01T00:00:00Z"}, "")
+	if err != nil || len(rates.Data) != 1 || rates.Data[0].Details.Price != "100" {
+		t.Errorf("GetRates(...): want the original rate kept, got %+v, %v", rates, err)
+	}
+}
@@ -18,12 +18,16 @@ package ratecard
 
 import (
 	"context"
+	"maps"
 	"slices"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
@@ -39,6 +43,7 @@ import (
 	metronomev1alpha1 "github.com/redbackthomson/provider-metronome/apis/v1alpha1"
 	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
 	"github.com/redbackthomson/provider-metronome/internal/connector"
+	"github.com/redbackthomson/provider-metronome/internal/controller/customfieldkey"
 	"github.com/redbackthomson/provider-metronome/internal/converters"
 )
 
@@ -46,25 +51,45 @@ const (
 	errNotRateCard     = "managed resource is not a RateCard custom resource"
 	errGetRateCard     = "failed to get rate card"
 	errCreateRateCard  = "failed to create rate card"
+	errUpdateRateCard  = "failed to update rate card"
 	errArchiveRateCard = "failed to archive rate card"
+	errAdoptRateCard   = "failed to find rate card previously created for this resource"
+	errEnsureOwnerKey  = "failed to create owner custom field key"
+	errUpdateFields    = "failed to update rate card custom fields"
+	errRateCardGone    = "rate card no longer exists"
+	errImmutableFields = "cannot change immutable fields %s"
 )
 
 // Setup adds a controller that reconciles RateCard managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
+func Setup(mgr ctrl.Manager, o controller.Options, co connector.Options) error {
 	name := managed.ControllerName(v1alpha1.RateCardGroupKind)
 
+	var fieldKeys client.Reader
+	if co.ValidateCustomFieldKeys {
+		fieldKeys = mgr.GetClient()
+	}
+
 	reconcilerOptions := []managed.ReconcilerOption{
 		managed.WithExternalConnecter(
 			&connector.Connector[*v1alpha1.RateCard, *metronomeExternal]{
 				Logger:               o.Logger,
 				Client:               mgr.GetClient(),
 				Usage:                resource.NewProviderConfigUsageTracker(mgr.GetClient(), &metronomev1alpha1.ProviderConfigUsage{}),
-				BaseURL:              baseUrl,
+				BaseURL:              co.BaseURL,
+				RateLimiters:         co.RateLimiters,
+				Metrics:              co.Metrics,
+				TracerProvider:       co.TracerProvider,
+				HTTPClients:          co.HTTPClients,
+				Clients:              co.Clients,
 				NewMetronomeClientFn: metronomeClient.New,
 				NewExternalClientFn: func(log logging.Logger, client *metronomeClient.Client) *metronomeExternal {
 					return &metronomeExternal{
-						logger:    o.Logger,
-						metronome: client.RateCard(),
+						logger:       o.Logger,
+						metronome:    client.RateCard(),
+						customFields: client.CustomField(),
+						keys:         client.CustomFieldKey(),
+						fieldKeys:    fieldKeys,
+						adopt:        co.AdoptCreatedObjects,
 					}
 				},
 			}),
@@ -96,8 +121,18 @@ func Setup(mgr ctrl.Manager, o controller.Options, baseUrl string) error {
 }
 
 type metronomeExternal struct {
-	logger    logging.Logger
-	metronome metronomeClient.RateCardClient
+	logger       logging.Logger
+	metronome    metronomeClient.RateCardClient
+	customFields metronomeClient.CustomFieldClient
+	keys         metronomeClient.CustomFieldKeyClient
+
+	// fieldKeys lists the CustomFieldKey resources the custom fields of the
+	// spec are validated against. They aren't validated if it is nil.
+	fieldKeys client.Reader
+
+	// adopt marks the objects created in Metronome with the UID of their
+	// managed resource, and adopts them if their ID was never recorded.
+	adopt bool
 }
 
 func (e *metronomeExternal) Disconnect(ctx context.Context) error {
@@ -118,40 +153,96 @@ func (e *metronomeExternal) Observe(ctx context.Context, mg resource.Managed) (m
 	}
 
 	id := meta.GetExternalName(cr)
+	adopted := false
 	if id == "" {
-		return managed.ExternalObservation{}, nil
+		// a previous reconcile may have created the rate card without
+		// recording its ID
+		owned, err := e.findOwned(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errAdoptRateCard)
+		}
+		if owned == "" {
+			return managed.ExternalObservation{}, nil
+		}
+		e.logger.Debug("Adopting rate card", "id", owned)
+		meta.SetExternalName(cr, owned)
+		id, adopted = owned, true
 	}
 
-	res, err := e.metronome.GetRateCard(ctx, metronomeClient.GetRateCardRequest{
-		ID: id,
-	})
+	card, err := e.get(ctx, id)
 	if err != nil {
-		// the external name isn't valid
-		if errors.Is(err, metronomeClient.ErrRateCardInvalidName) {
-			return managed.ExternalObservation{ResourceExists: false}, nil
-		}
-		return managed.ExternalObservation{}, errors.Wrap(err, errGetRateCard)
+		return managed.ExternalObservation{}, err
 	}
-
-	if res == nil {
+	if card == nil {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	card := &res.Data
+	created := cr.Status.AtProvider.CreditTypeConversions
+	if created == nil {
+		// rate cards created by earlier versions of the provider, or adopted,
+		// are assumed to have been created with the spec
+		created = conversions(cr.Spec.ForProvider.CreditTypeConversions)
+	}
 
 	converter := &converters.RateCardConverterImpl{}
 	cr.Status.AtProvider = *converter.FromRateCard(card)
+	cr.Status.AtProvider.CreditTypeConversions = created
 	cr.SetConditions(xpv1.Available())
 
+	if immutable := immutableChanges(cr, card); len(immutable) > 0 {
+		cr.SetConditions(metronomev1alpha1.ImmutableFieldsChanged(immutable...))
+	} else if cr.GetCondition(metronomev1alpha1.TypeImmutableFieldsChanged).Status != corev1.ConditionUnknown {
+		cr.SetConditions(metronomev1alpha1.ImmutableFieldsUnchanged())
+	}
+
 	upToDate, diff := isUpToDate(cr, card)
 
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: upToDate,
-		Diff:             diff,
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: adopted,
+		Diff:                    diff,
 	}, nil
 }
 
+// get returns the rate card with the given ID, or nil if it no longer exists.
+func (e *metronomeExternal) get(ctx context.Context, id string) (*metronomeClient.RateCard, error) {
+	res, err := e.metronome.GetRateCard(ctx, metronomeClient.GetRateCardRequest{
+		ID: id,
+	})
+	if err != nil {
+		// the external name isn't valid, or the rate card no longer exists
+		if errors.Is(err, metronomeClient.ErrRateCardInvalidName) || errors.Is(err, metronomeClient.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, errGetRateCard)
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return &res.Data, nil
+}
+
+// findOwned returns the ID of the rate card marked as created by the managed
+// resource, or an empty string if there is none.
+func (e *metronomeExternal) findOwned(ctx context.Context, cr *v1alpha1.RateCard) (string, error) {
+	// only a create that was attempted can have lost the ID of the object
+	// it created
+	uid := string(cr.GetUID())
+	if !e.adopt || uid == "" || meta.GetExternalCreatePending(cr).IsZero() {
+		return "", nil
+	}
+	for obj, err := range metronomeClient.AllRateCards(ctx, e.metronome) {
+		if err != nil {
+			return "", err
+		}
+		if metronomeClient.IsOwnedBy(obj.CustomFields, uid) && obj.Name == cr.Spec.ForProvider.Name {
+			return obj.ID, nil
+		}
+	}
+	return "", nil
+}
+
 func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.RateCard)
 	if !ok {
@@ -160,9 +251,21 @@ func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (ma
 
 	e.logger.Debug("Creating")
 
+	if err := e.validateCustomFields(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
 	converter := &converters.RateCardConverterImpl{}
 	req := converter.FromRateCardSpec(&cr.Spec.ForProvider)
 
+	// mark the rate card so it can be adopted if its ID is lost
+	if uid := string(cr.GetUID()); e.adopt && uid != "" {
+		if err := metronomeClient.EnsureOwnerCustomFieldKey(ctx, e.keys, metronomeClient.EntityRateCard); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errEnsureOwnerKey)
+		}
+		req.CustomFields = metronomeClient.WithOwner(req.CustomFields, uid)
+	}
+
 	res, err := e.metronome.CreateRateCard(ctx, *req)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateRateCard)
@@ -172,19 +275,69 @@ func (e *metronomeExternal) Create(ctx context.Context, mg resource.Managed) (ma
 	}
 
 	meta.SetExternalName(cr, res.Data.ID)
+	cr.Status.AtProvider.CreditTypeConversions = conversions(cr.Spec.ForProvider.CreditTypeConversions)
 
 	return managed.ExternalCreation{}, nil
 }
 
 func (e *metronomeExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	_, ok := mg.(*v1alpha1.RateCard)
+	cr, ok := mg.(*v1alpha1.RateCard)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotRateCard)
 	}
 
 	e.logger.Debug("Updating")
 
-	return managed.ExternalUpdate{}, errors.New("updating a rate card is not supported")
+	if err := e.validateCustomFields(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	id := meta.GetExternalName(cr)
+	card, err := e.get(ctx, id)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if card == nil {
+		return managed.ExternalUpdate{}, errors.New(errRateCardGone)
+	}
+
+	spec := cr.Spec.ForProvider
+
+	if spec.Name != card.Name || spec.Description != card.Description || !sameAliases(spec.Aliases, card.Aliases) {
+		aliases := make([]metronomeClient.RateCardAlias, 0, len(spec.Aliases))
+		for _, a := range spec.Aliases {
+			aliases = append(aliases, metronomeClient.RateCardAlias{Name: a.Name})
+		}
+		if _, err := e.metronome.UpdateRateCard(ctx, metronomeClient.UpdateRateCardRequest{
+			RateCardID:  id,
+			Name:        spec.Name,
+			Description: spec.Description,
+			Aliases:     aliases,
+		}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateRateCard)
+		}
+	}
+
+	if err := metronomeClient.SyncCustomFieldValues(ctx, e.customFields, metronomeClient.EntityRateCard, id, spec.CustomFields, card.CustomFields); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFields)
+	}
+
+	// everything else has been updated, but the resource can't be in sync
+	// until the immutable fields are reverted
+	if immutable := immutableChanges(cr, card); len(immutable) > 0 {
+		return managed.ExternalUpdate{}, errors.Errorf(errImmutableFields, strings.Join(immutable, ", "))
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// validateCustomFields checks that the custom fields of the spec have
+// CustomFieldKey resources, if validation is enabled.
+func (e *metronomeExternal) validateCustomFields(ctx context.Context, cr *v1alpha1.RateCard) error {
+	if e.fieldKeys == nil {
+		return nil
+	}
+	return customfieldkey.ValidateKeys(ctx, e.fieldKeys, metronomeClient.EntityRateCard, cr.Spec.ForProvider.CustomFields)
 }
 
 func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
@@ -193,33 +346,85 @@ func (e *metronomeExternal) Delete(ctx context.Context, mg resource.Managed) (ma
 		return managed.ExternalDelete{}, errors.New(errNotRateCard)
 	}
 
-	e.logger.Debug("Creating")
+	e.logger.Debug("Deleting")
 
 	if _, err := e.metronome.ArchiveRateCard(ctx, metronomeClient.ArchiveRateCardRequest{
 		Data: metronomeClient.IDOnly{
 			ID: meta.GetExternalName(cr),
 		},
 	}); err != nil {
+		// the rate card has already been archived or no longer exists
+		if errors.Is(err, metronomeClient.ErrConflict) || errors.Is(err, metronomeClient.ErrNotFound) {
+			return managed.ExternalDelete{}, nil
+		}
 		return managed.ExternalDelete{}, errors.Wrap(err, errArchiveRateCard)
 	}
 
 	return managed.ExternalDelete{}, nil
 }
 
-func isUpToDate(cr *v1alpha1.RateCard, metric *metronomeClient.RateCard) (bool, string) {
+// immutableChanges returns the fields of the spec that differ from the rate
+// card but can't be updated.
+func immutableChanges(cr *v1alpha1.RateCard, card *metronomeClient.RateCard) []string {
+	var fields []string
+	// the fiat credit type defaults to USD when it isn't set
+	if id := cr.Spec.ForProvider.FiatCreditTypeID; id != "" && id != card.FiatCreditType.ID {
+		fields = append(fields, "fiatCreditTypeId")
+	}
+	if created := cr.Status.AtProvider.CreditTypeConversions; created != nil {
+		opts := []cmp.Option{
+			cmpopts.EquateEmpty(),
+			cmpopts.SortSlices(func(a, b v1alpha1.CreditTypeConversion) bool {
+				return a.CustomCreditTypeID < b.CustomCreditTypeID
+			}),
+		}
+		if !cmp.Equal(cr.Spec.ForProvider.CreditTypeConversions, created, opts...) {
+			fields = append(fields, "creditTypeConversions")
+		}
+	}
+	return fields
+}
+
+// conversions returns a copy of the credit type conversions that is never
+// nil, so that a rate card created without any can be told apart from one
+// whose conversions aren't known.
+func conversions(in []v1alpha1.CreditTypeConversion) []v1alpha1.CreditTypeConversion {
+	return append([]v1alpha1.CreditTypeConversion{}, in...)
+}
+
+// sameAliases reports whether a and b contain the same aliases, in any order.
+func sameAliases(a []v1alpha1.RateCardAlias, b []metronomeClient.RateCardAlias) bool {
+	var an, bn []string
+	for _, x := range a {
+		an = append(an, x.Name)
+	}
+	for _, x := range b {
+		bn = append(bn, x.Name)
+	}
+	slices.Sort(an)
+	slices.Sort(bn)
+	return slices.Equal(an, bn)
+}
+
+func isUpToDate(cr *v1alpha1.RateCard, card *metronomeClient.RateCard) (bool, string) {
 	spec := cr.Spec.ForProvider.DeepCopy()
 
-	converter := &converters.RateCardConverterImpl{}
-	params := converter.FromRateCardToParameters(metric)
+	// credit type conversions aren't returned by Metronome, and neither they
+	// nor the fiat credit type can be updated, so they are checked by
+	// immutableChanges and only the mutable fields are compared here
+	params := &v1alpha1.RateCardParameters{
+		Name:                  card.Name,
+		Description:           card.Description,
+		FiatCreditTypeID:      spec.FiatCreditTypeID,
+		CreditTypeConversions: spec.CreditTypeConversions,
+		CustomFields:          metronomeClient.WithoutOwner(maps.Clone(card.CustomFields)),
+	}
+	for _, a := range card.Aliases {
+		params.Aliases = append(params.Aliases, v1alpha1.RateCardAlias{Name: a.Name})
+	}
 
 	sortAliases := func(a, b v1alpha1.RateCardAlias) int {
-		if a.Name < b.Name {
-			return 1
-		}
-		if a.Name == b.Name {
-			return 0
-		}
-		return -1
+		return strings.Compare(a.Name, b.Name)
 	}
 
 	slices.SortFunc(spec.Aliases, sortAliases)
@@ -229,5 +434,6 @@ func isUpToDate(cr *v1alpha1.RateCard, metric *metronomeClient.RateCard) (bool,
 		cmpopts.EquateEmpty(),
 	}
 
-	return cmp.Equal(spec, params, opts...), cmp.Diff(spec, params, opts...)
+	upToDate := cmp.Equal(spec, params, opts...) && len(immutableChanges(cr, card)) == 0
+	return upToDate, cmp.Diff(spec, params, opts...)
 }
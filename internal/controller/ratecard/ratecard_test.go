@@ -3,11 +3,14 @@ package ratecard
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -17,6 +20,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/redbackthomson/provider-metronome/apis/ratecard/v1alpha1"
+	metronomev1alpha1 "github.com/redbackthomson/provider-metronome/apis/v1alpha1"
 	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
 )
 
@@ -67,6 +71,7 @@ type MockRateCardClient struct {
 	GetRateCardFn     func(ctx context.Context, reqData metronomeClient.GetRateCardRequest) (*metronomeClient.GetRateCardResponse, error)
 	UpdateRateCardFn  func(ctx context.Context, reqData metronomeClient.UpdateRateCardRequest) (*metronomeClient.UpdateRateCardResponse, error)
 	ArchiveRateCardFn func(ctx context.Context, reqData metronomeClient.ArchiveRateCardRequest) (*metronomeClient.ArchiveRateCardResponse, error)
+	ListRateCardsFn   func(ctx context.Context, nextPage string) (*metronomeClient.ListRateCardsResponse, error)
 }
 
 // CreateRateCard implements metronome.RateCardClient.
@@ -89,16 +94,46 @@ func (m *MockRateCardClient) ArchiveRateCard(ctx context.Context, reqData metron
 	return m.ArchiveRateCardFn(ctx, reqData)
 }
 
+// ListRateCards implements metronome.RateCardClient.
+func (m *MockRateCardClient) ListRateCards(ctx context.Context, nextPage string) (*metronomeClient.ListRateCardsResponse, error) {
+	return m.ListRateCardsFn(ctx, nextPage)
+}
+
 var _ (metronomeClient.RateCardClient) = (*MockRateCardClient)(nil)
 
+type MockCustomFieldClient struct {
+	SetCustomFieldValuesFn    func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error
+	DeleteCustomFieldValuesFn func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldValuesRequest) error
+	ListCustomFieldValuesFn   func(ctx context.Context, entity, key string) (map[string]string, error)
+}
+
+// SetCustomFieldValues implements metronome.CustomFieldClient.
+func (m *MockCustomFieldClient) SetCustomFieldValues(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+	return m.SetCustomFieldValuesFn(ctx, reqData)
+}
+
+// DeleteCustomFieldValues implements metronome.CustomFieldClient.
+func (m *MockCustomFieldClient) DeleteCustomFieldValues(ctx context.Context, reqData metronomeClient.DeleteCustomFieldValuesRequest) error {
+	return m.DeleteCustomFieldValuesFn(ctx, reqData)
+}
+
+// ListCustomFieldValues implements metronome.CustomFieldClient.
+func (m *MockCustomFieldClient) ListCustomFieldValues(ctx context.Context, entity, key string) (map[string]string, error) {
+	return m.ListCustomFieldValuesFn(ctx, entity, key)
+}
+
+var _ (metronomeClient.CustomFieldClient) = (*MockCustomFieldClient)(nil)
+
 func Test_External_Observe(t *testing.T) {
 	type args struct {
+		adopt     bool
 		metronome metronomeClient.RateCardClient
 		mg        resource.Managed
 	}
 	type want struct {
-		out managed.ExternalObservation
-		err error
+		out       managed.ExternalObservation
+		immutable corev1.ConditionStatus
+		err       error
 	}
 	cases := map[string]struct {
 		args
@@ -160,6 +195,247 @@ func Test_External_Observe(t *testing.T) {
 				err: nil,
 			},
 		},
+		"NoExternalName": {
+			args: args{
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					meta.SetExternalName(mg, "")
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"AdoptionDisabled": {
+			args: args{
+				metronome: &MockRateCardClient{
+					ListRateCardsFn: func(ctx context.Context, nextPage string) (*metronomeClient.ListRateCardsResponse, error) {
+						t.Errorf("ListRateCards(...): want no lookup")
+						return nil, errBoom
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"CreateNotAttempted": {
+			args: args{
+				adopt: true,
+				metronome: &MockRateCardClient{
+					ListRateCardsFn: func(ctx context.Context, nextPage string) (*metronomeClient.ListRateCardsResponse, error) {
+						t.Errorf("ListRateCards(...): want no lookup")
+						return nil, errBoom
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"FailedToFindOwnedRateCard": {
+			args: args{
+				adopt: true,
+				metronome: &MockRateCardClient{
+					ListRateCardsFn: func(ctx context.Context, nextPage string) (*metronomeClient.ListRateCardsResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errAdoptRateCard),
+			},
+		},
+		"NoOwnedRateCard": {
+			args: args{
+				adopt: true,
+				metronome: &MockRateCardClient{
+					ListRateCardsFn: func(ctx context.Context, nextPage string) (*metronomeClient.ListRateCardsResponse, error) {
+						return &metronomeClient.ListRateCardsResponse{
+							Data: []metronomeClient.RateCard{
+								{ID: "id1", Name: "name"},
+								{ID: "id2", Name: "name", CustomFields: map[string]string{metronomeClient.OwnerCustomFieldKey: "other-uid"}},
+							},
+						}, nil
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+					mg.Spec.ForProvider.Name = "name"
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"AdoptsOwnedRateCard": {
+			args: args{
+				adopt: true,
+				metronome: &MockRateCardClient{
+					ListRateCardsFn: func(ctx context.Context, nextPage string) (*metronomeClient.ListRateCardsResponse, error) {
+						return &metronomeClient.ListRateCardsResponse{
+							Data: []metronomeClient.RateCard{
+								{ID: "id1", Name: "name", CustomFields: map[string]string{metronomeClient.OwnerCustomFieldKey: "uid"}},
+							},
+						}, nil
+					},
+					GetRateCardFn: func(ctx context.Context, reqData metronomeClient.GetRateCardRequest) (*metronomeClient.GetRateCardResponse, error) {
+						if reqData.ID != "id1" {
+							return nil, errBoom
+						}
+						return &metronomeClient.GetRateCardResponse{
+							Data: metronomeClient.RateCard{
+								ID: "id1", Name: "name", CustomFields: map[string]string{metronomeClient.OwnerCustomFieldKey: "uid"},
+							},
+						}, nil
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					meta.SetExternalName(mg, "")
+					mg.SetUID("uid")
+					meta.SetExternalCreatePending(mg, time.Now())
+					mg.Spec.ForProvider.Name = "name"
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
+		"ImmutableFieldChanged": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: func(ctx context.Context, reqData metronomeClient.GetRateCardRequest) (*metronomeClient.GetRateCardResponse, error) {
+						return &metronomeClient.GetRateCardResponse{
+							Data: metronomeClient.RateCard{
+								ID: "id1", Name: "name", FiatCreditType: metronomeClient.FiatCreditType{ID: "usd"},
+							},
+						}, nil
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = v1alpha1.RateCardParameters{
+						Name:             "name",
+						FiatCreditTypeID: "eur",
+					}
+				}),
+			},
+			want: want{
+				out:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				immutable: corev1.ConditionTrue,
+			},
+		},
+		"CreditTypeConversionsChanged": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: func(ctx context.Context, reqData metronomeClient.GetRateCardRequest) (*metronomeClient.GetRateCardResponse, error) {
+						return &metronomeClient.GetRateCardResponse{
+							Data: metronomeClient.RateCard{ID: "id1", Name: "name"},
+						}, nil
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = v1alpha1.RateCardParameters{
+						Name: "name",
+						CreditTypeConversions: []v1alpha1.CreditTypeConversion{
+							{CustomCreditTypeID: "credits", FiatPerCustomCredit: "2"},
+						},
+					}
+					mg.Status.AtProvider.CreditTypeConversions = []v1alpha1.CreditTypeConversion{}
+				}),
+			},
+			want: want{
+				out:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				immutable: corev1.ConditionTrue,
+			},
+		},
+		"CreditTypeConversionsUnknown": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: func(ctx context.Context, reqData metronomeClient.GetRateCardRequest) (*metronomeClient.GetRateCardResponse, error) {
+						return &metronomeClient.GetRateCardResponse{
+							Data: metronomeClient.RateCard{ID: "id1", Name: "name"},
+						}, nil
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = v1alpha1.RateCardParameters{
+						Name: "name",
+						CreditTypeConversions: []v1alpha1.CreditTypeConversion{
+							{CustomCreditTypeID: "credits", FiatPerCustomCredit: "2"},
+						},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"ImmutableFieldReverted": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: func(ctx context.Context, reqData metronomeClient.GetRateCardRequest) (*metronomeClient.GetRateCardResponse, error) {
+						return &metronomeClient.GetRateCardResponse{
+							Data: metronomeClient.RateCard{
+								ID: "id1", Name: "name", FiatCreditType: metronomeClient.FiatCreditType{ID: "usd"},
+							},
+						}, nil
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = v1alpha1.RateCardParameters{
+						Name:             "name",
+						FiatCreditTypeID: "usd",
+					}
+					mg.SetConditions(metronomev1alpha1.ImmutableFieldsChanged("fiatCreditTypeId"))
+				}),
+			},
+			want: want{
+				out:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				immutable: corev1.ConditionFalse,
+			},
+		},
+		"UpToDateIgnoresAliasOrderAndOwner": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: func(ctx context.Context, reqData metronomeClient.GetRateCardRequest) (*metronomeClient.GetRateCardResponse, error) {
+						return &metronomeClient.GetRateCardResponse{
+							Data: metronomeClient.RateCard{
+								ID:             "id1",
+								Name:           "name",
+								FiatCreditType: metronomeClient.FiatCreditType{ID: "usd"},
+								Aliases:        []metronomeClient.RateCardAlias{{Name: "b"}, {Name: "a"}},
+								CustomFields:   map[string]string{"team": "billing", metronomeClient.OwnerCustomFieldKey: "uid"},
+							},
+						}, nil
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = v1alpha1.RateCardParameters{
+						Name:         "name",
+						Aliases:      []v1alpha1.RateCardAlias{{Name: "a"}, {Name: "b"}},
+						CustomFields: map[string]string{"team": "billing"},
+					}
+				}),
+			},
+			want: want{
+				out: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
 		"UpToDate": {
 			args: args{
 				metronome: &MockRateCardClient{
@@ -187,6 +463,7 @@ func Test_External_Observe(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			e := &metronomeExternal{
+				adopt:     tc.args.adopt,
 				logger:    logging.NewNopLogger(),
 				metronome: tc.args.metronome,
 			}
@@ -201,6 +478,16 @@ func Test_External_Observe(t *testing.T) {
 			if diff := cmp.Diff(tc.want.out, got, ignoreDiff); diff != "" {
 				t.Fatalf("e.Observe(...): -want out, +got out: %s", diff)
 			}
+
+			if cr, ok := tc.args.mg.(*v1alpha1.RateCard); ok {
+				want := tc.want.immutable
+				if want == "" {
+					want = corev1.ConditionUnknown
+				}
+				if diff := cmp.Diff(want, cr.GetCondition(metronomev1alpha1.TypeImmutableFieldsChanged).Status); diff != "" {
+					t.Fatalf("e.Observe(...): -want immutable condition, +got immutable condition: %s", diff)
+				}
+			}
 		})
 	}
 }
@@ -208,6 +495,7 @@ func Test_External_Observe(t *testing.T) {
 func Test_External_Create(t *testing.T) {
 	type args struct {
 		metronome metronomeClient.RateCardClient
+		fieldKeys client.Reader
 		mg        resource.Managed
 	}
 	type want struct {
@@ -226,6 +514,19 @@ func Test_External_Create(t *testing.T) {
 				err: errors.New(errNotRateCard),
 			},
 		},
+		"MissingCustomFieldKey": {
+			args: args{
+				fieldKeys: &test.MockClient{
+					MockList: test.NewMockListFn(nil),
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider.CustomFields = map[string]string{"team": "finance", "region": "us"}
+				}),
+			},
+			want: want{
+				err: errors.Errorf("no CustomFieldKey resource defines the %s custom field keys: %s", metronomeClient.EntityRateCard, "region, team"),
+			},
+		},
 		"FailedToCreateRateCard": {
 			args: args{
 				metronome: &MockRateCardClient{
@@ -272,6 +573,7 @@ func Test_External_Create(t *testing.T) {
 			e := &metronomeExternal{
 				logger:    logging.NewNopLogger(),
 				metronome: tc.args.metronome,
+				fieldKeys: tc.args.fieldKeys,
 			}
 			got, gotErr := e.Create(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
@@ -285,6 +587,215 @@ func Test_External_Create(t *testing.T) {
 	}
 }
 
+func Test_External_Update(t *testing.T) {
+	card := func(ctx context.Context, reqData metronomeClient.GetRateCardRequest) (*metronomeClient.GetRateCardResponse, error) {
+		return &metronomeClient.GetRateCardResponse{
+			Data: metronomeClient.RateCard{
+				ID:             reqData.ID,
+				Name:           "name",
+				Description:    "description",
+				FiatCreditType: metronomeClient.FiatCreditType{ID: "usd"},
+				Aliases:        []metronomeClient.RateCardAlias{{Name: "a"}},
+				CustomFields:   map[string]string{"team": "billing", "old": "value", metronomeClient.OwnerCustomFieldKey: "uid"},
+			},
+		}, nil
+	}
+	noCustomFieldChanges := &MockCustomFieldClient{
+		SetCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+			return errors.New("unexpected SetCustomFieldValues")
+		},
+		DeleteCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldValuesRequest) error {
+			return errors.New("unexpected DeleteCustomFieldValues")
+		},
+	}
+	unchanged := v1alpha1.RateCardParameters{
+		Name:         "name",
+		Description:  "description",
+		Aliases:      []v1alpha1.RateCardAlias{{Name: "a"}},
+		CustomFields: map[string]string{"team": "billing", "old": "value"},
+	}
+
+	type args struct {
+		metronome    metronomeClient.RateCardClient
+		customFields metronomeClient.CustomFieldClient
+		mg           resource.Managed
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotRateCardResource": {
+			args: args{
+				mg: notRateCardResource{},
+			},
+			want: want{
+				err: errors.New(errNotRateCard),
+			},
+		},
+		"RateCardGone": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: func(ctx context.Context, reqData metronomeClient.GetRateCardRequest) (*metronomeClient.GetRateCardResponse, error) {
+						return nil, &metronomeClient.APIError{StatusCode: 404}
+					},
+				},
+				mg: rateCard(),
+			},
+			want: want{
+				err: errors.New(errRateCardGone),
+			},
+		},
+		"FailedToUpdateRateCard": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: card,
+					UpdateRateCardFn: func(ctx context.Context, reqData metronomeClient.UpdateRateCardRequest) (*metronomeClient.UpdateRateCardResponse, error) {
+						return nil, errBoom
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = *unchanged.DeepCopy()
+					mg.Spec.ForProvider.Name = "new-name"
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateRateCard),
+			},
+		},
+		"UpdatesNameDescriptionAndAliases": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: card,
+					UpdateRateCardFn: func(ctx context.Context, reqData metronomeClient.UpdateRateCardRequest) (*metronomeClient.UpdateRateCardResponse, error) {
+						expected := metronomeClient.UpdateRateCardRequest{
+							RateCardID:  "external-name",
+							Name:        "new-name",
+							Description: "new-description",
+							Aliases:     []metronomeClient.RateCardAlias{},
+						}
+						if diff := cmp.Diff(expected, reqData); diff != "" {
+							t.Errorf("UpdateRateCard mismatched: -want req, +got req: %s", diff)
+						}
+						return &metronomeClient.UpdateRateCardResponse{}, nil
+					},
+				},
+				customFields: noCustomFieldChanges,
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = *unchanged.DeepCopy()
+					mg.Spec.ForProvider.Name = "new-name"
+					mg.Spec.ForProvider.Description = "new-description"
+					mg.Spec.ForProvider.Aliases = nil
+				}),
+			},
+		},
+		"UpdatesCustomFields": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: card,
+				},
+				customFields: &MockCustomFieldClient{
+					SetCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+						expected := metronomeClient.SetCustomFieldValuesRequest{
+							Entity:       metronomeClient.EntityRateCard,
+							EntityID:     "external-name",
+							CustomFields: map[string]string{"team": "finance"},
+						}
+						if diff := cmp.Diff(expected, reqData); diff != "" {
+							t.Errorf("SetCustomFieldValues mismatched: -want req, +got req: %s", diff)
+						}
+						return nil
+					},
+					DeleteCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.DeleteCustomFieldValuesRequest) error {
+						expected := metronomeClient.DeleteCustomFieldValuesRequest{
+							Entity:   metronomeClient.EntityRateCard,
+							EntityID: "external-name",
+							Keys:     []string{"old"},
+						}
+						if diff := cmp.Diff(expected, reqData); diff != "" {
+							t.Errorf("DeleteCustomFieldValues mismatched: -want req, +got req: %s", diff)
+						}
+						return nil
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = *unchanged.DeepCopy()
+					mg.Spec.ForProvider.CustomFields = map[string]string{"team": "finance"}
+				}),
+			},
+		},
+		"FailedToUpdateCustomFields": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: card,
+				},
+				customFields: &MockCustomFieldClient{
+					SetCustomFieldValuesFn: func(ctx context.Context, reqData metronomeClient.SetCustomFieldValuesRequest) error {
+						return errBoom
+					},
+				},
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = *unchanged.DeepCopy()
+					mg.Spec.ForProvider.CustomFields = map[string]string{"team": "finance", "old": "value"}
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateFields),
+			},
+		},
+		"ImmutableFieldChanged": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: card,
+				},
+				customFields: noCustomFieldChanges,
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = *unchanged.DeepCopy()
+					mg.Spec.ForProvider.FiatCreditTypeID = "eur"
+				}),
+			},
+			want: want{
+				err: errors.Errorf(errImmutableFields, "fiatCreditTypeId"),
+			},
+		},
+		"CreditTypeConversionsChanged": {
+			args: args{
+				metronome: &MockRateCardClient{
+					GetRateCardFn: card,
+				},
+				customFields: noCustomFieldChanges,
+				mg: rateCard(func(mg *v1alpha1.RateCard) {
+					mg.Spec.ForProvider = *unchanged.DeepCopy()
+					mg.Spec.ForProvider.CreditTypeConversions = []v1alpha1.CreditTypeConversion{
+						{CustomCreditTypeID: "credits", FiatPerCustomCredit: "2"},
+					}
+					mg.Status.AtProvider.CreditTypeConversions = []v1alpha1.CreditTypeConversion{
+						{CustomCreditTypeID: "credits", FiatPerCustomCredit: "1"},
+					}
+				}),
+			},
+			want: want{
+				err: errors.Errorf(errImmutableFields, "creditTypeConversions"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &metronomeExternal{
+				logger:       logging.NewNopLogger(),
+				metronome:    tc.args.metronome,
+				customFields: tc.args.customFields,
+			}
+			_, gotErr := e.Update(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Update(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
+
 func Test_External_Delete(t *testing.T) {
 	type args struct {
 		metronome metronomeClient.RateCardClient
@@ -34,15 +34,20 @@ import (
 type ProductConverter interface {
 	FromProductSpec(in *v1alpha1.ProductParameters) *metronome.CreateProductRequest
 
-	// goverter:ignore BillableMetricRef BillableMetricSelector StartingAt
+	// goverter:ignore BillableMetricRef BillableMetricSelector StartingAt UpdatePolicy Schedule
 	ToProductSpec(in *metronome.CreateProductRequest) *v1alpha1.ProductParameters
 
+	// goverter:ignore UpdateStartingAt
 	FromProduct(in *metronome.Product) *v1alpha1.ObservedProduct
 	ToProduct(in *v1alpha1.ObservedProduct) *metronome.Product
 
 	// goverter:ignore ProductID
 	ToProductUpdate(in *v1alpha1.ProductParameters) *metronome.UpdateProductRequest
 
+	// goverter:ignore ProductID
+	FromProductScheduleEntry(in *v1alpha1.ProductScheduleEntry) *metronome.UpdateProductRequest
+	ToProductScheduleEntry(in *metronome.ProductDetails) *v1alpha1.ProductScheduleEntry
+
 	// goverter:ignoreMissing
 	// goverter:map Current.BillableMetricID BillableMetricID
 	// goverter:map Current.CompositeProductIDs CompositeProductIDs
@@ -37,7 +37,9 @@ type RateConverter interface {
 	// goverter:ignore RateCardRef RateCardSelector ProductRef ProductSelector
 	ToRateSpec(in *metronome.AddRateRequest) *v1alpha1.RateParameters
 
+	// goverter:ignore Superseded
 	FromRate(in *metronome.Rate) *v1alpha1.ObservedRate
+	FromRateToSuperseded(in metronome.Rate) v1alpha1.SupersededRate
 	ToRate(in *v1alpha1.ObservedRate) *metronome.Rate
 
 	// goverter:ignoreMissing
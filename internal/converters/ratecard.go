@@ -35,6 +35,7 @@ type RateCardConverter interface {
 	FromRateCardSpec(in *v1alpha1.RateCardParameters) *metronome.CreateRateCardRequest
 	ToRateCardSpec(in *metronome.CreateRateCardRequest) *v1alpha1.RateCardParameters
 
+	// goverter:ignore CreditTypeConversions
 	FromRateCard(in *metronome.RateCard) *v1alpha1.ObservedRateCard
 	ToRateCard(in *v1alpha1.ObservedRateCard) *metronome.RateCard
 
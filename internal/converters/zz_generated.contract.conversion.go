@@ -0,0 +1,214 @@
+// Code generated by github.com/jmattheis/goverter, DO NOT EDIT.
+//go:build !ignore_autogenerated
+
+package converters
+
+import (
+	v1alpha1 "github.com/redbackthomson/provider-metronome/apis/contract/v1alpha1"
+	v1alpha11 "github.com/redbackthomson/provider-metronome/apis/rate/v1alpha1"
+	metronome "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+// +k8s:deepcopy-gen=false
+type ContractConverterImpl struct{}
+
+func (c *ContractConverterImpl) FromCommitSpec(source v1alpha1.Commit) metronome.CommitRequest {
+	var metronomeCommitRequest metronome.CommitRequest
+	metronomeCommitRequest.Type = source.Type
+	metronomeCommitRequest.Name = source.Name
+	metronomeCommitRequest.Description = source.Description
+	metronomeCommitRequest.ProductID = source.ProductID
+	metronomeCommitRequest.AccessSchedule = c.pV1alpha1AccessScheduleToPMetronomeAccessSchedule(source.AccessSchedule)
+	if source.ApplicableProductIDs != nil {
+		metronomeCommitRequest.ApplicableProductIDs = make([]string, len(source.ApplicableProductIDs))
+		for i := 0; i < len(source.ApplicableProductIDs); i++ {
+			metronomeCommitRequest.ApplicableProductIDs[i] = source.ApplicableProductIDs[i]
+		}
+	}
+	if source.RolloverFraction != nil {
+		xfloat64 := *source.RolloverFraction
+		metronomeCommitRequest.RolloverFraction = &xfloat64
+	}
+	return metronomeCommitRequest
+}
+func (c *ContractConverterImpl) FromContract(source *metronome.Contract) *v1alpha1.ObservedContract {
+	var pV1alpha1ObservedContract *v1alpha1.ObservedContract
+	if source != nil {
+		var v1alpha1ObservedContract v1alpha1.ObservedContract
+		v1alpha1ObservedContract.ID = (*source).ID
+		v1alpha1ObservedContract.CustomerID = (*source).CustomerID
+		v1alpha1ObservedContract.RateCardID = (*source).RateCardID
+		v1alpha1ObservedContract.Name = (*source).Name
+		v1alpha1ObservedContract.StartingAt = (*source).StartingAt
+		v1alpha1ObservedContract.EndingBefore = (*source).EndingBefore
+		v1alpha1ObservedContract.NetPaymentTermsDays = (*source).NetPaymentTermsDays
+		v1alpha1ObservedContract.CreatedAt = (*source).CreatedAt
+		v1alpha1ObservedContract.CreatedBy = (*source).CreatedBy
+		if (*source).CustomFields != nil {
+			v1alpha1ObservedContract.CustomFields = make(map[string]string, len((*source).CustomFields))
+			for key, value := range (*source).CustomFields {
+				v1alpha1ObservedContract.CustomFields[key] = value
+			}
+		}
+		if (*source).Commits != nil {
+			v1alpha1ObservedContract.Commits = make([]v1alpha1.ObservedCommit, len((*source).Commits))
+			for i := 0; i < len((*source).Commits); i++ {
+				v1alpha1ObservedContract.Commits[i] = c.metronomeCommitToV1alpha1ObservedCommit((*source).Commits[i])
+			}
+		}
+		if (*source).Overrides != nil {
+			v1alpha1ObservedContract.Overrides = make([]v1alpha1.ObservedOverride, len((*source).Overrides))
+			for j := 0; j < len((*source).Overrides); j++ {
+				v1alpha1ObservedContract.Overrides[j] = c.metronomeOverrideToV1alpha1ObservedOverride((*source).Overrides[j])
+			}
+		}
+		pV1alpha1ObservedContract = &v1alpha1ObservedContract
+	}
+	return pV1alpha1ObservedContract
+}
+func (c *ContractConverterImpl) FromContractSpec(source *v1alpha1.ContractParameters) *metronome.CreateContractRequest {
+	var pMetronomeCreateContractRequest *metronome.CreateContractRequest
+	if source != nil {
+		var metronomeCreateContractRequest metronome.CreateContractRequest
+		metronomeCreateContractRequest.CustomerID = (*source).CustomerID
+		metronomeCreateContractRequest.RateCardID = (*source).RateCardID
+		metronomeCreateContractRequest.Name = (*source).Name
+		metronomeCreateContractRequest.StartingAt = (*source).StartingAt
+		metronomeCreateContractRequest.EndingBefore = (*source).EndingBefore
+		if (*source).NetPaymentTermsDays != nil {
+			xint := *(*source).NetPaymentTermsDays
+			metronomeCreateContractRequest.NetPaymentTermsDays = &xint
+		}
+		if (*source).CustomFields != nil {
+			metronomeCreateContractRequest.CustomFields = make(map[string]string, len((*source).CustomFields))
+			for key, value := range (*source).CustomFields {
+				metronomeCreateContractRequest.CustomFields[key] = value
+			}
+		}
+		if (*source).Commits != nil {
+			metronomeCreateContractRequest.Commits = make([]metronome.CommitRequest, len((*source).Commits))
+			for i := 0; i < len((*source).Commits); i++ {
+				metronomeCreateContractRequest.Commits[i] = c.FromCommitSpec((*source).Commits[i])
+			}
+		}
+		if (*source).Overrides != nil {
+			metronomeCreateContractRequest.Overrides = make([]metronome.OverrideRequest, len((*source).Overrides))
+			for j := 0; j < len((*source).Overrides); j++ {
+				metronomeCreateContractRequest.Overrides[j] = c.FromOverrideSpec((*source).Overrides[j])
+			}
+		}
+		pMetronomeCreateContractRequest = &metronomeCreateContractRequest
+	}
+	return pMetronomeCreateContractRequest
+}
+func (c *ContractConverterImpl) FromOverrideSpec(source v1alpha1.Override) metronome.OverrideRequest {
+	var metronomeOverrideRequest metronome.OverrideRequest
+	metronomeOverrideRequest.ProductID = source.ProductID
+	metronomeOverrideRequest.StartingAt = source.StartingAt
+	metronomeOverrideRequest.EndingBefore = source.EndingBefore
+	metronomeOverrideRequest.Type = source.Type
+	if source.Multiplier != nil {
+		xfloat64 := *source.Multiplier
+		metronomeOverrideRequest.Multiplier = &xfloat64
+	}
+	metronomeOverrideRequest.OverwriteRate = c.pV1alpha1OverwriteRateToPMetronomeOverwriteRate(source.OverwriteRate)
+	return metronomeOverrideRequest
+}
+func (c *ContractConverterImpl) metronomeCommitToV1alpha1ObservedCommit(source metronome.Commit) v1alpha1.ObservedCommit {
+	var v1alpha1ObservedCommit v1alpha1.ObservedCommit
+	v1alpha1ObservedCommit.ID = source.ID
+	v1alpha1ObservedCommit.Type = source.Type
+	v1alpha1ObservedCommit.Name = source.Name
+	v1alpha1ObservedCommit.Description = source.Description
+	v1alpha1ObservedCommit.Product = c.metronomeProductIdentifierToV1alpha1ProductIdentifier(source.Product)
+	v1alpha1ObservedCommit.AccessSchedule = c.pMetronomeAccessScheduleToPV1alpha1AccessSchedule(source.AccessSchedule)
+	v1alpha1ObservedCommit.RolloverFraction = source.RolloverFraction
+	if source.ApplicableProductIDs != nil {
+		v1alpha1ObservedCommit.ApplicableProductIDs = make([]string, len(source.ApplicableProductIDs))
+		for i := 0; i < len(source.ApplicableProductIDs); i++ {
+			v1alpha1ObservedCommit.ApplicableProductIDs[i] = source.ApplicableProductIDs[i]
+		}
+	}
+	return v1alpha1ObservedCommit
+}
+func (c *ContractConverterImpl) metronomeOverrideToV1alpha1ObservedOverride(source metronome.Override) v1alpha1.ObservedOverride {
+	var v1alpha1ObservedOverride v1alpha1.ObservedOverride
+	v1alpha1ObservedOverride.ID = source.ID
+	v1alpha1ObservedOverride.Product = c.metronomeProductIdentifierToV1alpha1ProductIdentifier(source.Product)
+	v1alpha1ObservedOverride.StartingAt = source.StartingAt
+	v1alpha1ObservedOverride.EndingBefore = source.EndingBefore
+	v1alpha1ObservedOverride.Type = source.Type
+	v1alpha1ObservedOverride.Multiplier = source.Multiplier
+	v1alpha1ObservedOverride.OverwriteRate = c.pMetronomeOverwriteRateToPV1alpha1OverwriteRate(source.OverwriteRate)
+	return v1alpha1ObservedOverride
+}
+func (c *ContractConverterImpl) metronomeProductIdentifierToV1alpha1ProductIdentifier(source metronome.ProductIdentifier) v1alpha1.ProductIdentifier {
+	var v1alpha1ProductIdentifier v1alpha1.ProductIdentifier
+	v1alpha1ProductIdentifier.ID = source.ID
+	v1alpha1ProductIdentifier.Name = source.Name
+	return v1alpha1ProductIdentifier
+}
+func (c *ContractConverterImpl) metronomeScheduleItemToV1alpha1ScheduleItem(source metronome.ScheduleItem) v1alpha1.ScheduleItem {
+	var v1alpha1ScheduleItem v1alpha1.ScheduleItem
+	v1alpha1ScheduleItem.Amount = v1alpha11.Decimal(source.Amount)
+	v1alpha1ScheduleItem.StartingAt = source.StartingAt
+	v1alpha1ScheduleItem.EndingBefore = source.EndingBefore
+	return v1alpha1ScheduleItem
+}
+func (c *ContractConverterImpl) pMetronomeAccessScheduleToPV1alpha1AccessSchedule(source *metronome.AccessSchedule) *v1alpha1.AccessSchedule {
+	var pV1alpha1AccessSchedule *v1alpha1.AccessSchedule
+	if source != nil {
+		var v1alpha1AccessSchedule v1alpha1.AccessSchedule
+		v1alpha1AccessSchedule.CreditTypeID = (*source).CreditTypeID
+		if (*source).ScheduleItems != nil {
+			v1alpha1AccessSchedule.ScheduleItems = make([]v1alpha1.ScheduleItem, len((*source).ScheduleItems))
+			for i := 0; i < len((*source).ScheduleItems); i++ {
+				v1alpha1AccessSchedule.ScheduleItems[i] = c.metronomeScheduleItemToV1alpha1ScheduleItem((*source).ScheduleItems[i])
+			}
+		}
+		pV1alpha1AccessSchedule = &v1alpha1AccessSchedule
+	}
+	return pV1alpha1AccessSchedule
+}
+func (c *ContractConverterImpl) pMetronomeOverwriteRateToPV1alpha1OverwriteRate(source *metronome.OverwriteRate) *v1alpha1.OverwriteRate {
+	var pV1alpha1OverwriteRate *v1alpha1.OverwriteRate
+	if source != nil {
+		var v1alpha1OverwriteRate v1alpha1.OverwriteRate
+		v1alpha1OverwriteRate.RateType = (*source).RateType
+		v1alpha1OverwriteRate.Price = v1alpha11.Decimal((*source).Price)
+		pV1alpha1OverwriteRate = &v1alpha1OverwriteRate
+	}
+	return pV1alpha1OverwriteRate
+}
+func (c *ContractConverterImpl) pV1alpha1AccessScheduleToPMetronomeAccessSchedule(source *v1alpha1.AccessSchedule) *metronome.AccessSchedule {
+	var pMetronomeAccessSchedule *metronome.AccessSchedule
+	if source != nil {
+		var metronomeAccessSchedule metronome.AccessSchedule
+		metronomeAccessSchedule.CreditTypeID = (*source).CreditTypeID
+		if (*source).ScheduleItems != nil {
+			metronomeAccessSchedule.ScheduleItems = make([]metronome.ScheduleItem, len((*source).ScheduleItems))
+			for i := 0; i < len((*source).ScheduleItems); i++ {
+				metronomeAccessSchedule.ScheduleItems[i] = c.v1alpha1ScheduleItemToMetronomeScheduleItem((*source).ScheduleItems[i])
+			}
+		}
+		pMetronomeAccessSchedule = &metronomeAccessSchedule
+	}
+	return pMetronomeAccessSchedule
+}
+func (c *ContractConverterImpl) pV1alpha1OverwriteRateToPMetronomeOverwriteRate(source *v1alpha1.OverwriteRate) *metronome.OverwriteRate {
+	var pMetronomeOverwriteRate *metronome.OverwriteRate
+	if source != nil {
+		var metronomeOverwriteRate metronome.OverwriteRate
+		metronomeOverwriteRate.RateType = (*source).RateType
+		metronomeOverwriteRate.Price = metronome.Decimal((*source).Price)
+		pMetronomeOverwriteRate = &metronomeOverwriteRate
+	}
+	return pMetronomeOverwriteRate
+}
+func (c *ContractConverterImpl) v1alpha1ScheduleItemToMetronomeScheduleItem(source v1alpha1.ScheduleItem) metronome.ScheduleItem {
+	var metronomeScheduleItem metronome.ScheduleItem
+	metronomeScheduleItem.Amount = metronome.Decimal(source.Amount)
+	metronomeScheduleItem.StartingAt = source.StartingAt
+	metronomeScheduleItem.EndingBefore = source.EndingBefore
+	return metronomeScheduleItem
+}
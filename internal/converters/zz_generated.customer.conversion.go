@@ -0,0 +1,124 @@
+// Code generated by github.com/jmattheis/goverter, DO NOT EDIT.
+//go:build !ignore_autogenerated
+
+package converters
+
+import (
+	v1alpha1 "github.com/redbackthomson/provider-metronome/apis/customer/v1alpha1"
+	metronome "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+// +k8s:deepcopy-gen=false
+type CustomerConverterImpl struct{}
+
+func (c *CustomerConverterImpl) FromBillingProviderConfiguration(source metronome.CustomerBillingProviderConfiguration) v1alpha1.BillingProviderConfiguration {
+	var v1alpha1BillingProviderConfiguration v1alpha1.BillingProviderConfiguration
+	v1alpha1BillingProviderConfiguration.BillingProvider = source.BillingProvider
+	v1alpha1BillingProviderConfiguration.DeliveryMethod = source.DeliveryMethod
+	v1alpha1BillingProviderConfiguration.Configuration = c.metronomeBillingConfigurationToV1alpha1BillingConfiguration(source.Configuration)
+	return v1alpha1BillingProviderConfiguration
+}
+func (c *CustomerConverterImpl) FromCustomer(source *metronome.GetCustomerData) *v1alpha1.ObservedCustomer {
+	var pV1alpha1ObservedCustomer *v1alpha1.ObservedCustomer
+	if source != nil {
+		var v1alpha1ObservedCustomer v1alpha1.ObservedCustomer
+		v1alpha1ObservedCustomer.ID = (*source).ID
+		v1alpha1ObservedCustomer.ExternalID = (*source).ExternalID
+		v1alpha1ObservedCustomer.Name = (*source).Name
+		if (*source).IngestAliases != nil {
+			v1alpha1ObservedCustomer.IngestAliases = make([]string, len((*source).IngestAliases))
+			for i := 0; i < len((*source).IngestAliases); i++ {
+				v1alpha1ObservedCustomer.IngestAliases[i] = (*source).IngestAliases[i]
+			}
+		}
+		if (*source).CustomFields != nil {
+			v1alpha1ObservedCustomer.CustomFields = make(map[string]string, len((*source).CustomFields))
+			for key, value := range (*source).CustomFields {
+				v1alpha1ObservedCustomer.CustomFields[key] = value
+			}
+		}
+		v1alpha1ObservedCustomer.SalesforceAccountID = (*source).CustomerConfig.SalesforceAccountID
+		pV1alpha1ObservedCustomer = &v1alpha1ObservedCustomer
+	}
+	return pV1alpha1ObservedCustomer
+}
+func (c *CustomerConverterImpl) FromCustomerSpec(source *v1alpha1.CustomerParameters) *metronome.CreateCustomerRequest {
+	var pMetronomeCreateCustomerRequest *metronome.CreateCustomerRequest
+	if source != nil {
+		var metronomeCreateCustomerRequest metronome.CreateCustomerRequest
+		if (*source).IngestAliases != nil {
+			metronomeCreateCustomerRequest.IngestAliases = make([]string, len((*source).IngestAliases))
+			for i := 0; i < len((*source).IngestAliases); i++ {
+				metronomeCreateCustomerRequest.IngestAliases[i] = (*source).IngestAliases[i]
+			}
+		}
+		metronomeCreateCustomerRequest.Name = (*source).Name
+		if (*source).BillingProviderConfigurations != nil {
+			metronomeCreateCustomerRequest.BillingProviderConfigurations = make([]metronome.BillingProviderConfiguration, len((*source).BillingProviderConfigurations))
+			for j := 0; j < len((*source).BillingProviderConfigurations); j++ {
+				metronomeCreateCustomerRequest.BillingProviderConfigurations[j] = c.v1alpha1BillingProviderConfigurationToMetronomeBillingProviderConfiguration((*source).BillingProviderConfigurations[j])
+			}
+		}
+		if (*source).CustomFields != nil {
+			metronomeCreateCustomerRequest.CustomFields = make(map[string]string, len((*source).CustomFields))
+			for key, value := range (*source).CustomFields {
+				metronomeCreateCustomerRequest.CustomFields[key] = value
+			}
+		}
+		pMetronomeCreateCustomerRequest = &metronomeCreateCustomerRequest
+	}
+	return pMetronomeCreateCustomerRequest
+}
+func (c *CustomerConverterImpl) ToCustomerSpec(source *metronome.CreateCustomerRequest) *v1alpha1.CustomerParameters {
+	var pV1alpha1CustomerParameters *v1alpha1.CustomerParameters
+	if source != nil {
+		var v1alpha1CustomerParameters v1alpha1.CustomerParameters
+		v1alpha1CustomerParameters.Name = (*source).Name
+		if (*source).IngestAliases != nil {
+			v1alpha1CustomerParameters.IngestAliases = make([]string, len((*source).IngestAliases))
+			for i := 0; i < len((*source).IngestAliases); i++ {
+				v1alpha1CustomerParameters.IngestAliases[i] = (*source).IngestAliases[i]
+			}
+		}
+		if (*source).CustomFields != nil {
+			v1alpha1CustomerParameters.CustomFields = make(map[string]string, len((*source).CustomFields))
+			for key, value := range (*source).CustomFields {
+				v1alpha1CustomerParameters.CustomFields[key] = value
+			}
+		}
+		if (*source).BillingProviderConfigurations != nil {
+			v1alpha1CustomerParameters.BillingProviderConfigurations = make([]v1alpha1.BillingProviderConfiguration, len((*source).BillingProviderConfigurations))
+			for j := 0; j < len((*source).BillingProviderConfigurations); j++ {
+				v1alpha1CustomerParameters.BillingProviderConfigurations[j] = c.metronomeBillingProviderConfigurationToV1alpha1BillingProviderConfiguration((*source).BillingProviderConfigurations[j])
+			}
+		}
+		pV1alpha1CustomerParameters = &v1alpha1CustomerParameters
+	}
+	return pV1alpha1CustomerParameters
+}
+func (c *CustomerConverterImpl) metronomeBillingConfigurationToV1alpha1BillingConfiguration(source metronome.BillingConfiguration) v1alpha1.BillingConfiguration {
+	var v1alpha1BillingConfiguration v1alpha1.BillingConfiguration
+	v1alpha1BillingConfiguration.StripeCustomerID = source.StripeCustomerID
+	v1alpha1BillingConfiguration.StripeCollectionMethod = source.StripeCollectionMethod
+	return v1alpha1BillingConfiguration
+}
+func (c *CustomerConverterImpl) metronomeBillingProviderConfigurationToV1alpha1BillingProviderConfiguration(source metronome.BillingProviderConfiguration) v1alpha1.BillingProviderConfiguration {
+	var v1alpha1BillingProviderConfiguration v1alpha1.BillingProviderConfiguration
+	v1alpha1BillingProviderConfiguration.BillingProvider = source.BillingProvider
+	v1alpha1BillingProviderConfiguration.DeliveryMethod = source.DeliveryMethod
+	v1alpha1BillingProviderConfiguration.Configuration = c.metronomeBillingConfigurationToV1alpha1BillingConfiguration(source.Configuration)
+	return v1alpha1BillingProviderConfiguration
+}
+func (c *CustomerConverterImpl) v1alpha1BillingConfigurationToMetronomeBillingConfiguration(source v1alpha1.BillingConfiguration) metronome.BillingConfiguration {
+	var metronomeBillingConfiguration metronome.BillingConfiguration
+	metronomeBillingConfiguration.StripeCustomerID = source.StripeCustomerID
+	metronomeBillingConfiguration.StripeCollectionMethod = source.StripeCollectionMethod
+	return metronomeBillingConfiguration
+}
+func (c *CustomerConverterImpl) v1alpha1BillingProviderConfigurationToMetronomeBillingProviderConfiguration(source v1alpha1.BillingProviderConfiguration) metronome.BillingProviderConfiguration {
+	var metronomeBillingProviderConfiguration metronome.BillingProviderConfiguration
+	metronomeBillingProviderConfiguration.BillingProvider = source.BillingProvider
+	metronomeBillingProviderConfiguration.DeliveryMethod = source.DeliveryMethod
+	metronomeBillingProviderConfiguration.Configuration = c.v1alpha1BillingConfigurationToMetronomeBillingConfiguration(source.Configuration)
+	return metronomeBillingProviderConfiguration
+}
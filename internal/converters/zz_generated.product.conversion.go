@@ -36,6 +36,50 @@ func (c *ProductConverterImpl) FromProduct(source *metronome.Product) *v1alpha1.
 	}
 	return pV1alpha1ObservedProduct
 }
+func (c *ProductConverterImpl) FromProductScheduleEntry(source *v1alpha1.ProductScheduleEntry) *metronome.UpdateProductRequest {
+	var pMetronomeUpdateProductRequest *metronome.UpdateProductRequest
+	if source != nil {
+		var metronomeUpdateProductRequest metronome.UpdateProductRequest
+		metronomeUpdateProductRequest.StartingAt = (*source).StartingAt
+		metronomeUpdateProductRequest.BillableMetricID = (*source).BillableMetricID
+		if (*source).CompositeProductIDs != nil {
+			metronomeUpdateProductRequest.CompositeProductIDs = make([]string, len((*source).CompositeProductIDs))
+			for i := 0; i < len((*source).CompositeProductIDs); i++ {
+				metronomeUpdateProductRequest.CompositeProductIDs[i] = (*source).CompositeProductIDs[i]
+			}
+		}
+		if (*source).CompositeTags != nil {
+			metronomeUpdateProductRequest.CompositeTags = make([]string, len((*source).CompositeTags))
+			for j := 0; j < len((*source).CompositeTags); j++ {
+				metronomeUpdateProductRequest.CompositeTags[j] = (*source).CompositeTags[j]
+			}
+		}
+		metronomeUpdateProductRequest.ExcludeFreeUsage = (*source).ExcludeFreeUsage
+		metronomeUpdateProductRequest.Name = (*source).Name
+		if (*source).PresentationGroupKey != nil {
+			metronomeUpdateProductRequest.PresentationGroupKey = make([]string, len((*source).PresentationGroupKey))
+			for k := 0; k < len((*source).PresentationGroupKey); k++ {
+				metronomeUpdateProductRequest.PresentationGroupKey[k] = (*source).PresentationGroupKey[k]
+			}
+		}
+		if (*source).PricingGroupKey != nil {
+			metronomeUpdateProductRequest.PricingGroupKey = make([]string, len((*source).PricingGroupKey))
+			for l := 0; l < len((*source).PricingGroupKey); l++ {
+				metronomeUpdateProductRequest.PricingGroupKey[l] = (*source).PricingGroupKey[l]
+			}
+		}
+		metronomeUpdateProductRequest.QuantityConversion = c.pV1alpha1QuantityConversionToPMetronomeQuantityConversion((*source).QuantityConversion)
+		metronomeUpdateProductRequest.QuantityRounding = c.pV1alpha1QuantityRoundingToPMetronomeQuantityRounding((*source).QuantityRounding)
+		if (*source).Tags != nil {
+			metronomeUpdateProductRequest.Tags = make([]string, len((*source).Tags))
+			for m := 0; m < len((*source).Tags); m++ {
+				metronomeUpdateProductRequest.Tags[m] = (*source).Tags[m]
+			}
+		}
+		pMetronomeUpdateProductRequest = &metronomeUpdateProductRequest
+	}
+	return pMetronomeUpdateProductRequest
+}
 func (c *ProductConverterImpl) FromProductSpec(source *v1alpha1.ProductParameters) *metronome.CreateProductRequest {
 	var pMetronomeCreateProductRequest *metronome.CreateProductRequest
 	if source != nil {
@@ -76,6 +120,12 @@ func (c *ProductConverterImpl) FromProductSpec(source *v1alpha1.ProductParameter
 				metronomeCreateProductRequest.Tags[m] = (*source).Tags[m]
 			}
 		}
+		if (*source).CustomFields != nil {
+			metronomeCreateProductRequest.CustomFields = make(map[string]string, len((*source).CustomFields))
+			for key, value := range (*source).CustomFields {
+				metronomeCreateProductRequest.CustomFields[key] = value
+			}
+		}
 		pMetronomeCreateProductRequest = &metronomeCreateProductRequest
 	}
 	return pMetronomeCreateProductRequest
@@ -121,6 +171,12 @@ func (c *ProductConverterImpl) FromProductToParameters(source *metronome.Product
 			}
 		}
 		v1alpha1ProductParameters.StartingAt = (*source).Current.StartingAt
+		if (*source).CustomFields != nil {
+			v1alpha1ProductParameters.CustomFields = make(map[string]string, len((*source).CustomFields))
+			for key, value := range (*source).CustomFields {
+				v1alpha1ProductParameters.CustomFields[key] = value
+			}
+		}
 		pV1alpha1ProductParameters = &v1alpha1ProductParameters
 	}
 	return pV1alpha1ProductParameters
@@ -150,6 +206,50 @@ func (c *ProductConverterImpl) ToProduct(source *v1alpha1.ObservedProduct) *metr
 	}
 	return pMetronomeProduct
 }
+func (c *ProductConverterImpl) ToProductScheduleEntry(source *metronome.ProductDetails) *v1alpha1.ProductScheduleEntry {
+	var pV1alpha1ProductScheduleEntry *v1alpha1.ProductScheduleEntry
+	if source != nil {
+		var v1alpha1ProductScheduleEntry v1alpha1.ProductScheduleEntry
+		v1alpha1ProductScheduleEntry.StartingAt = (*source).StartingAt
+		v1alpha1ProductScheduleEntry.BillableMetricID = (*source).BillableMetricID
+		v1alpha1ProductScheduleEntry.Name = (*source).Name
+		if (*source).CompositeProductIDs != nil {
+			v1alpha1ProductScheduleEntry.CompositeProductIDs = make([]string, len((*source).CompositeProductIDs))
+			for i := 0; i < len((*source).CompositeProductIDs); i++ {
+				v1alpha1ProductScheduleEntry.CompositeProductIDs[i] = (*source).CompositeProductIDs[i]
+			}
+		}
+		if (*source).CompositeTags != nil {
+			v1alpha1ProductScheduleEntry.CompositeTags = make([]string, len((*source).CompositeTags))
+			for j := 0; j < len((*source).CompositeTags); j++ {
+				v1alpha1ProductScheduleEntry.CompositeTags[j] = (*source).CompositeTags[j]
+			}
+		}
+		v1alpha1ProductScheduleEntry.ExcludeFreeUsage = (*source).ExcludeFreeUsage
+		if (*source).PresentationGroupKey != nil {
+			v1alpha1ProductScheduleEntry.PresentationGroupKey = make([]string, len((*source).PresentationGroupKey))
+			for k := 0; k < len((*source).PresentationGroupKey); k++ {
+				v1alpha1ProductScheduleEntry.PresentationGroupKey[k] = (*source).PresentationGroupKey[k]
+			}
+		}
+		if (*source).PricingGroupKey != nil {
+			v1alpha1ProductScheduleEntry.PricingGroupKey = make([]string, len((*source).PricingGroupKey))
+			for l := 0; l < len((*source).PricingGroupKey); l++ {
+				v1alpha1ProductScheduleEntry.PricingGroupKey[l] = (*source).PricingGroupKey[l]
+			}
+		}
+		v1alpha1ProductScheduleEntry.QuantityConversion = c.pMetronomeQuantityConversionToPV1alpha1QuantityConversion((*source).QuantityConversion)
+		v1alpha1ProductScheduleEntry.QuantityRounding = c.pMetronomeQuantityRoundingToPV1alpha1QuantityRounding((*source).QuantityRounding)
+		if (*source).Tags != nil {
+			v1alpha1ProductScheduleEntry.Tags = make([]string, len((*source).Tags))
+			for m := 0; m < len((*source).Tags); m++ {
+				v1alpha1ProductScheduleEntry.Tags[m] = (*source).Tags[m]
+			}
+		}
+		pV1alpha1ProductScheduleEntry = &v1alpha1ProductScheduleEntry
+	}
+	return pV1alpha1ProductScheduleEntry
+}
 func (c *ProductConverterImpl) ToProductSpec(source *metronome.CreateProductRequest) *v1alpha1.ProductParameters {
 	var pV1alpha1ProductParameters *v1alpha1.ProductParameters
 	if source != nil {
@@ -190,6 +290,12 @@ func (c *ProductConverterImpl) ToProductSpec(source *metronome.CreateProductRequ
 				v1alpha1ProductParameters.Tags[m] = (*source).Tags[m]
 			}
 		}
+		if (*source).CustomFields != nil {
+			v1alpha1ProductParameters.CustomFields = make(map[string]string, len((*source).CustomFields))
+			for key, value := range (*source).CustomFields {
+				v1alpha1ProductParameters.CustomFields[key] = value
+			}
+		}
 		pV1alpha1ProductParameters = &v1alpha1ProductParameters
 	}
 	return pV1alpha1ProductParameters
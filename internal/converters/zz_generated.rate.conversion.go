@@ -53,7 +53,7 @@ func (c *RateConverterImpl) FromRateSpec(source *v1alpha1.RateParameters) *metro
 		metronomeAddRateRequest.EndingBefore = (*source).EndingBefore
 		metronomeAddRateRequest.Entitled = (*source).Entitled
 		metronomeAddRateRequest.IsProrated = (*source).IsProrated
-		metronomeAddRateRequest.Price = (*source).Price
+		metronomeAddRateRequest.Price = metronome.Decimal((*source).Price)
 		if (*source).PricingGroupValues != nil {
 			metronomeAddRateRequest.PricingGroupValues = make(map[string]string, len((*source).PricingGroupValues))
 			for key, value := range (*source).PricingGroupValues {
@@ -84,7 +84,7 @@ func (c *RateConverterImpl) FromRateToParameters(source *metronome.Rate) *v1alph
 		v1alpha1RateParameters.StartingAt = (*source).StartingAt
 		v1alpha1RateParameters.Entitled = (*source).Entitled
 		v1alpha1RateParameters.RateType = (*source).Details.RateType
-		v1alpha1RateParameters.Price = (*source).Details.Price
+		v1alpha1RateParameters.Price = v1alpha1.Decimal((*source).Details.Price)
 		if (*source).PricingGroupValues != nil {
 			v1alpha1RateParameters.PricingGroupValues = make(map[string]string, len((*source).PricingGroupValues))
 			for key, value := range (*source).PricingGroupValues {
@@ -107,6 +107,14 @@ func (c *RateConverterImpl) FromRateToParameters(source *metronome.Rate) *v1alph
 	}
 	return pV1alpha1RateParameters
 }
+func (c *RateConverterImpl) FromRateToSuperseded(source metronome.Rate) v1alpha1.SupersededRate {
+	var v1alpha1SupersededRate v1alpha1.SupersededRate
+	v1alpha1SupersededRate.StartingAt = source.StartingAt
+	v1alpha1SupersededRate.EndingBefore = source.EndingBefore
+	v1alpha1SupersededRate.Details = c.metronomeRateDetailsToV1alpha1RateDetails(source.Details)
+	v1alpha1SupersededRate.CommitRate = c.pMetronomeCommitRateToPV1alpha1CommitRate(source.CommitRate)
+	return v1alpha1SupersededRate
+}
 func (c *RateConverterImpl) ToRate(source *v1alpha1.ObservedRate) *metronome.Rate {
 	var pMetronomeRate *metronome.Rate
 	if source != nil {
@@ -149,7 +157,7 @@ func (c *RateConverterImpl) ToRateSpec(source *metronome.AddRateRequest) *v1alph
 		v1alpha1RateParameters.StartingAt = (*source).StartingAt
 		v1alpha1RateParameters.Entitled = (*source).Entitled
 		v1alpha1RateParameters.RateType = (*source).RateType
-		v1alpha1RateParameters.Price = (*source).Price
+		v1alpha1RateParameters.Price = v1alpha1.Decimal((*source).Price)
 		if (*source).PricingGroupValues != nil {
 			v1alpha1RateParameters.PricingGroupValues = make(map[string]string, len((*source).PricingGroupValues))
 			for key, value := range (*source).PricingGroupValues {
@@ -183,7 +191,7 @@ func (c *RateConverterImpl) metronomeRateDetailsToV1alpha1RateDetails(source met
 	v1alpha1RateDetails.RateType = source.RateType
 	v1alpha1RateDetails.CreditType = c.metronomeCreditTypeToV1alpha1CreditType(source.CreditType)
 	v1alpha1RateDetails.IsProrated = source.IsProrated
-	v1alpha1RateDetails.Price = source.Price
+	v1alpha1RateDetails.Price = v1alpha1.Decimal(source.Price)
 	if source.PricingGroupValues != nil {
 		v1alpha1RateDetails.PricingGroupValues = make(map[string]string, len(source.PricingGroupValues))
 		for key, value := range source.PricingGroupValues {
@@ -202,7 +210,7 @@ func (c *RateConverterImpl) metronomeRateDetailsToV1alpha1RateDetails(source met
 }
 func (c *RateConverterImpl) metronomeTierToV1alpha1Tier(source metronome.Tier) v1alpha1.Tier {
 	var v1alpha1Tier v1alpha1.Tier
-	v1alpha1Tier.Price = source.Price
+	v1alpha1Tier.Price = v1alpha1.Decimal(source.Price)
 	v1alpha1Tier.Size = source.Size
 	return v1alpha1Tier
 }
@@ -211,7 +219,7 @@ func (c *RateConverterImpl) pMetronomeCommitRateToPV1alpha1CommitRate(source *me
 	if source != nil {
 		var v1alpha1CommitRate v1alpha1.CommitRate
 		v1alpha1CommitRate.RateType = (*source).RateType
-		v1alpha1CommitRate.Price = (*source).Price
+		v1alpha1CommitRate.Price = v1alpha1.Decimal((*source).Price)
 		if (*source).Tiers != nil {
 			v1alpha1CommitRate.Tiers = make([]v1alpha1.Tier, len((*source).Tiers))
 			for i := 0; i < len((*source).Tiers); i++ {
@@ -227,7 +235,7 @@ func (c *RateConverterImpl) pMetronomeCommitRateToV1alpha1CommitRate(source *met
 	if source != nil {
 		var v1alpha1CommitRate2 v1alpha1.CommitRate
 		v1alpha1CommitRate2.RateType = (*source).RateType
-		v1alpha1CommitRate2.Price = (*source).Price
+		v1alpha1CommitRate2.Price = v1alpha1.Decimal((*source).Price)
 		if (*source).Tiers != nil {
 			v1alpha1CommitRate2.Tiers = make([]v1alpha1.Tier, len((*source).Tiers))
 			for i := 0; i < len((*source).Tiers); i++ {
@@ -243,7 +251,7 @@ func (c *RateConverterImpl) pV1alpha1CommitRateToPMetronomeCommitRate(source *v1
 	if source != nil {
 		var metronomeCommitRate metronome.CommitRate
 		metronomeCommitRate.RateType = (*source).RateType
-		metronomeCommitRate.Price = (*source).Price
+		metronomeCommitRate.Price = metronome.Decimal((*source).Price)
 		if (*source).Tiers != nil {
 			metronomeCommitRate.Tiers = make([]metronome.Tier, len((*source).Tiers))
 			for i := 0; i < len((*source).Tiers); i++ {
@@ -257,7 +265,7 @@ func (c *RateConverterImpl) pV1alpha1CommitRateToPMetronomeCommitRate(source *v1
 func (c *RateConverterImpl) v1alpha1CommitRateToPMetronomeCommitRate(source v1alpha1.CommitRate) *metronome.CommitRate {
 	var metronomeCommitRate metronome.CommitRate
 	metronomeCommitRate.RateType = source.RateType
-	metronomeCommitRate.Price = source.Price
+	metronomeCommitRate.Price = metronome.Decimal(source.Price)
 	if source.Tiers != nil {
 		metronomeCommitRate.Tiers = make([]metronome.Tier, len(source.Tiers))
 		for i := 0; i < len(source.Tiers); i++ {
@@ -276,7 +284,7 @@ func (c *RateConverterImpl) v1alpha1RateDetailsToMetronomeRateDetails(source v1a
 	var metronomeRateDetails metronome.RateDetails
 	metronomeRateDetails.CreditType = c.v1alpha1CreditTypeToMetronomeCreditType(source.CreditType)
 	metronomeRateDetails.IsProrated = source.IsProrated
-	metronomeRateDetails.Price = source.Price
+	metronomeRateDetails.Price = metronome.Decimal(source.Price)
 	if source.PricingGroupValues != nil {
 		metronomeRateDetails.PricingGroupValues = make(map[string]string, len(source.PricingGroupValues))
 		for key, value := range source.PricingGroupValues {
@@ -296,7 +304,7 @@ func (c *RateConverterImpl) v1alpha1RateDetailsToMetronomeRateDetails(source v1a
 }
 func (c *RateConverterImpl) v1alpha1TierToMetronomeTier(source v1alpha1.Tier) metronome.Tier {
 	var metronomeTier metronome.Tier
-	metronomeTier.Price = source.Price
+	metronomeTier.Price = metronome.Decimal(source.Price)
 	metronomeTier.Size = source.Size
 	return metronomeTier
 }
@@ -0,0 +1,223 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration rewrites resources stored by earlier versions of the
+// provider in the form the current version writes them.
+package migration
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	contractv1alpha1 "github.com/redbackthomson/provider-metronome/apis/contract/v1alpha1"
+	ratev1alpha1 "github.com/redbackthomson/provider-metronome/apis/rate/v1alpha1"
+)
+
+const (
+	errListRates           = "cannot list rates"
+	errPatchRate           = "cannot migrate the prices of rate %s"
+	errPatchRateStatus     = "cannot migrate the observed prices of rate %s"
+	errListContracts       = "cannot list contracts"
+	errPatchContract       = "cannot migrate the prices of contract %s"
+	errPatchContractStatus = "cannot migrate the observed prices of contract %s"
+	errMigratePrices       = "cannot migrate prices"
+)
+
+// reasonMigratePrices is the reason of the events recorded on resources whose
+// prices can't be migrated.
+const reasonMigratePrices event.Reason = "CannotMigratePrices"
+
+// prices are the paths of the decimal fields of a kind, in its spec and in its
+// status. A * matches every item of a list.
+type prices struct {
+	spec   [][]string
+	status [][]string
+}
+
+var (
+	ratePrices = prices{
+		spec: [][]string{
+			{"spec", "forProvider", "price"},
+			{"spec", "forProvider", "tiers", "*", "price"},
+			{"spec", "forProvider", "commitRate", "price"},
+			{"spec", "forProvider", "commitRate", "tiers", "*", "price"},
+		},
+		status: [][]string{
+			{"status", "atProvider", "rate", "price"},
+			{"status", "atProvider", "rate", "tiers", "*", "price"},
+			{"status", "atProvider", "commitRate", "price"},
+			{"status", "atProvider", "commitRate", "tiers", "*", "price"},
+			{"status", "atProvider", "superseded", "*", "rate", "price"},
+			{"status", "atProvider", "superseded", "*", "rate", "tiers", "*", "price"},
+			{"status", "atProvider", "superseded", "*", "commitRate", "price"},
+			{"status", "atProvider", "superseded", "*", "commitRate", "tiers", "*", "price"},
+		},
+	}
+	contractPrices = prices{
+		spec: [][]string{
+			{"spec", "forProvider", "commits", "*", "accessSchedule", "scheduleItems", "*", "amount"},
+			{"spec", "forProvider", "overrides", "*", "overwriteRate", "price"},
+		},
+		status: [][]string{
+			{"status", "atProvider", "commits", "*", "accessSchedule", "scheduleItems", "*", "amount"},
+			{"status", "atProvider", "overrides", "*", "overwriteRate", "price"},
+		},
+	}
+)
+
+// migrateBackoff is how often Prices retries migrations that fail.
+var migrateBackoff = wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: 6}
+
+// Prices runs RatePrices and ContractPrices, retrying them with backoff while
+// any fail. It returns an error if they still fail after the last retry. A
+// warning event is recorded on every resource that couldn't be patched, and
+// the resources are migrated the next time the provider starts.
+func Prices(ctx context.Context, kube client.Client, record event.Recorder, log logging.Logger) error {
+	var err error
+	werr := wait.ExponentialBackoffWithContext(ctx, migrateBackoff, func(ctx context.Context) (bool, error) {
+		err = kerrors.NewAggregate([]error{RatePrices(ctx, kube, record, log), ContractPrices(ctx, kube, record, log)})
+		if err != nil {
+			log.Debug("Cannot migrate prices", "error", err)
+		}
+		return err == nil, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, errMigratePrices)
+	}
+	return errors.Wrap(werr, errMigratePrices)
+}
+
+// RatePrices rewrites the prices of Rates that were stored as JSON numbers,
+// before prices were decimal strings, in both their spec and their status.
+// Rates with numeric prices can still be read, but are rejected by the CRD
+// schema when they are next updated.
+//
+// A price is rewritten as the shortest decimal that reads back as the stored
+// number, which is what was written unless it had more digits than a float
+// holds.
+func RatePrices(ctx context.Context, kube client.Client, record event.Recorder, log logging.Logger) error {
+	gvk := ratev1alpha1.RateGroupVersionKind.GroupVersion().WithKind(ratev1alpha1.RateKind + "List")
+	return migrate(ctx, kube, record, log, gvk, ratePrices, errListRates, errPatchRate, errPatchRateStatus)
+}
+
+// ContractPrices rewrites the commit amounts and override prices of Contracts
+// that were stored as JSON numbers, in the same way as RatePrices.
+func ContractPrices(ctx context.Context, kube client.Client, record event.Recorder, log logging.Logger) error {
+	gvk := contractv1alpha1.ContractGroupVersionKind.GroupVersion().WithKind(contractv1alpha1.ContractKind + "List")
+	return migrate(ctx, kube, record, log, gvk, contractPrices, errListContracts, errPatchContract, errPatchContractStatus)
+}
+
+// migrate patches the resources of a kind that have numbers at any of the
+// given paths. The status is patched separately, through its subresource.
+func migrate(ctx context.Context, kube client.Client, record event.Recorder, log logging.Logger, list schema.GroupVersionKind, p prices, errList, errPatch, errPatchStatus string) error {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(list)
+	if err := kube.List(ctx, l); err != nil {
+		return errors.Wrap(err, errList)
+	}
+
+	var errs []error
+	for i := range l.Items {
+		u := &l.Items[i]
+		name := u.GetName()
+		orig := u.DeepCopy()
+		if migratePrices(u.Object, p.spec) {
+			if err := kube.Patch(ctx, u, client.MergeFrom(orig)); err != nil {
+				err = errors.Wrapf(err, errPatch, name)
+				record.Event(u, event.Warning(reasonMigratePrices, err))
+				errs = append(errs, err)
+				continue
+			}
+			log.Info("Migrated prices to decimal strings", "kind", u.GetKind(), "name", name)
+		}
+		orig = u.DeepCopy()
+		if migratePrices(u.Object, p.status) {
+			if err := kube.Status().Patch(ctx, u, client.MergeFrom(orig)); err != nil {
+				err = errors.Wrapf(err, errPatchStatus, name)
+				record.Event(u, event.Warning(reasonMigratePrices, err))
+				errs = append(errs, err)
+				continue
+			}
+			log.Info("Migrated observed prices to decimal strings", "kind", u.GetKind(), "name", name)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// migratePrices rewrites the numbers at the given paths of an object, and
+// reports whether any were found.
+func migratePrices(obj map[string]any, paths [][]string) bool {
+	changed := false
+	for _, p := range paths {
+		changed = migrateField(obj, p) || changed
+	}
+	return changed
+}
+
+// migrateField rewrites the number at a path below v, or every number the
+// path matches when it includes a *.
+func migrateField(v any, path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	switch o := v.(type) {
+	case map[string]any:
+		child, ok := o[path[0]]
+		if !ok {
+			return false
+		}
+		if len(path) > 1 {
+			return migrateField(child, path[1:])
+		}
+		s, ok := decimal(child)
+		if ok {
+			o[path[0]] = s
+		}
+		return ok
+	case []any:
+		if path[0] != "*" || len(path) == 1 {
+			return false
+		}
+		changed := false
+		for _, item := range o {
+			changed = migrateField(item, path[1:]) || changed
+		}
+		return changed
+	}
+	return false
+}
+
+// decimal returns the decimal string of a number decoded from JSON.
+func decimal(v any) (string, bool) {
+	switch n := v.(type) {
+	case int64:
+		return strconv.FormatInt(n, 10), true
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64), true
+	}
+	return "", false
+}
@@ -0,0 +1,320 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	errBoom = errors.New("boom")
+)
+
+// recorder records the reason of every event, and the name of the object it
+// was recorded on.
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) Event(obj runtime.Object, e event.Event) {
+	r.events = append(r.events, obj.(client.Object).GetName()+": "+string(e.Reason))
+}
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+func forProvider(params map[string]any) map[string]any {
+	return map[string]any{"spec": map[string]any{"forProvider": params}}
+}
+
+func Test_MigratePrices(t *testing.T) {
+	type want struct {
+		obj     map[string]any
+		changed bool
+	}
+	cases := map[string]struct {
+		obj   map[string]any
+		paths [][]string
+		want
+	}{
+		"AlreadyDecimals": {
+			paths: ratePrices.spec,
+			obj:   forProvider(map[string]any{"price": "0.0001", "tiers": []any{map[string]any{"price": "1"}}}),
+			want: want{
+				obj: forProvider(map[string]any{"price": "0.0001", "tiers": []any{map[string]any{"price": "1"}}}),
+			},
+		},
+		"NoPrices": {
+			paths: ratePrices.spec,
+			obj:   forProvider(map[string]any{"rateType": "CUSTOM"}),
+			want: want{
+				obj: forProvider(map[string]any{"rateType": "CUSTOM"}),
+			},
+		},
+		"RateNumbers": {
+			paths: ratePrices.spec,
+			obj: forProvider(map[string]any{
+				"price": int64(120),
+				"tiers": []any{
+					map[string]any{"price": 0.0001, "size": int64(100)},
+					map[string]any{"price": "0.00005"},
+				},
+				"commitRate": map[string]any{
+					"price": 1e-10,
+					"tiers": []any{map[string]any{"price": 2.5}},
+				},
+			}),
+			want: want{
+				obj: forProvider(map[string]any{
+					"price": "120",
+					"tiers": []any{
+						map[string]any{"price": "0.0001", "size": int64(100)},
+						map[string]any{"price": "0.00005"},
+					},
+					"commitRate": map[string]any{
+						"price": "0.0000000001",
+						"tiers": []any{map[string]any{"price": "2.5"}},
+					},
+				}),
+				changed: true,
+			},
+		},
+		"ContractNumbers": {
+			paths: contractPrices.spec,
+			obj: forProvider(map[string]any{
+				"commits": []any{
+					map[string]any{"accessSchedule": map[string]any{"scheduleItems": []any{
+						map[string]any{"amount": int64(100000)},
+						map[string]any{"amount": 0.5},
+					}}},
+					map[string]any{"type": "POSTPAID"},
+				},
+				"overrides": []any{
+					map[string]any{"overwriteRate": map[string]any{"rateType": "FLAT", "price": 12.25}},
+					map[string]any{"multiplier": 0.9},
+				},
+			}),
+			want: want{
+				obj: forProvider(map[string]any{
+					"commits": []any{
+						map[string]any{"accessSchedule": map[string]any{"scheduleItems": []any{
+							map[string]any{"amount": "100000"},
+							map[string]any{"amount": "0.5"},
+						}}},
+						map[string]any{"type": "POSTPAID"},
+					},
+					"overrides": []any{
+						map[string]any{"overwriteRate": map[string]any{"rateType": "FLAT", "price": "12.25"}},
+						map[string]any{"multiplier": 0.9},
+					},
+				}),
+				changed: true,
+			},
+		},
+		"ObservedRateNumbers": {
+			paths: ratePrices.status,
+			obj: map[string]any{"status": map[string]any{"atProvider": map[string]any{
+				"rate":       map[string]any{"price": 1.5, "tiers": []any{map[string]any{"price": int64(3)}}},
+				"commitRate": map[string]any{"price": 0.25},
+				"superseded": []any{
+					map[string]any{
+						"rate":       map[string]any{"price": int64(1)},
+						"commitRate": map[string]any{"tiers": []any{map[string]any{"price": 0.5}}},
+					},
+				},
+			}}},
+			want: want{
+				obj: map[string]any{"status": map[string]any{"atProvider": map[string]any{
+					"rate":       map[string]any{"price": "1.5", "tiers": []any{map[string]any{"price": "3"}}},
+					"commitRate": map[string]any{"price": "0.25"},
+					"superseded": []any{
+						map[string]any{
+							"rate":       map[string]any{"price": "1"},
+							"commitRate": map[string]any{"tiers": []any{map[string]any{"price": "0.5"}}},
+						},
+					},
+				}}},
+				changed: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := migratePrices(tc.obj, tc.paths)
+			if changed != tc.want.changed {
+				t.Errorf("migratePrices(...): want changed %t, got %t", tc.want.changed, changed)
+			}
+			if diff := cmp.Diff(tc.want.obj, tc.obj); diff != "" {
+				t.Errorf("migratePrices(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_RatePrices(t *testing.T) {
+	rate := func(name string, price, observed any) unstructured.Unstructured {
+		u := unstructured.Unstructured{Object: forProvider(map[string]any{"price": price})}
+		u.Object["status"] = map[string]any{"atProvider": map[string]any{"rate": map[string]any{"price": observed}}}
+		u.SetName(name)
+		return u
+	}
+	patch := func(patched *[]string, suffix string) func(_ context.Context, obj client.Object) error {
+		return func(_ context.Context, obj client.Object) error {
+			*patched = append(*patched, obj.GetName()+suffix)
+			return nil
+		}
+	}
+
+	type want struct {
+		err     error
+		patched []string
+		events  []string
+	}
+	cases := map[string]struct {
+		kube func(patched *[]string) client.Client
+		want
+	}{
+		"FailedToList": {
+			kube: func(_ *[]string) client.Client {
+				return &test.MockClient{MockList: test.NewMockListFn(errBoom)}
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListRates),
+			},
+		},
+		"PatchesNumericPrices": {
+			kube: func(patched *[]string) client.Client {
+				return &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+						obj.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{
+							rate("number", 1.5, "1.5"),
+							rate("decimal", "1.5", "1.5"),
+							rate("observed", "1.5", 1.5),
+						}
+						return nil
+					},
+					MockPatch: func(ctx context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						return patch(patched, "")(ctx, obj)
+					},
+					MockStatusPatch: func(ctx context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+						return patch(patched, "/status")(ctx, obj)
+					},
+				}
+			},
+			want: want{
+				patched: []string{"number", "observed/status"},
+			},
+		},
+		"FailedToPatch": {
+			kube: func(patched *[]string) client.Client {
+				return &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+						obj.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{rate("number", int64(2), "2")}
+						return nil
+					},
+					MockPatch: test.NewMockPatchFn(errBoom),
+				}
+			},
+			want: want{
+				err:    kerrors.NewAggregate([]error{errors.Wrapf(errBoom, errPatchRate, "number")}),
+				events: []string{"number: " + string(reasonMigratePrices)},
+			},
+		},
+		"FailedToPatchStatus": {
+			kube: func(patched *[]string) client.Client {
+				return &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+						obj.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{rate("number", int64(2), int64(2))}
+						return nil
+					},
+					MockPatch:       test.NewMockPatchFn(nil),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(errBoom),
+				}
+			},
+			want: want{
+				err:    kerrors.NewAggregate([]error{errors.Wrapf(errBoom, errPatchRateStatus, "number")}),
+				events: []string{"number: " + string(reasonMigratePrices)},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var patched []string
+			record := &recorder{}
+			err := RatePrices(context.Background(), tc.kube(&patched), record, logging.NewNopLogger())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("RatePrices(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.patched, patched); diff != "" {
+				t.Errorf("RatePrices(...): -want patched, +got patched: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.events, record.events); diff != "" {
+				t.Errorf("RatePrices(...): -want events, +got events: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_Prices(t *testing.T) {
+	migrateBackoff.Duration, migrateBackoff.Steps = time.Millisecond, 3
+
+	type want struct {
+		err   error
+		lists int
+	}
+	cases := map[string]struct {
+		fails int
+		want
+	}{
+		"Succeeds": {
+			want: want{lists: 2},
+		},
+		"RetriesFailures": {
+			fails: 1,
+			want:  want{lists: 4},
+		},
+		"StillFails": {
+			fails: 10,
+			want: want{
+				err: errors.Wrap(kerrors.NewAggregate([]error{
+					errors.Wrap(errBoom, errListRates),
+					errors.Wrap(errBoom, errListContracts),
+				}), errMigratePrices),
+				lists: 6,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			lists := 0
+			kube := &test.MockClient{
+				MockList: func(_ context.Context, _ client.ObjectList, _ ...client.ListOption) error {
+					lists++
+					if lists <= tc.fails*2 {
+						return errBoom
+					}
+					return nil
+				},
+			}
+			err := Prices(context.Background(), kube, event.NewNopRecorder(), logging.NewNopLogger())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Prices(...): -want error, +got error: %s", diff)
+			}
+			if lists != tc.want.lists {
+				t.Errorf("Prices(...): want %d lists, got %d", tc.want.lists, lists)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	billablemetricv1alpha1 "github.com/redbackthomson/provider-metronome/apis/billablemetric/v1alpha1"
+	customfieldkeyv1alpha1 "github.com/redbackthomson/provider-metronome/apis/customfieldkey/v1alpha1"
+	productv1alpha1 "github.com/redbackthomson/provider-metronome/apis/product/v1alpha1"
+	ratev1alpha1 "github.com/redbackthomson/provider-metronome/apis/rate/v1alpha1"
+	ratecardv1alpha1 "github.com/redbackthomson/provider-metronome/apis/ratecard/v1alpha1"
+	metronomeClient "github.com/redbackthomson/provider-metronome/internal/clients/metronome"
+)
+
+const (
+	errHourTimestamp = "must be an RFC 3339 timestamp on an hour boundary"
+	errTimestamp     = "must be an RFC 3339 timestamp"
+	errDecimal       = "must be a decimal number"
+)
+
+var (
+	rateTypes       = []string{"FLAT", "PERCENTAGE", "SUBSCRIPTION", "TIERED", "CUSTOM"}
+	productTypes    = []string{"USAGE", "SUBSCRIPTION", "COMPOSITE", "FIXED", "PRO_SERVICE"}
+	operations      = []string{"MULTIPLY", "DIVIDE"}
+	roundingMethods = []string{"ROUND_UP", "ROUND_DOWN", "ROUND_HALF_UP"}
+
+	aggregationTypes = []string{
+		metronomeClient.AggregationCount,
+		metronomeClient.AggregationLatest,
+		metronomeClient.AggregationMax,
+		metronomeClient.AggregationSum,
+		metronomeClient.AggregationUnique,
+	}
+
+	customFieldEntities = []string{
+		"alert",
+		"billable_metric",
+		"charge",
+		"commit",
+		"contract",
+		"contract_credit",
+		"contract_product",
+		"credit_grant",
+		"customer",
+		"customer_plan",
+		"invoice",
+		"plan",
+		"product",
+		"professional_service",
+		"rate_card",
+		"scheduled_charge",
+		"subscription",
+	}
+)
+
+var forProvider = field.NewPath("spec", "forProvider")
+
+// ValidateRate returns the problems with the spec and annotations of a Rate.
+func ValidateRate(r *ratev1alpha1.Rate) field.ErrorList {
+	p := r.Spec.ForProvider
+	var errs field.ErrorList
+
+	if p.RateCardID == "" && p.RateCardRef == nil && p.RateCardSelector == nil {
+		errs = append(errs, field.Required(forProvider.Child("rateCardId"), "one of rateCardId, rateCardRef or rateCardSelector is required"))
+	}
+	if p.ProductID == "" && p.ProductRef == nil && p.ProductSelector == nil {
+		errs = append(errs, field.Required(forProvider.Child("productId"), "one of productId, productRef or productSelector is required"))
+	}
+
+	start, err := requireHour(forProvider.Child("startingAt"), p.StartingAt)
+	errs = appendErr(errs, err)
+	if p.EndingBefore != "" {
+		path := forProvider.Child("endingBefore")
+		end, err := parseHour(path, p.EndingBefore)
+		errs = appendErr(errs, err)
+		if err == nil && !start.IsZero() && !end.After(start) {
+			errs = append(errs, field.Invalid(path, p.EndingBefore, "must be after startingAt"))
+		}
+	}
+
+	errs = append(errs, validatePricing(forProvider, p.RateType, p.Price, p.Tiers)...)
+	if c := p.CommitRate; c != nil {
+		errs = append(errs, validatePricing(forProvider.Child("commitRate"), c.RateType, c.Price, c.Tiers)...)
+	}
+
+	annotations := field.NewPath("metadata", "annotations")
+	for _, k := range []string{ratev1alpha1.AnnotationKeyChangeAt, ratev1alpha1.AnnotationKeyEndAt} {
+		if v, ok := r.GetAnnotations()[k]; ok {
+			_, err := parseHour(annotations.Key(k), v)
+			errs = appendErr(errs, err)
+		}
+	}
+	return errs
+}
+
+// validatePricing returns the problems with the rate type, price and tiers of
+// a rate, or of its commit rate.
+func validatePricing(path *field.Path, rateType string, price ratev1alpha1.Decimal, tiers []ratev1alpha1.Tier) field.ErrorList {
+	var errs field.ErrorList
+
+	p, ok := price.Rat()
+	if !ok {
+		errs = append(errs, field.Invalid(path.Child("price"), price, errDecimal))
+	}
+
+	typ := strings.ToUpper(rateType)
+	switch typ {
+	case "FLAT", "SUBSCRIPTION":
+		if ok && p.Sign() < 0 {
+			errs = append(errs, field.Invalid(path.Child("price"), price, "must not be negative"))
+		}
+	case "PERCENTAGE":
+		if ok && (p.Sign() < 0 || p.Cmp(big.NewRat(1, 1)) > 0) {
+			errs = append(errs, field.Invalid(path.Child("price"), price, "must be a fraction between 0 and 1"))
+		}
+	case "TIERED":
+		if len(tiers) == 0 {
+			errs = append(errs, field.Required(path.Child("tiers"), "tiers are required for TIERED rates"))
+		}
+	case "CUSTOM":
+	default:
+		errs = append(errs, field.NotSupported(path.Child("rateType"), rateType, rateTypes))
+	}
+
+	// tiers must be listed in order of increasing size, and only the last
+	// tier may be unbounded
+	for i, t := range tiers {
+		tp := path.Child("tiers").Index(i)
+		if p, ok := t.Price.Rat(); !ok {
+			errs = append(errs, field.Invalid(tp.Child("price"), t.Price, errDecimal))
+		} else if p.Sign() < 0 {
+			errs = append(errs, field.Invalid(tp.Child("price"), t.Price, "must not be negative"))
+		}
+		switch {
+		case t.Size < 0:
+			errs = append(errs, field.Invalid(tp.Child("size"), t.Size, "must not be negative"))
+		case t.Size == 0 && i < len(tiers)-1:
+			errs = append(errs, field.Required(tp.Child("size"), "only the last tier may omit its size"))
+		case t.Size > 0 && i > 0 && t.Size <= tiers[i-1].Size:
+			errs = append(errs, field.Invalid(tp.Child("size"), t.Size, "tier sizes out of order"))
+		}
+	}
+	return errs
+}
+
+// ValidateProduct returns the problems with the spec of a Product.
+func ValidateProduct(p *productv1alpha1.Product) field.ErrorList {
+	fp := p.Spec.ForProvider
+	var errs field.ErrorList
+
+	if fp.Name == "" {
+		errs = append(errs, field.Required(forProvider.Child("name"), ""))
+	}
+	typ := strings.ToUpper(fp.Type)
+	switch {
+	case !slices.Contains(productTypes, typ):
+		errs = append(errs, field.NotSupported(forProvider.Child("type"), fp.Type, productTypes))
+	case typ == "USAGE" && fp.BillableMetricID == "" && fp.BillableMetricRef == nil && fp.BillableMetricSelector == nil:
+		errs = append(errs, field.Required(forProvider.Child("billableMetricId"), "a billable metric is required for USAGE products"))
+	}
+
+	if fp.StartingAt != "" {
+		_, err := parseHour(forProvider.Child("startingAt"), fp.StartingAt)
+		errs = appendErr(errs, err)
+	} else if fp.UpdatePolicy == productv1alpha1.UpdatePolicyExplicit {
+		errs = append(errs, field.Required(forProvider.Child("startingAt"), "startingAt is required by the Explicit update policy"))
+	}
+	errs = append(errs, validateQuantity(forProvider, fp.QuantityConversion, fp.QuantityRounding)...)
+
+	for i, e := range fp.Schedule {
+		path := forProvider.Child("schedule").Index(i)
+		_, err := requireHour(path.Child("startingAt"), e.StartingAt)
+		errs = appendErr(errs, err)
+		errs = append(errs, validateQuantity(path, e.QuantityConversion, e.QuantityRounding)...)
+	}
+	return errs
+}
+
+func validateQuantity(path *field.Path, c *productv1alpha1.QuantityConversion, r *productv1alpha1.QuantityRounding) field.ErrorList {
+	var errs field.ErrorList
+	if c != nil {
+		if c.ConversionFactor <= 0 {
+			errs = append(errs, field.Invalid(path.Child("quantityConversion", "conversionFactor"), c.ConversionFactor, "must be positive"))
+		}
+		if !slices.Contains(operations, c.Operation) {
+			errs = append(errs, field.NotSupported(path.Child("quantityConversion", "operation"), c.Operation, operations))
+		}
+	}
+	if r != nil {
+		if r.DecimalPlaces < 0 {
+			errs = append(errs, field.Invalid(path.Child("quantityRounding", "decimalPlaces"), r.DecimalPlaces, "must not be negative"))
+		}
+		if !slices.Contains(roundingMethods, r.RoundingMethod) {
+			errs = append(errs, field.NotSupported(path.Child("quantityRounding", "roundingMethod"), r.RoundingMethod, roundingMethods))
+		}
+	}
+	return errs
+}
+
+// ValidateBillableMetric returns the problems with the spec of a
+// BillableMetric.
+func ValidateBillableMetric(m *billablemetricv1alpha1.BillableMetric) field.ErrorList {
+	fp := m.Spec.ForProvider
+	var errs field.ErrorList
+
+	if fp.Name == "" {
+		errs = append(errs, field.Required(forProvider.Child("name"), ""))
+	}
+	// metrics defined by SQL don't aggregate events themselves
+	if fp.SQL == "" {
+		agg := strings.ToLower(string(fp.AggregationType))
+		if !slices.Contains(aggregationTypes, agg) {
+			errs = append(errs, field.NotSupported(forProvider.Child("aggregationType"), fp.AggregationType, aggregationTypes))
+		} else if agg != metronomeClient.AggregationCount && fp.AggregationKey == "" {
+			errs = append(errs, field.Required(forProvider.Child("aggregationKey"), "an aggregation key is required unless the aggregation type is count"))
+		}
+	}
+	for i, f := range fp.PropertyFilters {
+		if f.Name == "" {
+			errs = append(errs, field.Required(forProvider.Child("propertyFilters").Index(i).Child("name"), ""))
+		}
+	}
+
+	if r := m.Spec.Replacement; r != nil && r.StartingAt != "" {
+		if _, err := time.Parse(time.RFC3339, r.StartingAt); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "replacement", "startingAt"), r.StartingAt, errTimestamp))
+		}
+	}
+	return errs
+}
+
+// ValidateRateCard returns the problems with the spec of a RateCard.
+func ValidateRateCard(rc *ratecardv1alpha1.RateCard) field.ErrorList {
+	fp := rc.Spec.ForProvider
+	var errs field.ErrorList
+
+	if fp.Name == "" {
+		errs = append(errs, field.Required(forProvider.Child("name"), ""))
+	}
+	for i, a := range fp.Aliases {
+		if a.Name == "" {
+			errs = append(errs, field.Required(forProvider.Child("aliases").Index(i).Child("name"), ""))
+		}
+	}
+	for i, c := range fp.CreditTypeConversions {
+		path := forProvider.Child("creditTypeConversions").Index(i)
+		if c.CustomCreditTypeID == "" {
+			errs = append(errs, field.Required(path.Child("customCreditTypeId"), ""))
+		}
+		if f, err := strconv.ParseFloat(c.FiatPerCustomCredit, 64); err != nil || f <= 0 {
+			errs = append(errs, field.Invalid(path.Child("fiatPerCustomCredit"), c.FiatPerCustomCredit, "must be a positive number"))
+		}
+	}
+	return errs
+}
+
+// ValidateCustomFieldKey returns the problems with the spec of a
+// CustomFieldKey.
+func ValidateCustomFieldKey(k *customfieldkeyv1alpha1.CustomFieldKey) field.ErrorList {
+	fp := k.Spec.ForProvider
+	var errs field.ErrorList
+
+	if !slices.Contains(customFieldEntities, fp.Entity) {
+		errs = append(errs, field.NotSupported(forProvider.Child("entity"), fp.Entity, customFieldEntities))
+	}
+	if fp.Key == "" {
+		errs = append(errs, field.Required(forProvider.Child("key"), ""))
+	}
+	return errs
+}
+
+// requireHour parses a required RFC 3339 timestamp on an hour boundary.
+func requireHour(path *field.Path, value string) (time.Time, *field.Error) {
+	if value == "" {
+		return time.Time{}, field.Required(path, "")
+	}
+	return parseHour(path, value)
+}
+
+// parseHour parses an RFC 3339 timestamp on an hour boundary.
+func parseHour(path *field.Path, value string) (time.Time, *field.Error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil || !t.Truncate(time.Hour).Equal(t) {
+		return time.Time{}, field.Invalid(path, value, errHourTimestamp)
+	}
+	return t, nil
+}
+
+func appendErr(errs field.ErrorList, err *field.Error) field.ErrorList {
+	if err == nil {
+		return errs
+	}
+	return append(errs, err)
+}
@@ -0,0 +1,297 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	billablemetricv1alpha1 "github.com/redbackthomson/provider-metronome/apis/billablemetric/v1alpha1"
+	customfieldkeyv1alpha1 "github.com/redbackthomson/provider-metronome/apis/customfieldkey/v1alpha1"
+	productv1alpha1 "github.com/redbackthomson/provider-metronome/apis/product/v1alpha1"
+	ratev1alpha1 "github.com/redbackthomson/provider-metronome/apis/rate/v1alpha1"
+	ratecardv1alpha1 "github.com/redbackthomson/provider-metronome/apis/ratecard/v1alpha1"
+)
+
+const (
+	testStartingAt = "2025-01-01T00:00:00Z"
+)
+
+func rate(annotations map[string]string, fn func(p *ratev1alpha1.RateParameters)) *ratev1alpha1.Rate {
+	r := &ratev1alpha1.Rate{
+		ObjectMeta: metav1.ObjectMeta{Name: "rate", Annotations: annotations},
+		Spec: ratev1alpha1.RateSpec{
+			ForProvider: ratev1alpha1.RateParameters{
+				RateCardRef: &xpv1.Reference{Name: "card"},
+				ProductID:   "product",
+				StartingAt:  testStartingAt,
+				RateType:    "FLAT",
+				Price:       "100",
+			},
+		},
+	}
+	if fn != nil {
+		fn(&r.Spec.ForProvider)
+	}
+	return r
+}
+
+func Test_ValidateRate(t *testing.T) {
+	cases := map[string]struct {
+		rate *ratev1alpha1.Rate
+		want field.ErrorList
+	}{
+		"Valid": {
+			rate: rate(nil, nil),
+		},
+		"MissingReferences": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) {
+				p.RateCardRef = nil
+				p.ProductID = ""
+			}),
+			want: field.ErrorList{
+				field.Required(forProvider.Child("rateCardId"), "one of rateCardId, rateCardRef or rateCardSelector is required"),
+				field.Required(forProvider.Child("productId"), "one of productId, productRef or productSelector is required"),
+			},
+		},
+		"UnsupportedRateType": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) { p.RateType = "flag" }),
+			want: field.ErrorList{
+				field.NotSupported(forProvider.Child("rateType"), "flag", rateTypes),
+			},
+		},
+		"NegativeFlatPrice": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) { p.Price = "-1" }),
+			want: field.ErrorList{
+				field.Invalid(forProvider.Child("price"), ratev1alpha1.Decimal("-1"), "must not be negative"),
+			},
+		},
+		"PercentageAboveOne": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) {
+				p.RateType = "PERCENTAGE"
+				p.Price = "10"
+			}),
+			want: field.ErrorList{
+				field.Invalid(forProvider.Child("price"), ratev1alpha1.Decimal("10"), "must be a fraction between 0 and 1"),
+			},
+		},
+		"TieredWithoutTiers": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) { p.RateType = "TIERED" }),
+			want: field.ErrorList{
+				field.Required(forProvider.Child("tiers"), "tiers are required for TIERED rates"),
+			},
+		},
+		"UnboundedTierNotLast": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) {
+				p.RateType = "TIERED"
+				p.Tiers = []ratev1alpha1.Tier{{Price: "10"}, {Price: "5", Size: 100}}
+			}),
+			want: field.ErrorList{
+				field.Required(forProvider.Child("tiers").Index(0).Child("size"), "only the last tier may omit its size"),
+			},
+		},
+		"TiersOutOfOrder": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) {
+				p.RateType = "TIERED"
+				p.Tiers = []ratev1alpha1.Tier{{Price: "10", Size: 100}, {Price: "8", Size: 50}, {Price: "5"}}
+			}),
+			want: field.ErrorList{
+				field.Invalid(forProvider.Child("tiers").Index(1).Child("size"), float64(50), "tier sizes out of order"),
+			},
+		},
+		"InvalidCommitRate": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) {
+				p.CommitRate = &ratev1alpha1.CommitRate{RateType: "FLAT", Price: "-5"}
+			}),
+			want: field.ErrorList{
+				field.Invalid(forProvider.Child("commitRate", "price"), ratev1alpha1.Decimal("-5"), "must not be negative"),
+			},
+		},
+		"StartingAtNotOnHour": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) { p.StartingAt = "2025-01-01T00:30:00Z" }),
+			want: field.ErrorList{
+				field.Invalid(forProvider.Child("startingAt"), "2025-01-01T00:30:00Z", errHourTimestamp),
+			},
+		},
+		"EndingBeforeStart": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) { p.EndingBefore = "2024-01-01T00:00:00Z" }),
+			want: field.ErrorList{
+				field.Invalid(forProvider.Child("endingBefore"), "2024-01-01T00:00:00Z", "must be after startingAt"),
+			},
+		},
+		"InvalidChangeAt": {
+			rate: rate(map[string]string{ratev1alpha1.AnnotationKeyChangeAt: "tomorrow"}, nil),
+			want: field.ErrorList{
+				field.Invalid(field.NewPath("metadata", "annotations").Key(ratev1alpha1.AnnotationKeyChangeAt), "tomorrow", errHourTimestamp),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ValidateRate(tc.rate)); diff != "" {
+				t.Errorf("ValidateRate(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func product(fn func(p *productv1alpha1.ProductParameters)) *productv1alpha1.Product {
+	p := &productv1alpha1.Product{
+		Spec: productv1alpha1.ProductSpec{
+			ForProvider: productv1alpha1.ProductParameters{
+				Name:             "product",
+				Type:             "USAGE",
+				BillableMetricID: "metric",
+			},
+		},
+	}
+	if fn != nil {
+		fn(&p.Spec.ForProvider)
+	}
+	return p
+}
+
+func Test_ValidateProduct(t *testing.T) {
+	cases := map[string]struct {
+		product *productv1alpha1.Product
+		want    field.ErrorList
+	}{
+		"Valid": {
+			product: product(nil),
+		},
+		"UsageWithoutBillableMetric": {
+			product: product(func(p *productv1alpha1.ProductParameters) { p.BillableMetricID = "" }),
+			want: field.ErrorList{
+				field.Required(forProvider.Child("billableMetricId"), "a billable metric is required for USAGE products"),
+			},
+		},
+		"UnsupportedType": {
+			product: product(func(p *productv1alpha1.ProductParameters) { p.Type = "USED" }),
+			want: field.ErrorList{
+				field.NotSupported(forProvider.Child("type"), "USED", productTypes),
+			},
+		},
+		"ExplicitWithoutStartingAt": {
+			product: product(func(p *productv1alpha1.ProductParameters) { p.UpdatePolicy = productv1alpha1.UpdatePolicyExplicit }),
+			want: field.ErrorList{
+				field.Required(forProvider.Child("startingAt"), "startingAt is required by the Explicit update policy"),
+			},
+		},
+		"InvalidSchedule": {
+			product: product(func(p *productv1alpha1.ProductParameters) {
+				p.Schedule = []productv1alpha1.ProductScheduleEntry{{
+					StartingAt:         "2025-01-01",
+					QuantityConversion: &productv1alpha1.QuantityConversion{ConversionFactor: 0, Operation: "MULTIPLY"},
+				}}
+			}),
+			want: field.ErrorList{
+				field.Invalid(forProvider.Child("schedule").Index(0).Child("startingAt"), "2025-01-01", errHourTimestamp),
+				field.Invalid(forProvider.Child("schedule").Index(0).Child("quantityConversion", "conversionFactor"), 0.0, "must be positive"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ValidateProduct(tc.product)); diff != "" {
+				t.Errorf("ValidateProduct(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_ValidateBillableMetric(t *testing.T) {
+	cases := map[string]struct {
+		params billablemetricv1alpha1.BillableMetricParameters
+		want   field.ErrorList
+	}{
+		"Valid": {
+			params: billablemetricv1alpha1.BillableMetricParameters{Name: "metric", AggregationType: "sum", AggregationKey: "cpu"},
+		},
+		"ValidSQL": {
+			params: billablemetricv1alpha1.BillableMetricParameters{Name: "metric", SQL: "SELECT 1"},
+		},
+		"MissingAggregationKey": {
+			params: billablemetricv1alpha1.BillableMetricParameters{Name: "metric", AggregationType: "max"},
+			want: field.ErrorList{
+				field.Required(forProvider.Child("aggregationKey"), "an aggregation key is required unless the aggregation type is count"),
+			},
+		},
+		"UnnamedPropertyFilter": {
+			params: billablemetricv1alpha1.BillableMetricParameters{
+				Name:            "metric",
+				AggregationType: "count",
+				PropertyFilters: []billablemetricv1alpha1.PropertyFilter{{}},
+			},
+			want: field.ErrorList{
+				field.Required(forProvider.Child("propertyFilters").Index(0).Child("name"), ""),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &billablemetricv1alpha1.BillableMetric{Spec: billablemetricv1alpha1.BillableMetricSpec{ForProvider: tc.params}}
+			if diff := cmp.Diff(tc.want, ValidateBillableMetric(m)); diff != "" {
+				t.Errorf("ValidateBillableMetric(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_ValidateRateCard(t *testing.T) {
+	cases := map[string]struct {
+		params ratecardv1alpha1.RateCardParameters
+		want   field.ErrorList
+	}{
+		"Valid": {
+			params: ratecardv1alpha1.RateCardParameters{
+				Name:                  "card",
+				CreditTypeConversions: []ratecardv1alpha1.CreditTypeConversion{{CustomCreditTypeID: "credits", FiatPerCustomCredit: "0.5"}},
+			},
+		},
+		"InvalidConversion": {
+			params: ratecardv1alpha1.RateCardParameters{
+				Name:                  "card",
+				CreditTypeConversions: []ratecardv1alpha1.CreditTypeConversion{{CustomCreditTypeID: "credits", FiatPerCustomCredit: "half"}},
+			},
+			want: field.ErrorList{
+				field.Invalid(forProvider.Child("creditTypeConversions").Index(0).Child("fiatPerCustomCredit"), "half", "must be a positive number"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rc := &ratecardv1alpha1.RateCard{Spec: ratecardv1alpha1.RateCardSpec{ForProvider: tc.params}}
+			if diff := cmp.Diff(tc.want, ValidateRateCard(rc)); diff != "" {
+				t.Errorf("ValidateRateCard(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_ValidateCustomFieldKey(t *testing.T) {
+	cases := map[string]struct {
+		params customfieldkeyv1alpha1.CustomFieldKeyParameters
+		want   field.ErrorList
+	}{
+		"Valid": {
+			params: customfieldkeyv1alpha1.CustomFieldKeyParameters{Entity: "customer", Key: "x_account_id"},
+		},
+		"UnsupportedEntity": {
+			params: customfieldkeyv1alpha1.CustomFieldKeyParameters{Entity: "Customer", Key: "x_account_id"},
+			want: field.ErrorList{
+				field.NotSupported(forProvider.Child("entity"), "Customer", customFieldEntities),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			k := &customfieldkeyv1alpha1.CustomFieldKey{Spec: customfieldkeyv1alpha1.CustomFieldKeySpec{ForProvider: tc.params}}
+			if diff := cmp.Diff(tc.want, ValidateCustomFieldKey(k)); diff != "" {
+				t.Errorf("ValidateCustomFieldKey(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
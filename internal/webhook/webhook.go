@@ -0,0 +1,142 @@
+/*
+Copyright 2025 RedbackThomson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook serves the validating admission webhooks of the Metronome
+// resources, which reject specs that Metronome would refuse.
+package webhook
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	billablemetricv1alpha1 "github.com/redbackthomson/provider-metronome/apis/billablemetric/v1alpha1"
+	customfieldkeyv1alpha1 "github.com/redbackthomson/provider-metronome/apis/customfieldkey/v1alpha1"
+	productv1alpha1 "github.com/redbackthomson/provider-metronome/apis/product/v1alpha1"
+	ratev1alpha1 "github.com/redbackthomson/provider-metronome/apis/rate/v1alpha1"
+	ratecardv1alpha1 "github.com/redbackthomson/provider-metronome/apis/ratecard/v1alpha1"
+)
+
+const (
+	errUnexpectedObject = "unexpected object type %T"
+)
+
+// The webhook configurations are generated from these markers into
+// package/webhookconfigurations, and Crossplane points them at the provider.
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-metronome-crossplane-io-v1alpha1-rate,mutating=false,failurePolicy=fail,groups=metronome.crossplane.io,resources=rates,versions=v1alpha1,name=rates.metronome.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-metronome-crossplane-io-v1alpha1-product,mutating=false,failurePolicy=fail,groups=metronome.crossplane.io,resources=products,versions=v1alpha1,name=products.metronome.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-metronome-crossplane-io-v1alpha1-billablemetric,mutating=false,failurePolicy=fail,groups=metronome.crossplane.io,resources=billablemetrics,versions=v1alpha1,name=billablemetrics.metronome.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-metronome-crossplane-io-v1alpha1-ratecard,mutating=false,failurePolicy=fail,groups=metronome.crossplane.io,resources=ratecards,versions=v1alpha1,name=ratecards.metronome.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-metronome-crossplane-io-v1alpha1-customfieldkey,mutating=false,failurePolicy=fail,groups=metronome.crossplane.io,resources=customfieldkeys,versions=v1alpha1,name=customfieldkeys.metronome.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// Setup adds the validating webhooks of the Metronome resources to the
+// manager.
+func Setup(mgr ctrl.Manager) error {
+	setups := []func(ctrl.Manager) error{
+		register(&ratev1alpha1.Rate{}, ratev1alpha1.RateKind, ValidateRate, rateValidated),
+		register(&productv1alpha1.Product{}, productv1alpha1.ProductKind, ValidateProduct,
+			func(p *productv1alpha1.Product) any { return p.Spec }),
+		register(&billablemetricv1alpha1.BillableMetric{}, billablemetricv1alpha1.BillableMetricKind, ValidateBillableMetric,
+			func(m *billablemetricv1alpha1.BillableMetric) any { return m.Spec }),
+		register(&ratecardv1alpha1.RateCard{}, ratecardv1alpha1.RateCardKind, ValidateRateCard,
+			func(rc *ratecardv1alpha1.RateCard) any { return rc.Spec }),
+		register(&customfieldkeyv1alpha1.CustomFieldKey{}, customfieldkeyv1alpha1.CustomFieldKeyKind, ValidateCustomFieldKey,
+			func(k *customfieldkeyv1alpha1.CustomFieldKey) any { return k.Spec }),
+	}
+	for _, setup := range setups {
+		if err := setup(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rateValidated returns the parts of a Rate its rules check, which include
+// annotations as well as the spec.
+func rateValidated(r *ratev1alpha1.Rate) any {
+	a := r.GetAnnotations()
+	return []any{r.Spec, a[ratev1alpha1.AnnotationKeyChangeAt], a[ratev1alpha1.AnnotationKeyEndAt]}
+}
+
+func register[T client.Object](obj T, kind string, validate func(T) field.ErrorList, validated func(T) any) func(ctrl.Manager) error {
+	return func(mgr ctrl.Manager) error {
+		return ctrl.NewWebhookManagedBy(mgr).
+			For(obj).
+			WithValidator(&validator[T]{
+				gk:        schema.GroupKind{Group: ratev1alpha1.Group, Kind: kind},
+				validate:  validate,
+				validated: validated,
+			}).
+			Complete()
+	}
+}
+
+// validator rejects objects of a kind that have any validation errors.
+//
+// Updates that don't change what the rules check aren't validated, so that
+// objects created before a rule existed can still be updated by the provider,
+// e.g. to record status or remove finalizers. Neither are updates to objects
+// being deleted.
+type validator[T client.Object] struct {
+	gk       schema.GroupKind
+	validate func(T) field.ErrorList
+	// validated returns the parts of an object the rules check.
+	validated func(T) any
+}
+
+func (v *validator[T]) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.check(obj)
+}
+
+func (v *validator[T]) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := newObj.(T)
+	if !ok {
+		return nil, errors.Errorf(errUnexpectedObject, newObj)
+	}
+	if meta.WasDeleted(o) {
+		return nil, nil
+	}
+	if old, ok := oldObj.(T); ok && equality.Semantic.DeepEqual(v.validated(old), v.validated(o)) {
+		return nil, nil
+	}
+	return nil, v.check(newObj)
+}
+
+func (v *validator[T]) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *validator[T]) check(obj runtime.Object) error {
+	o, ok := obj.(T)
+	if !ok {
+		return errors.Errorf(errUnexpectedObject, obj)
+	}
+	if errs := v.validate(o); len(errs) > 0 {
+		return kerrors.NewInvalid(v.gk, o.GetName(), errs)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	ratev1alpha1 "github.com/redbackthomson/provider-metronome/apis/rate/v1alpha1"
+)
+
+func Test_Validator_ValidateCreate(t *testing.T) {
+	gk := schema.GroupKind{Group: ratev1alpha1.Group, Kind: ratev1alpha1.RateKind}
+	v := &validator[*ratev1alpha1.Rate]{gk: gk, validate: ValidateRate}
+
+	cases := map[string]struct {
+		rate *ratev1alpha1.Rate
+		want error
+	}{
+		"Valid": {
+			rate: rate(nil, nil),
+		},
+		"Invalid": {
+			rate: rate(nil, func(p *ratev1alpha1.RateParameters) { p.StartingAt = "" }),
+			want: kerrors.NewInvalid(gk, "rate", field.ErrorList{
+				field.Required(forProvider.Child("startingAt"), ""),
+			}),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := v.ValidateCreate(context.Background(), tc.rate)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateCreate(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_Validator_ValidateUpdate(t *testing.T) {
+	gk := schema.GroupKind{Group: ratev1alpha1.Group, Kind: ratev1alpha1.RateKind}
+	v := &validator[*ratev1alpha1.Rate]{gk: gk, validate: ValidateRate, validated: rateValidated}
+	invalid := func(p *ratev1alpha1.RateParameters) { p.StartingAt = "" }
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		old  *ratev1alpha1.Rate
+		new  *ratev1alpha1.Rate
+		want error
+	}{
+		"SpecChangedToInvalid": {
+			old: rate(nil, nil),
+			new: rate(nil, invalid),
+			want: kerrors.NewInvalid(gk, "rate", field.ErrorList{
+				field.Required(forProvider.Child("startingAt"), ""),
+			}),
+		},
+		"InvalidSpecUnchanged": {
+			old: rate(nil, invalid),
+			new: rate(map[string]string{"example.org/other": "value"}, invalid),
+		},
+		"InvalidAnnotationAdded": {
+			old: rate(nil, nil),
+			new: rate(map[string]string{ratev1alpha1.AnnotationKeyChangeAt: "tomorrow"}, nil),
+			want: kerrors.NewInvalid(gk, "rate", ValidateRate(
+				rate(map[string]string{ratev1alpha1.AnnotationKeyChangeAt: "tomorrow"}, nil))),
+		},
+		"Deleting": {
+			old: rate(nil, nil),
+			new: func() *ratev1alpha1.Rate {
+				r := rate(nil, invalid)
+				r.SetDeletionTimestamp(&now)
+				return r
+			}(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := v.ValidateUpdate(context.Background(), tc.old, tc.new)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ValidateUpdate(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}